@@ -0,0 +1,226 @@
+// rlpdump 是一个用来查看rlp编码数据的命令行工具，它从文件或标准输入里读取16进制或二进制形式的rlp编码数据，然后以树状的
+// 文本形式把它们打印出来。在 -reverse 模式下，rlpdump 会反过来把这种文本形式重新组装成rlp编码数据。用法如下：
+//
+//	rlpdump -hex c88363617483646f67
+//	rlpdump data.rlp
+//	rlpdump data.rlp | rlpdump -reverse
+//	rlpdump -json data.rlp | rlpdump -json -reverse
+//	rlpdump -schema messages.schema -type "[]Tx" data.rlp
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+)
+
+func main() {
+	var (
+		hexFlag     = flag.String("hex", "", "直接从命令行参数读取16进制形式的输入")
+		binFlag     = flag.Bool("bin", false, "将输入视为二进制数据；在 -reverse 模式下则输出二进制数据")
+		offsetsFlag = flag.Bool("offsets", false, "在注释里标出每个元素在输入数据里的偏移量")
+		indentFlag  = flag.String("indent", "  ", "每一层嵌套列表使用的缩进")
+		reverseFlag = flag.Bool("reverse", false, "将文本形式重新组装成rlp编码数据")
+		jsonFlag    = flag.Bool("json", false, "以JSON形式输出；在 -reverse 模式下则读取JSON形式的输入")
+		schemaFlag  = flag.String("schema", "", "在输出之前，按照该文件里的结构体定义检查输入")
+		typeFlag    = flag.String("type", "", "与 -schema 一起使用，输入应当满足的类型表达式，例如\"[]Tx\"")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[-hex <data>] [-bin] [-offsets] [-json] [-reverse] [-schema <file> -type <expr>] [<file>]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var input []byte
+	switch {
+	case *hexFlag != "":
+		input = []byte(*hexFlag)
+	case flag.NArg() == 0:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fatal(err)
+		}
+		input = data
+	case flag.NArg() == 1:
+		data, err := os.ReadFile(flag.Arg(0))
+		if err != nil {
+			fatal(err)
+		}
+		input = data
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *reverseFlag {
+		var (
+			bz  []byte
+			err error
+		)
+		if *jsonFlag {
+			bz, err = rlp.JSONToRLP(input)
+		} else {
+			bz, err = assemble(string(input))
+		}
+		if err != nil {
+			fatal(err)
+		}
+		if *binFlag {
+			os.Stdout.Write(bz)
+		} else {
+			fmt.Printf("%x\n", bz)
+		}
+		return
+	}
+
+	if !*binFlag || *hexFlag != "" {
+		bz, err := decodeHex(input)
+		if err != nil {
+			fatal(err)
+		}
+		input = bz
+	}
+	if *schemaFlag != "" {
+		defs, err := os.ReadFile(*schemaFlag)
+		if err != nil {
+			fatal(err)
+		}
+		schema, err := rlp.ParseSchema(string(defs), *typeFlag)
+		if err != nil {
+			fatal(err)
+		}
+		if err = schema.Validate(input); err != nil {
+			fatal(err)
+		}
+	}
+	if *jsonFlag {
+		js, err := rlp.RLPToJSON(input)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Printf("%s\n", js)
+		return
+	}
+	if err := rlp.Dump(os.Stdout, input, rlp.DumpOptions{Indent: *indentFlag, ShowOffsets: *offsetsFlag}); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, append([]interface{}{"rlpdump:"}, args...)...)
+	os.Exit(1)
+}
+
+// decodeHex ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeHex 方法解析16进制形式的输入，输入可以带有"0x"前缀，其中的空白字符会被忽略。
+func decodeHex(input []byte) ([]byte, error) {
+	s := strings.Join(strings.Fields(string(input)), "")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex input (use -bin for binary input): %v", err)
+	}
+	return bz, nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// assemble ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// assemble 方法将 rlp.Dump 输出的文本形式重新组装成rlp编码数据，它能识别以下几种记号：
+//   - "[" 和 "]"：列表的开始和结束；
+//   - "0x" 开头的16进制串：字节串，单独的"0x"表示空字节串；
+//   - 双引号包裹的Go字符串字面量：字符串，例如"cat"；
+//   - 十进制数字：非负整数，按照大整数的规则进行编码；
+//   - "#" 到行尾之间的内容是注释，会被忽略，逗号与空白字符一样被视为分隔符。
+//
+// 组装得到的总是规范的编码，所以 rlp.Dump 标记为"non-canonical"的数据在经过一轮转换后会被修正为规范的形式。
+func assemble(text string) ([]byte, error) {
+	var out bytes.Buffer
+	w := rlp.NewEncodeBuffer(&out)
+	var lists []int
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+			i++
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '[':
+			lists = append(lists, w.ListStart())
+			i++
+		case c == ']':
+			if len(lists) == 0 {
+				return nil, syntaxError(text, i, "unexpected ']'")
+			}
+			w.ListEnd(lists[len(lists)-1])
+			lists = lists[:len(lists)-1]
+			i++
+		case strings.HasPrefix(text[i:], "0x") || strings.HasPrefix(text[i:], "0X"):
+			j := i + 2
+			for j < len(text) && isHexDigit(text[j]) {
+				j++
+			}
+			bz, err := hex.DecodeString(text[i+2 : j])
+			if err != nil {
+				return nil, syntaxError(text, i, fmt.Sprintf("invalid hex string %q", text[i:j]))
+			}
+			w.WriteBytes(bz)
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(text) && text[j] != '"' && text[j] != '\n' {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(text) || text[j] != '"' {
+				return nil, syntaxError(text, i, "unterminated string")
+			}
+			s, err := strconv.Unquote(text[i : j+1])
+			if err != nil {
+				return nil, syntaxError(text, i, fmt.Sprintf("invalid string %s", text[i:j+1]))
+			}
+			w.WriteString(s)
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+				j++
+			}
+			n, _ := new(big.Int).SetString(text[i:j], 10)
+			w.WriteBigInt(n)
+			i = j
+		default:
+			return nil, syntaxError(text, i, fmt.Sprintf("unexpected character %q", c))
+		}
+	}
+	if len(lists) > 0 {
+		return nil, syntaxError(text, len(text), "unterminated list")
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// syntaxError 方法生成一个带有行号的语法错误，pos 是出错的位置在 text 里的索引。
+func syntaxError(text string, pos int, msg string) error {
+	return fmt.Errorf("line %d: %s", strings.Count(text[:pos], "\n")+1, msg)
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
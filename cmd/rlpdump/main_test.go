@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		text   string
+		output string
+		err    string
+	}{
+		{text: "", output: ""},
+		{text: "0x", output: "80"},
+		{text: "0x05 0x0400", output: "05820400"},
+		{text: "\"cat\", \"dog\"", output: "8363617483646f67"},
+		{text: "[ 0 1 1024 ]", output: "c5800182" + "0400"},
+		{text: "[\n  [] # 空列表\n  [[]]\n]", output: "c3c0c1c0"},
+		{text: "18446744073709551616", output: "89010000000000000000"},
+		{text: "\"a\\\"b\"", output: "83612262"},
+		{text: "[\n  0x01\n]\n]", err: "line 4: unexpected ']'"},
+		{text: "[\n  0x01\n", err: "line 3: unterminated list"},
+		{text: "0x123", err: "line 1: invalid hex string \"0x123\""},
+		{text: "\"abc", err: "line 1: unterminated string"},
+		{text: "\n  foo", err: "line 2: unexpected character 'f'"},
+	}
+	for i, test := range tests {
+		bz, err := assemble(test.text)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, "test %d", i)
+			continue
+		}
+		assert.Nil(t, err, "test %d", i)
+		assert.Equal(t, test.output, hex.EncodeToString(bz), "test %d", i)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	inputs := []string{
+		"c88363617483646f67",
+		"c9" + "83636174" + "c4" + "01" + "820400",
+		"80c005",
+		"b838" + "6161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161616161",
+		"c7c0c1c0c3c0c1c0",
+	}
+	for i, input := range inputs {
+		var buf bytes.Buffer
+		bz, _ := hex.DecodeString(input)
+		err := rlp.Dump(&buf, bz, rlp.DumpOptions{ShowOffsets: true})
+		assert.Nil(t, err, "input %d", i)
+		out, err := assemble(buf.String())
+		assert.Nil(t, err, "input %d", i)
+		assert.Equal(t, input, hex.EncodeToString(out), "input %d:\n%s", i, buf.String())
+	}
+
+	// 非规范的编码在经过一轮转换后会变成规范的编码
+	var buf bytes.Buffer
+	bz, _ := hex.DecodeString("c58105b801aa")
+	assert.Nil(t, rlp.Dump(&buf, bz, rlp.DumpOptions{}))
+	out, err := assemble(buf.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "c30581aa", hex.EncodeToString(out))
+}
+
+func TestDecodeHex(t *testing.T) {
+	bz, err := decodeHex([]byte("0xC8 8363\n6174 83646f67\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "c88363617483646f67", hex.EncodeToString(bz))
+	_, err = decodeHex([]byte("zz"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,61 @@
+package sha3
+
+import "math/bits"
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// roundConstants ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// roundConstants 是 Keccak-f[1600] 置换在24轮的 ι 步骤里分别使用的轮常量。
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations 和 piLanes 共同描述了 ρ 和 π 两个步骤：沿着 π 置换的轨迹依次访问除(0,0)以外的24个lane，第i个被访问的
+// lane是 piLanes[i]，它要循环左移 rotations[i] 位。
+var (
+	rotations = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	piLanes   = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// keccakF1600 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// keccakF1600 方法对1600比特的状态a执行24轮 Keccak-f 置换，状态由25个64位的lane组成，a[x+5y]表示坐标为(x,y)的lane。
+// 每一轮依次执行 θ、ρ、π、χ、ι 五个步骤，这里采用的是最直接的通用实现，没有针对特定平台做优化。
+func keccakF1600(a *[25]uint64) {
+	var bc [5]uint64
+	for round := 0; round < 24; round++ {
+		// θ：每个lane都要异或上相邻两列的奇偶校验值
+		for i := 0; i < 5; i++ {
+			bc[i] = a[i] ^ a[i+5] ^ a[i+10] ^ a[i+15] ^ a[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			t := bc[(i+4)%5] ^ bits.RotateLeft64(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				a[j+i] ^= t
+			}
+		}
+		// ρ 和 π：循环移位并重新排列lane的位置
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := piLanes[i]
+			t, a[j] = a[j], bits.RotateLeft64(t, rotations[i])
+		}
+		// χ：唯一的非线性步骤，逐行进行
+		for j := 0; j < 25; j += 5 {
+			for i := 0; i < 5; i++ {
+				bc[i] = a[j+i]
+			}
+			for i := 0; i < 5; i++ {
+				a[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+		// ι：打破对称性
+		a[0] ^= roundConstants[round]
+	}
+}
@@ -0,0 +1,110 @@
+// Package sha3 实现了以太坊使用的 Keccak-256 哈希函数。以太坊在 SHA-3 标准（FIPS-202）定稿之前就采用了 Keccak，
+// 两者唯一的区别在于填充方式：Keccak 的域分隔字节是0x01，而 SHA-3 是0x06，因此以太坊里的哈希值与标准的 SHA3-256
+// 并不相同，这里的实现被称为"legacy"Keccak。
+package sha3
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	// rate256 Keccak-256 每次吸收的字节数，等于(1600-2*256)/8
+	rate256 = 136
+	// dsbyteKeccak legacy Keccak 使用的域分隔字节
+	dsbyteKeccak = 0x01
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// state ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// state 是海绵结构的状态，它实现了 hash.Hash 接口。写入的数据先被缓存在buf里，每凑满rate个字节就异或进a，然后执行
+// 一次 keccakF1600 置换。
+type state struct {
+	a         [25]uint64
+	buf       [rate256]byte
+	n         int  // buf里已经缓存的字节数
+	rate      int  // 每次吸收的字节数
+	outputLen int  // 输出的哈希值长度
+	dsbyte    byte // 域分隔字节
+}
+
+// NewLegacyKeccak256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewLegacyKeccak256 方法返回一个计算 Keccak-256 哈希值的 hash.Hash，以太坊里的交易哈希、区块哈希以及默克尔前缀树
+// 的节点哈希都是用它计算的。
+func NewLegacyKeccak256() hash.Hash {
+	return &state{rate: rate256, outputLen: 32, dsbyte: dsbyteKeccak}
+}
+
+// Keccak256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Keccak256 方法计算所有输入数据拼接在一起之后的 Keccak-256 哈希值，例如空输入的哈希值为：
+//
+//	c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470
+func Keccak256(data ...[]byte) []byte {
+	d := NewLegacyKeccak256()
+	for _, bz := range data {
+		d.Write(bz)
+	}
+	return d.Sum(nil)
+}
+
+// BlockSize 方法返回海绵结构每次吸收的字节数。
+func (d *state) BlockSize() int { return d.rate }
+
+// Size 方法返回哈希值的字节长度。
+func (d *state) Size() int { return d.outputLen }
+
+// Reset 方法清空状态，使其可以重新计算新的哈希值。
+func (d *state) Reset() {
+	d.a = [25]uint64{}
+	d.n = 0
+}
+
+// Write ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Write 方法实现了 io.Writer 接口，它把数据吸收进海绵结构里，该方法永远不会返回错误。
+func (d *state) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		m := copy(d.buf[d.n:d.rate], p)
+		d.n += m
+		p = p[m:]
+		if d.n == d.rate {
+			d.absorb()
+		}
+	}
+	return written, nil
+}
+
+// Sum ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Sum 方法把当前的哈希值追加到b后面并返回，它在状态的副本上完成填充和挤压，所以不会影响后续的写入。
+func (d *state) Sum(b []byte) []byte {
+	dup := *d
+	// 填充：在数据后面追加域分隔字节，并把最后一个字节的最高位置为1，两者可能落在同一个字节上
+	for i := dup.n; i < dup.rate; i++ {
+		dup.buf[i] = 0
+	}
+	dup.buf[dup.n] ^= dup.dsbyte
+	dup.buf[dup.rate-1] ^= 0x80
+	dup.n = dup.rate
+	dup.absorb()
+
+	var out [200]byte
+	for i := 0; i < dup.outputLen/8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], dup.a[i])
+	}
+	return append(b, out[:dup.outputLen]...)
+}
+
+// absorb 方法把buf里缓存的rate个字节以小端序异或进状态里，然后执行一次置换。
+func (d *state) absorb() {
+	for i := 0; i < d.rate/8; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(d.buf[i*8:])
+	}
+	keccakF1600(&d.a)
+	d.n = 0
+}
@@ -0,0 +1,151 @@
+package sha3
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		// 空字符串和空列表的rlp编码，它们的哈希值分别是空前缀树的根哈希和空叔块列表的哈希
+		{"\x80", "56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"},
+		{"\xc0", "1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, hex.EncodeToString(Keccak256([]byte(test.input))), "input %q", test.input)
+	}
+}
+
+func TestKeccak256Streaming(t *testing.T) {
+	// 跨越多个块的输入，无论怎样分段写入，得到的哈希值都应当一样
+	data := bytes.Repeat([]byte("ethereum"), 100)
+	want := Keccak256(data)
+	for _, step := range []int{1, 7, rate256 - 1, rate256, rate256 + 1, len(data)} {
+		d := NewLegacyKeccak256()
+		for i := 0; i < len(data); i += step {
+			end := i + step
+			if end > len(data) {
+				end = len(data)
+			}
+			d.Write(data[i:end])
+		}
+		assert.Equal(t, want, d.Sum(nil), "step %d", step)
+		// Sum 不能改变状态
+		assert.Equal(t, want, d.Sum(nil), "step %d", step)
+	}
+
+	d := NewLegacyKeccak256()
+	d.Write([]byte("abc"))
+	d.Reset()
+	assert.Equal(t, Keccak256(), d.Sum(nil))
+	assert.Equal(t, 32, d.Size())
+	assert.Equal(t, rate256, d.BlockSize())
+}
+
+// katVector 是 testdata/keccak256_kat.txt 里的一条已知答案测试向量。
+type katVector struct {
+	input  []byte
+	keccak string
+	sha3   string
+}
+
+// loadKAT 读取已知答案测试向量，输入的第i个字节为byte(i)。
+func loadKAT(t *testing.T) []katVector {
+	f, err := os.Open("testdata/keccak256_kat.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var vectors []katVector
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			t.Fatalf("malformed vector: %q", line)
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := make([]byte, n)
+		for i := range input {
+			input[i] = byte(i)
+		}
+		vectors = append(vectors, katVector{input: input, keccak: fields[1], sha3: fields[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestKeccak256KAT(t *testing.T) {
+	vectors := loadKAT(t)
+	lengths := make(map[int]bool)
+	for _, v := range vectors {
+		lengths[len(v.input)] = true
+		assert.Equal(t, v.keccak, hex.EncodeToString(Keccak256(v.input)), "length %d", len(v.input))
+	}
+	// 0到200字节的每个长度都要覆盖，它们包含了填充字节与最后一个字节重合（135字节）以及恰好填满一个块（136字节）的情况
+	for n := 0; n <= 200; n++ {
+		assert.True(t, lengths[n], "missing vector for length %d", n)
+	}
+	for _, n := range []int{2*rate256 - 1, 2 * rate256, 2*rate256 + 1, 3 * rate256, 8 * rate256} {
+		assert.True(t, lengths[n], "missing vector for length %d", n)
+	}
+}
+
+func TestSHA3KAT(t *testing.T) {
+	// SHA3-256 与 Keccak-256 只有域分隔字节不同，用标准的 SHA3-256 向量可以独立地检验置换函数和海绵结构
+	newSHA3 := func() *state { return &state{rate: rate256, outputLen: 32, dsbyte: 0x06} }
+	d := newSHA3()
+	d.Write([]byte("abc"))
+	assert.Equal(t, "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532", hex.EncodeToString(d.Sum(nil)))
+	for _, v := range loadKAT(t) {
+		d := newSHA3()
+		d.Write(v.input)
+		assert.Equal(t, v.sha3, hex.EncodeToString(d.Sum(nil)), "length %d", len(v.input))
+	}
+}
+
+func TestKeccak256SplitWrites(t *testing.T) {
+	// 把输入从任意位置切成两段写入，或者逐字节写入，得到的哈希值都应当与一次性写入相同
+	for _, v := range loadKAT(t) {
+		if len(v.input) > 3*rate256 {
+			continue
+		}
+		for split := 0; split <= len(v.input); split++ {
+			d := NewLegacyKeccak256()
+			d.Write(v.input[:split])
+			d.Write(v.input[split:])
+			if !assert.Equal(t, v.keccak, hex.EncodeToString(d.Sum(nil)), "length %d, split at %d", len(v.input), split) {
+				break
+			}
+		}
+		d := NewLegacyKeccak256()
+		for i := range v.input {
+			d.Write(v.input[i : i+1])
+			// 中途调用 Sum 不能影响后续的写入
+			if i == rate256 {
+				d.Sum(nil)
+			}
+		}
+		assert.Equal(t, v.keccak, hex.EncodeToString(d.Sum(nil)), "length %d, byte by byte", len(v.input))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -174,6 +175,83 @@ func FilterHandler(fn func(r *Record) bool, h Handler) Handler {
 	})
 }
 
+// MatchFilterHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MatchFilterHandler 方法只输出键key对应的值等于value的日志，key除了可以是 Ctx 里的键之外，还可以是
+// RecordKeyNames 里的日志等级、日志消息和时间的键名，例如只输出p2p模块的日志：
+//
+//	MatchFilterHandler("module", "p2p", h)
+func MatchFilterHandler(key string, value interface{}, h Handler) Handler {
+	return MatchAnyFilterHandler(key, []interface{}{value}, h)
+}
+
+// MatchAnyFilterHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MatchAnyFilterHandler 方法与 MatchFilterHandler 方法类似，不同的是键key对应的值只要等于values中的任意
+// 一个，就会被输出，例如输出p2p和eth两个模块的日志：
+//
+//	MatchAnyFilterHandler("module", []interface{}{"p2p", "eth"}, h)
+func MatchAnyFilterHandler(key string, values []interface{}, h Handler) Handler {
+	return FilterHandler(func(r *Record) (pass bool) {
+		v, ok := lookupKey(r, key)
+		if !ok {
+			return false
+		}
+		for _, value := range values {
+			if matchValue(v, value) {
+				return true
+			}
+		}
+		return false
+	}, h)
+}
+
+// MultiHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MultiHandler 方法将同一条日志记录依次交给所有的 Handler，例如同时输出到控制台和文件。某个 Handler 出错
+// 不会影响后面的 Handler，所有的错误会被合并成一个错误返回，错误信息里带有出错的 Handler 的下标：
+//
+//	MultiHandler(
+//	    StreamHandler(os.Stderr, TerminalFormat(true)),
+//	    LvlFilterHandler(LvlError, fileHandler),
+//	)
+func MultiHandler(hs ...Handler) Handler {
+	return FuncHandler(func(r *Record) error {
+		var errs multiError
+		for i, h := range hs {
+			if err := h.Log(r); err != nil {
+				errs = append(errs, fmt.Errorf("handler %d: %w", i, err))
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	})
+}
+
+// FailoverHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// FailoverHandler 方法按顺序尝试给定的 Handler，只要有一个 Handler 成功输出了日志记录就停止，例如在网络连接
+// 断开时改为写入文件，文件也写不进去时再输出到控制台：
+//
+//	FailoverHandler(netHandler, fileHandler, StdoutHandler)
+//
+// 前面的 Handler 失败的原因会以"failover_err_{i}"为键追加到日志记录的 Ctx 里，交给后面的 Handler 一并输出。
+// 所有的 Handler 都失败时，返回最后一个 Handler 的错误。
+func FailoverHandler(hs ...Handler) Handler {
+	return FuncHandler(func(r *Record) error {
+		var err error
+		for i, h := range hs {
+			if err = h.Log(r); err == nil {
+				return nil
+			}
+			r.Ctx = append(r.Ctx, fmt.Sprintf("failover_err_%d", i), err)
+		}
+		return err
+	})
+}
+
 // DiscardHandler ♏ |作者：吴翔宇| 🍁 |日期：2022/11/21|
 //
 // DiscardHandler 方法用于禁用日志功能。
@@ -217,6 +295,61 @@ func evaluateLazy(lz Lazy) (interface{}, error) {
 	return values, nil
 }
 
+// lookupKey ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// lookupKey 方法在日志记录里查找键key对应的值，先检查日志等级、日志消息和时间的键名，再按照 normalize 方法整理
+// 之后的 Ctx 查找，Ctx 里有多个相同的键时，返回第一个键对应的值。
+func lookupKey(r *Record, key string) (interface{}, bool) {
+	switch key {
+	case r.KeyNames.Lvl:
+		return r.Lvl, true
+	case r.KeyNames.Msg:
+		return r.Msg, true
+	case r.KeyNames.Time:
+		return r.Time, true
+	}
+	ctx := normalize(r.Ctx)
+	for i := 0; i < len(ctx); i += 2 {
+		if k, ok := ctx[i].(string); ok && k == key {
+			return ctx[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// matchValue 方法判断两个值是否相等，不可比较的值（例如切片）用 reflect.DeepEqual 比较，避免"=="引发panic。
+func matchValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) {
+		return false
+	}
+	if ta.Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// multiError ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// multiError 是 MultiHandler 返回的错误，它包含了所有出错的 Handler 返回的错误。
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap 方法使得 errors.Is 和 errors.As 能够检查其中的每一个错误。
+func (e multiError) Unwrap() []error {
+	return e
+}
+
 // swapHandler ♏ |作者：吴翔宇| 🍁 |日期：2022/11/21|
 //
 // swapHandler 可以在多线程情况下安全的切换 Handler。
@@ -0,0 +1,251 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 BufferedHandler
+
+// errHandlerClosed 在 BufferedHandler 被关闭之后继续输出日志时返回。
+var errHandlerClosed = errors.New("log handler closed")
+
+const (
+	dropReportEvery    = 1000            // 队列一直没有被清空时，每输出这么多条日志记录检查一次丢弃数量
+	dropReportInterval = 5 * time.Second // 队列一直没有被清空时，每隔这么长时间检查一次丢弃数量
+)
+
+// OverflowPolicy ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// OverflowPolicy 决定了 BufferedHandler 的队列满了之后如何处理新的日志记录。
+type OverflowPolicy int
+
+const (
+	BlockWhenFull OverflowPolicy = iota // 阻塞调用者，直到队列有空位，不会丢失日志
+	DropNewest                          // 丢弃新的日志记录，调用者不会被阻塞
+	DropOldest                          // 丢弃队列里最旧的日志记录，为新的日志记录腾出空位
+)
+
+// String 方法返回策略的名字。
+func (p OverflowPolicy) String() string {
+	switch p {
+	case BlockWhenFull:
+		return "block"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// queuedRecord 是队列里的一条日志记录，seq是它入队的序号，从1开始递增。
+type queuedRecord struct {
+	r   *Record
+	seq uint64
+}
+
+// BufferedHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// BufferedHandler 将日志记录放入一个带缓冲的通道，然后由后台的goroutine按照先进先出的顺序交给真正输出日志
+// 的 Handler，这样 Log 方法不会因为磁盘或者网络连接太慢而阻塞调用者。队列满了之后的行为由 OverflowPolicy
+// 决定，被丢弃的日志记录会被计数，后台goroutine在队列清空时输出一条警告日志，说明丢弃了多少条日志记录。持续过载
+// 时队列可能一直不会被清空，所以每输出 dropReportEvery 条日志记录或者每隔 dropReportInterval 也会检查一次。
+type BufferedHandler struct {
+	h      Handler
+	policy OverflowPolicy
+	queue  chan queuedRecord
+
+	sendLock sync.Mutex // 保证入队的顺序与序号的顺序一致，同时保护seq和closed
+	seq      uint64     // 最后一条入队的日志记录的序号
+	closed   bool
+
+	doneLock sync.Mutex
+	doneCond *sync.Cond
+	done     uint64 // 序号不超过done的日志记录都已经被输出或者丢弃
+
+	dropped  uint64 // 被丢弃的日志记录总数，原子操作
+	reported uint64 // 已经通过警告日志报告过的丢弃数量，只有后台goroutine访问
+	exited   chan struct{}
+
+	reportEvery    int           // 每输出多少条日志记录检查一次丢弃数量
+	reportInterval time.Duration // 每隔多长时间检查一次丢弃数量
+	sinceCheck     int           // 上次检查之后输出的日志记录数量，只有后台goroutine访问
+	lastCheck      time.Time     // 上次检查的时间，只有后台goroutine访问
+}
+
+// AsyncHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// AsyncHandler 方法将给定的 Handler 包装成一个异步的 BufferedHandler，队列最多容纳bufSize条日志记录，
+// 队列满了之后按照policy处理新的日志记录，例如：
+//
+//	async := AsyncHandler(StreamHandler(conn, JSONFormat()), 1024, DropOldest)
+//	defer async.Close()
+//	Root().SetHandler(async)
+//
+// 由于日志记录是在后台输出的，h.Log 返回的错误无法被调用者得知，Log 方法只在 BufferedHandler 已经关闭时返回错误。
+func AsyncHandler(h Handler, bufSize int, policy OverflowPolicy) *BufferedHandler {
+	return newBufferedHandler(h, bufSize, policy, dropReportEvery, dropReportInterval)
+}
+
+// newBufferedHandler 方法创建一个 BufferedHandler，并启动后台goroutine，reportEvery 和 reportInterval 决定了队列
+// 一直没有被清空时多久检查一次丢弃数量。
+func newBufferedHandler(h Handler, bufSize int, policy OverflowPolicy, reportEvery int, reportInterval time.Duration) *BufferedHandler {
+	if bufSize < 1 {
+		bufSize = 1
+	}
+	bh := &BufferedHandler{
+		h:              h,
+		policy:         policy,
+		queue:          make(chan queuedRecord, bufSize),
+		exited:         make(chan struct{}),
+		reportEvery:    reportEvery,
+		reportInterval: reportInterval,
+		lastCheck:      time.Now(),
+	}
+	bh.doneCond = sync.NewCond(&bh.doneLock)
+	go bh.loop()
+	return bh
+}
+
+// Log ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Log 方法实现了 Handler 接口，它将日志记录放入队列之后立即返回，队列满了之后按照 OverflowPolicy 处理。
+func (bh *BufferedHandler) Log(r *Record) error {
+	bh.sendLock.Lock()
+	defer bh.sendLock.Unlock()
+
+	if bh.closed {
+		return errHandlerClosed
+	}
+	item := queuedRecord{r: r, seq: bh.seq + 1}
+	switch bh.policy {
+	case DropNewest:
+		select {
+		case bh.queue <- item:
+		default:
+			atomic.AddUint64(&bh.dropped, 1)
+			return nil
+		}
+	case DropOldest:
+		for sent := false; !sent; {
+			select {
+			case bh.queue <- item:
+				sent = true
+			default:
+				bh.dropOldest()
+			}
+		}
+	default:
+		bh.queue <- item
+	}
+	bh.seq = item.seq
+	return nil
+}
+
+// Flush ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Flush 方法等待调用之前已经入队的日志记录全部被输出或者丢弃，之后才入队的日志记录不在等待之列。
+func (bh *BufferedHandler) Flush() {
+	bh.sendLock.Lock()
+	target := bh.seq
+	bh.sendLock.Unlock()
+
+	bh.doneLock.Lock()
+	defer bh.doneLock.Unlock()
+
+	for bh.done < target {
+		bh.doneCond.Wait()
+	}
+}
+
+// Close ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Close 方法停止接收新的日志记录，等待队列里的日志记录全部被输出，然后结束后台goroutine。如果给定的 Handler
+// 实现了Close方法，也会将其关闭。重复调用 Close 方法不会产生任何影响。
+func (bh *BufferedHandler) Close() error {
+	bh.sendLock.Lock()
+	if bh.closed {
+		bh.sendLock.Unlock()
+		<-bh.exited
+		return nil
+	}
+	bh.closed = true
+	close(bh.queue)
+	bh.sendLock.Unlock()
+
+	<-bh.exited
+	if c, ok := bh.h.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Dropped 方法返回到目前为止被丢弃的日志记录总数。
+func (bh *BufferedHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&bh.dropped)
+}
+
+// loop ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// loop 方法运行在后台goroutine里，它依次取出队列里的日志记录并输出，每当队列被清空、输出了 reportEvery 条日志
+// 记录或者距离上次检查超过了 reportInterval 时，检查是否有新的日志记录被丢弃，如果有就输出一条警告日志。队列被
+// 关闭并且清空之后，loop 方法退出。
+func (bh *BufferedHandler) loop() {
+	defer close(bh.exited)
+
+	for item := range bh.queue {
+		_ = bh.h.Log(item.r)
+		bh.sinceCheck++
+		if len(bh.queue) == 0 || bh.sinceCheck >= bh.reportEvery || time.Since(bh.lastCheck) >= bh.reportInterval {
+			bh.reportDropped()
+		}
+		bh.doneLock.Lock()
+		bh.done = item.seq
+		bh.doneCond.Broadcast()
+		bh.doneLock.Unlock()
+	}
+	bh.reportDropped()
+}
+
+// dropOldest ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// dropOldest 方法丢弃队列里最旧的一条日志记录，调用者必须持有sendLock。通道保证了先进先出的顺序，所以被丢弃的
+// 日志记录一定比后台goroutine正在输出的日志记录更新。被丢弃的日志记录不会更新done，因为随后入队的日志记录的序号
+// 更大，它被后台goroutine输出时，Flush 方法自然不再需要等待被丢弃的日志记录。
+func (bh *BufferedHandler) dropOldest() {
+	select {
+	case <-bh.queue:
+		atomic.AddUint64(&bh.dropped, 1)
+	default:
+		// 后台goroutine刚刚取走了一条日志记录，队列已经有空位了
+	}
+}
+
+// reportDropped 方法在有新的日志记录被丢弃时，输出一条警告日志。
+func (bh *BufferedHandler) reportDropped() {
+	bh.sinceCheck, bh.lastCheck = 0, time.Now()
+	total := atomic.LoadUint64(&bh.dropped)
+	if total == bh.reported {
+		return
+	}
+	_ = bh.h.Log(&Record{
+		Time: time.Now(),
+		Lvl:  LvlWarn,
+		Msg:  "Dropped log records",
+		Ctx:  []interface{}{"dropped", total - bh.reported, "total", total, "policy", bh.policy.String()},
+		KeyNames: RecordKeyNames{
+			Time: timeKey,
+			Msg:  msgKey,
+			Lvl:  lvlKey,
+			Ctx:  ctxKey,
+		},
+	})
+	bh.reported = total
+}
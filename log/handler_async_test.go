@@ -0,0 +1,168 @@
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedHandler 在gate被关闭之前阻塞所有的 Log 调用，started用来通知第一条日志记录已经被后台goroutine取出。
+type gatedHandler struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	msgs    []string
+	closed  bool
+}
+
+func newGatedHandler() *gatedHandler {
+	return &gatedHandler{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (h *gatedHandler) Log(r *Record) error {
+	h.once.Do(func() { close(h.started) })
+	<-h.gate
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.msgs = append(h.msgs, r.Msg)
+	return nil
+}
+
+func (h *gatedHandler) Close() error {
+	h.closed = true
+	return nil
+}
+
+func (h *gatedHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.msgs...)
+}
+
+// fillAsync 输出msgs，第一条日志记录被后台goroutine取出之后才输出剩下的日志记录，因此队列里恰好是剩下的日志记录。
+func fillAsync(h *BufferedHandler, gh *gatedHandler, msgs ...string) {
+	h.Log(&Record{Msg: msgs[0]})
+	<-gh.started
+	for _, msg := range msgs[1:] {
+		h.Log(&Record{Msg: msg})
+	}
+}
+
+func TestAsyncHandlerDropNewest(t *testing.T) {
+	gh := newGatedHandler()
+	h := AsyncHandler(gh, 2, DropNewest)
+	fillAsync(h, gh, "a", "b", "c", "d", "e")
+	assert.Equal(t, uint64(2), h.Dropped())
+	close(gh.gate)
+	h.Flush()
+	assert.Equal(t, []string{"a", "b", "c", "Dropped log records"}, gh.messages())
+	assert.Nil(t, h.Close())
+	assert.True(t, gh.closed)
+}
+
+func TestAsyncHandlerDropOldest(t *testing.T) {
+	gh := newGatedHandler()
+	h := AsyncHandler(gh, 2, DropOldest)
+	fillAsync(h, gh, "a", "b", "c", "d", "e")
+	assert.Equal(t, uint64(2), h.Dropped())
+	close(gh.gate)
+	h.Flush()
+	assert.Equal(t, []string{"a", "d", "e", "Dropped log records"}, gh.messages())
+
+	// 丢弃数量只报告一次
+	h.Log(&Record{Msg: "f"})
+	assert.Nil(t, h.Close())
+	assert.Equal(t, []string{"a", "d", "e", "Dropped log records", "f"}, gh.messages())
+	assert.Equal(t, errHandlerClosed, h.Log(&Record{Msg: "g"}))
+	assert.Nil(t, h.Close())
+}
+
+func TestAsyncHandlerSaturated(t *testing.T) {
+	var (
+		h    *BufferedHandler
+		msgs []string
+		n    int
+	)
+	// 每输出一条日志记录就再输出3条，队列只能容纳2条，所以队列一直是满的，并且不断有日志记录被丢弃
+	h = newBufferedHandler(FuncHandler(func(r *Record) error {
+		msgs = append(msgs, r.Msg)
+		if r.Msg == "msg" && n < 20 {
+			n++
+			for i := 0; i < 3; i++ {
+				h.Log(&Record{Msg: "msg"})
+			}
+		}
+		return nil
+	}), 2, DropNewest, 5, time.Hour)
+	h.Log(&Record{Msg: "msg"})
+	h.Flush()
+	assert.Nil(t, h.Close())
+
+	// 队列清空之前，每输出5条日志记录就报告一次丢弃数量
+	var reports []int
+	for i, msg := range msgs {
+		if msg == "Dropped log records" {
+			reports = append(reports, i)
+		}
+	}
+	assert.Equal(t, []int{5, 11, 17, 23}, reports)
+	// 第一次输出时队列是空的，只丢弃了1条，之后每次丢弃2条
+	assert.Equal(t, uint64(39), h.Dropped())
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	gh := newGatedHandler()
+	h := AsyncHandler(gh, 2, BlockWhenFull)
+	fillAsync(h, gh, "a", "b", "c")
+
+	// 队列已满，下一条日志记录会阻塞调用者，直到后台goroutine腾出空位
+	logged := make(chan struct{})
+	go func() {
+		h.Log(&Record{Msg: "d"})
+		close(logged)
+	}()
+	select {
+	case <-logged:
+		t.Fatal("Log should block when queue is full")
+	default:
+	}
+	close(gh.gate)
+	<-logged
+	assert.Nil(t, h.Close())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, gh.messages())
+	assert.Equal(t, uint64(0), h.Dropped())
+}
+
+func TestAsyncHandlerConcurrent(t *testing.T) {
+	for _, policy := range []OverflowPolicy{BlockWhenFull, DropNewest, DropOldest} {
+		var mu sync.Mutex
+		var count int
+		h := AsyncHandler(FuncHandler(func(r *Record) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Msg == "msg" {
+				count++
+			}
+			return nil
+		}), 4, policy)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					h.Log(&Record{Msg: "msg"})
+				}
+			}()
+		}
+		wg.Wait()
+		h.Flush()
+		mu.Lock()
+		assert.Equal(t, 1600, count+int(h.Dropped()), policy.String())
+		mu.Unlock()
+		assert.Nil(t, h.Close())
+	}
+}
@@ -0,0 +1,238 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义包级全局变量
+
+// errVmoduleSyntax 在vmodule规则的格式不正确时返回。
+var errVmoduleSyntax = errors.New("expect comma-separated list of filename=N")
+
+// errTraceSyntax 在backtrace位置的格式不正确时返回。
+var errTraceSyntax = errors.New("expect file.go:234")
+
+// backtraceSize 是为调用栈快照准备的缓冲区大小，超出的部分会被截断。
+const backtraceSize = 1024 * 1024
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 GlogHandler
+
+// GlogHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// GlogHandler 仿照glog的做法，在一个全局的日志等级之外，还可以为某些文件或者某些包单独设置日志等级，
+// 例如只打开"rlp"包的Trace日志，而其他包仍然只输出Info日志。除此之外，还可以指定一个代码位置，例如
+// "encode.go:123"，在这个位置输出的日志会附带所有goroutine的调用栈。GlogHandler 的所有配置都可以
+// 在运行时修改，不需要重启程序，例如：
+//
+//	glogger := NewGlogHandler(StreamHandler(os.Stderr, TerminalFormat(false)))
+//	glogger.Verbosity(LvlInfo)
+//	glogger.Vmodule("rlp/*=5,p2p/server.go=4")
+//	Root().SetHandler(glogger)
+type GlogHandler struct {
+	origin Handler // 真正输出日志的处理器
+
+	level     uint32 // 全局的日志等级
+	override  uint32 // 不等于0时表示设置了vmodule规则
+	backtrace uint32 // 不等于0时表示设置了backtrace位置
+
+	patterns  []pattern       // vmodule规则，排在后面的规则优先
+	siteCache map[uintptr]Lvl // 缓存每个调用位置按照vmodule规则计算出的日志等级
+	location  string          // backtrace位置，格式为"file.go:123"
+	lock      sync.RWMutex    // 保护上面三个字段
+}
+
+// pattern ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// pattern 是一条vmodule规则，所有文件路径能与pattern匹配的日志，都使用level作为日志等级。
+type pattern struct {
+	pattern *regexp.Regexp
+	level   Lvl
+}
+
+// NewGlogHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewGlogHandler 方法将给定的 Handler 包装成一个 GlogHandler，新建的 GlogHandler 的全局日志等级是
+// LvlCrit，并且没有设置vmodule规则和backtrace位置。
+func NewGlogHandler(h Handler) *GlogHandler {
+	return &GlogHandler{
+		origin: h,
+	}
+}
+
+// SetHandler 方法替换真正输出日志的处理器。
+func (h *GlogHandler) SetHandler(nh Handler) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.origin = nh
+}
+
+// Verbosity 方法设置全局的日志等级，日志等级不超过level的日志都会被输出。
+func (h *GlogHandler) Verbosity(level Lvl) {
+	atomic.StoreUint32(&h.level, uint32(level))
+}
+
+// Vmodule ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Vmodule 方法设置vmodule规则，ruleset是由逗号分隔的若干条"pattern=N"规则，N是日志等级，可以是数字
+// （0表示Crit，5表示Trace），也可以是 LvlFromString 能够识别的名字，pattern用来匹配输出日志的代码文件
+// 的路径：
+//
+//   - "server.go=4"：名为server.go的文件；
+//   - "p2p=4"：p2p目录下的所有文件，不包括子目录；
+//   - "p2p/*=4"：p2p目录及其所有子目录下的文件；
+//   - "p2p/discover/table.go=5"：路径以p2p/discover/table.go结尾的文件。
+//
+// 一个文件能够匹配多条规则时，排在后面的规则优先。vmodule规则只会放宽全局日志等级，不会让原本应该输出的日志
+// 被过滤掉。ruleset为空字符串时清除所有的规则。
+func (h *GlogHandler) Vmodule(ruleset string) error {
+	var filter []pattern
+	for _, rule := range strings.Split(ruleset, ",") {
+		if len(rule) == 0 {
+			continue
+		}
+		parts := strings.Split(rule, "=")
+		if len(parts) != 2 {
+			return errVmoduleSyntax
+		}
+		parts[0] = strings.TrimSpace(parts[0])
+		parts[1] = strings.TrimSpace(parts[1])
+		if len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return errVmoduleSyntax
+		}
+		level, err := parseVerbosity(parts[1])
+		if err != nil {
+			return errVmoduleSyntax
+		}
+		// 将pattern转换成正则表达式，"*"可以匹配任意多级目录，不以".go"结尾的pattern表示一个目录
+		matcher := ".*"
+		for _, comp := range strings.Split(parts[0], "/") {
+			if comp == "*" {
+				matcher += "(/.*)?"
+			} else if comp != "" {
+				matcher += "/" + regexp.QuoteMeta(comp)
+			}
+		}
+		if !strings.HasSuffix(parts[0], ".go") {
+			matcher += "/[^/]+\\.go"
+		}
+		filter = append(filter, pattern{regexp.MustCompile(matcher + "$"), level})
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.patterns = filter
+	h.siteCache = make(map[uintptr]Lvl)
+	atomic.StoreUint32(&h.override, uint32(len(filter)))
+	return nil
+}
+
+// BacktraceAt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// BacktraceAt 方法设置backtrace位置，格式为"file.go:123"，在该位置输出的日志会在消息后面附带所有
+// goroutine的调用栈，location为空字符串时取消backtrace。
+func (h *GlogHandler) BacktraceAt(location string) error {
+	if len(location) > 0 {
+		parts := strings.Split(location, ":")
+		if len(parts) != 2 {
+			return errTraceSyntax
+		}
+		parts[0] = strings.TrimSpace(parts[0])
+		parts[1] = strings.TrimSpace(parts[1])
+		if len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return errTraceSyntax
+		}
+		if !strings.HasSuffix(parts[0], ".go") {
+			return errTraceSyntax
+		}
+		if _, err := strconv.Atoi(parts[1]); err != nil {
+			return errTraceSyntax
+		}
+		location = parts[0] + ":" + parts[1]
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.location = location
+	atomic.StoreUint32(&h.backtrace, uint32(len(location)))
+	return nil
+}
+
+// Log ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Log 方法实现了 Handler 接口，日志等级不超过全局日志等级的日志直接输出，否则根据输出日志的代码位置找到
+// 对应的vmodule规则，再决定是否输出。每个代码位置的计算结果都会被缓存起来，vmodule规则改变时缓存被清空。
+func (h *GlogHandler) Log(r *Record) error {
+	// 如果设置了backtrace位置，并且日志恰好是在该位置输出的，那么就在日志消息后面附带调用栈
+	if atomic.LoadUint32(&h.backtrace) > 0 {
+		h.lock.RLock()
+		match := h.location == fmt.Sprintf("%v", r.Call)
+		h.lock.RUnlock()
+
+		if match {
+			buf := make([]byte, backtraceSize)
+			buf = buf[:runtime.Stack(buf, true)]
+			r.Msg += "\n\n" + string(buf)
+		}
+	}
+	if Lvl(atomic.LoadUint32(&h.level)) >= r.Lvl {
+		return h.handler().Log(r)
+	}
+	if atomic.LoadUint32(&h.override) == 0 {
+		return nil
+	}
+	h.lock.RLock()
+	lvl, ok := h.siteCache[r.Call.PC()]
+	origin := h.origin
+	h.lock.RUnlock()
+
+	if !ok {
+		h.lock.Lock()
+		lvl = 0
+		file := fmt.Sprintf("%+s", r.Call)
+		for _, rule := range h.patterns {
+			if rule.pattern.MatchString(file) {
+				lvl = rule.level
+			}
+		}
+		h.siteCache[r.Call.PC()] = lvl
+		origin = h.origin
+		h.lock.Unlock()
+	}
+	if lvl >= r.Lvl {
+		return origin.Log(r)
+	}
+	return nil
+}
+
+// handler 方法在读锁的保护下返回真正输出日志的处理器。
+func (h *GlogHandler) handler() Handler {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.origin
+}
+
+// parseVerbosity 方法将数字或者名字形式的日志等级转换成 Lvl。
+func parseVerbosity(s string) (Lvl, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < int(LvlCrit) || n > int(LvlTrace) {
+			return 0, fmt.Errorf("verbosity %d out of range", n)
+		}
+		return Lvl(n), nil
+	}
+	return LvlFromString(s)
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordCollector 返回一个把日志记录保存到切片里的 Handler。
+func recordCollector(records *[]*Record) Handler {
+	return FuncHandler(func(r *Record) error {
+		*records = append(*records, r)
+		return nil
+	})
+}
+
+func TestGlogHandlerVerbosity(t *testing.T) {
+	var records []*Record
+	glogger := NewGlogHandler(recordCollector(&records))
+	l := New()
+	l.SetHandler(glogger)
+
+	glogger.Verbosity(LvlInfo)
+	l.Debug("debug")
+	l.Info("info")
+	l.Error("error")
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "info", records[0].Msg)
+	assert.Equal(t, "error", records[1].Msg)
+
+	// 运行时修改日志等级
+	glogger.Verbosity(LvlTrace)
+	l.Trace("trace")
+	assert.Equal(t, 3, len(records))
+}
+
+func TestGlogHandlerVmodule(t *testing.T) {
+	var records []*Record
+	glogger := NewGlogHandler(recordCollector(&records))
+	glogger.Verbosity(LvlWarn)
+	l := New()
+	l.SetHandler(glogger)
+
+	tests := []struct {
+		ruleset string
+		want    int // 依次输出Trace、Debug、Info三条日志，有多少条能被输出
+	}{
+		{ruleset: "", want: 0},
+		{ruleset: "handler_glog_test.go=4", want: 2},
+		{ruleset: "log=trace", want: 3},
+		{ruleset: "log/*=3", want: 1},
+		{ruleset: "rlp/*=5", want: 0},
+		{ruleset: "log/handler_glog_test.go=5", want: 3},
+		{ruleset: "log=5, handler_glog_test.go=3", want: 1},
+		{ruleset: "rlp/*=5,handler.go=5", want: 0},
+	}
+	for i, test := range tests {
+		assert.Nil(t, glogger.Vmodule(test.ruleset), "test %d", i)
+		records = records[:0]
+		l.Trace("trace")
+		l.Debug("debug")
+		l.Info("info")
+		assert.Equal(t, test.want, len(records), "test %d: %q", i, test.ruleset)
+	}
+
+	// vmodule规则不会过滤掉全局日志等级允许输出的日志
+	assert.Nil(t, glogger.Vmodule("log=0"))
+	records = records[:0]
+	l.Warn("warn")
+	assert.Equal(t, 1, len(records))
+
+	for _, ruleset := range []string{"log", "log=", "=5", "log=6", "log=x", "a=1=2"} {
+		assert.Equal(t, errVmoduleSyntax, glogger.Vmodule(ruleset), ruleset)
+	}
+}
+
+func TestGlogHandlerBacktraceAt(t *testing.T) {
+	var records []*Record
+	glogger := NewGlogHandler(recordCollector(&records))
+	glogger.Verbosity(LvlInfo)
+	l := New()
+	l.SetHandler(glogger)
+
+	_, _, line, _ := runtime.Caller(0)
+	assert.Nil(t, glogger.BacktraceAt(fmt.Sprintf("handler_glog_test.go:%d", line+2)))
+	l.Info("here")
+	l.Info("there")
+	assert.Equal(t, 2, len(records))
+	assert.True(t, strings.HasPrefix(records[0].Msg, "here\n\ngoroutine "))
+	assert.True(t, strings.Contains(records[0].Msg, "TestGlogHandlerBacktraceAt"))
+	assert.Equal(t, "there", records[1].Msg)
+
+	// 取消backtrace
+	assert.Nil(t, glogger.BacktraceAt(""))
+	records = records[:0]
+	l.Info("here")
+	assert.Equal(t, "here", records[0].Msg)
+
+	for _, location := range []string{"file.go", "file:12", "file.go:x", ":12", "a.go:1:2"} {
+		assert.Equal(t, errTraceSyntax, glogger.BacktraceAt(location), location)
+	}
+}
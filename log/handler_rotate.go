@@ -0,0 +1,313 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 RotatingHandler
+
+// compressSuffix 是压缩之后的旧日志文件的后缀。
+const compressSuffix = ".gz"
+
+// RotateConfig ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RotateConfig 定义了日志文件在什么时候被轮转，以及轮转之后的旧文件如何保存。MaxSize 和 Interval 都等于0
+// 时，日志文件只会在调用 RotatingHandler.Rotate 方法时被轮转。
+type RotateConfig struct {
+	MaxSize  int64         // 日志文件的最大字节数，写入一条日志会超过该值时先轮转文件，0表示不限制
+	Interval time.Duration // 日志文件打开之后经过多长时间轮转一次，0表示不按时间轮转
+	MaxFiles int           // 最多保留多少个旧文件，更旧的文件会被删除，0表示全部保留
+	Compress bool          // 是否用gzip压缩轮转之后的旧文件
+}
+
+// RotatingHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RotatingHandler 与 FileHandler 一样将日志写入文件，不同的是它会按照 RotateConfig 的设置轮转日志文件：
+// 当前的日志文件总是位于path，轮转时path被重命名为"path.1"，原来的"path.1"被重命名为"path.2"，以此类推，
+// 编号越大的文件越旧，设置了 RotateConfig.Compress 时，旧文件的名字是"path.1.gz"这样的形式。RotatingHandler
+// 可以被多个goroutine同时使用。
+type RotatingHandler struct {
+	path   string
+	fmtr   Format
+	config RotateConfig
+	h      Handler          // 在写入之前先计算 Lazy 的值
+	now    func() time.Time // 获取当前时间，测试时可以替换成假的时钟
+
+	file     *os.File
+	size     int64     // 当前日志文件的大小
+	openedAt time.Time // 当前日志文件的打开时间
+	lock     sync.Mutex
+}
+
+// RotatingFileHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RotatingFileHandler 方法打开位于path的日志文件，如果该文件已经存在，就在其后面追加日志，已有的内容也计入
+// RotateConfig.MaxSize。例如，日志文件超过100MB或者每过一天就轮转一次，最多保留7个压缩后的旧文件：
+//
+//	h, err := RotatingFileHandler("geth.log", LogfmtFormat(), RotateConfig{
+//		MaxSize:  100 << 20,
+//		Interval: 24 * time.Hour,
+//		MaxFiles: 7,
+//		Compress: true,
+//	})
+func RotatingFileHandler(path string, fmtr Format, config RotateConfig) (*RotatingHandler, error) {
+	if config.MaxSize < 0 || config.Interval < 0 || config.MaxFiles < 0 {
+		return nil, fmt.Errorf("invalid rotate config: %+v", config)
+	}
+	h := &RotatingHandler{path: path, fmtr: fmtr, config: config, now: time.Now}
+	h.h = LazyHandler(FuncHandler(h.write))
+	if err := h.open(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Log 方法实现了 Handler 接口。
+func (h *RotatingHandler) Log(r *Record) error {
+	return h.h.Log(r)
+}
+
+// Rotate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Rotate 方法立即轮转日志文件，不论当前文件的大小和打开时间。
+func (h *RotatingHandler) Rotate() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.rotate()
+}
+
+// Reopen ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Reopen 方法关闭当前的日志文件，然后重新打开path，用于配合logrotate这样的外部工具：外部工具移走日志文件
+// 之后发送SIGHUP信号，程序收到信号后调用 Reopen 方法，之后的日志会写入新创建的文件，例如：
+//
+//	sighup := make(chan os.Signal, 1)
+//	signal.Notify(sighup, syscall.SIGHUP)
+//	go func() {
+//		for range sighup {
+//			h.Reopen()
+//		}
+//	}()
+func (h *RotatingHandler) Reopen() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var closeErr error
+	if h.file != nil {
+		closeErr = h.file.Close()
+		h.file = nil
+	}
+	if err := h.open(); err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Close 方法关闭当前的日志文件，关闭之后再输出日志会返回错误。
+func (h *RotatingHandler) Close() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	err := h.file.Close()
+	h.file = nil
+	return err
+}
+
+// write ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// write 方法格式化日志记录，如果写入之后文件会超过大小限制，或者文件已经打开了足够长的时间，就先轮转文件，
+// 然后再写入。单条日志本身超过大小限制时不会被截断，而是单独占据一个文件。只要轮转之后日志文件仍然是打开的，
+// 日志就一定会被写入，移动旧文件时遇到的错误在写入之后才返回。
+func (h *RotatingHandler) write(r *Record) error {
+	msg := h.fmtr.Format(r)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.file == nil {
+		return os.ErrClosed
+	}
+	var rotateErr error
+	if h.shouldRotate(int64(len(msg))) {
+		if rotateErr = h.rotate(); h.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := h.file.Write(msg)
+	h.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+// shouldRotate 方法判断写入n个字节之前是否需要轮转文件，空文件永远不需要轮转。
+func (h *RotatingHandler) shouldRotate(n int64) bool {
+	if h.size == 0 {
+		return false
+	}
+	if h.config.MaxSize > 0 && h.size+n > h.config.MaxSize {
+		return true
+	}
+	return h.config.Interval > 0 && h.now().Sub(h.openedAt) >= h.config.Interval
+}
+
+// open 方法以追加的方式打开path，并记录文件的大小和打开时间。
+func (h *RotatingHandler) open() error {
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	h.file, h.size, h.openedAt = f, info.Size(), h.now()
+	return nil
+}
+
+// rotate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// rotate 方法关闭当前的日志文件，调用 shift 方法移动旧文件，然后重新打开path。即使关闭当前文件或者移动旧文件失败，
+// 也会重新打开path，保证之后的日志仍然能够被写入，此时path可能没有被移走，它会被当作一个新文件重新计算大小，这样
+// 就不会在之后的每一条日志上都重试轮转，而是等到再写满 RotateConfig.MaxSize 个字节或者再过一个 RotateConfig.Interval
+// 之后再重试。调用者必须持有锁。
+func (h *RotatingHandler) rotate() error {
+	var closeErr error
+	if h.file != nil {
+		// 关闭失败的文件也不能再使用了
+		closeErr = h.file.Close()
+		h.file = nil
+	}
+	shiftErr := h.shift()
+	if err := h.open(); err != nil {
+		return err
+	}
+	if shiftErr != nil {
+		h.size = 0
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	return shiftErr
+}
+
+// shift ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// shift 方法将所有旧文件的编号加一，当前文件变成编号为1的旧文件，删除超出 RotateConfig.MaxFiles 的旧文件，
+// 必要时压缩新产生的旧文件。
+func (h *RotatingHandler) shift() error {
+	backups, err := h.backups()
+	if err != nil {
+		return err
+	}
+	// 从最旧的文件开始重命名，避免覆盖还没有被移走的文件
+	for i := len(backups) - 1; i >= 0; i-- {
+		b := backups[i]
+		if h.config.MaxFiles > 0 && b.index >= h.config.MaxFiles {
+			if err = os.Remove(b.name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = os.Rename(b.name, h.backupName(b.index+1, b.compressed)); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(h.path, h.backupName(1, false)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if h.config.Compress {
+		return compressFile(h.backupName(1, false), h.backupName(1, true))
+	}
+	return nil
+}
+
+// backup 表示一个轮转之后的旧文件。
+type backup struct {
+	name       string
+	index      int
+	compressed bool
+}
+
+// backups 方法找出所有形如"path.N"和"path.N.gz"的旧文件，并按照编号从小到大排序。
+func (h *RotatingHandler) backups() ([]backup, error) {
+	matches, err := filepath.Glob(h.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, name := range matches {
+		suffix := strings.TrimPrefix(name, h.path+".")
+		compressed := strings.HasSuffix(suffix, compressSuffix)
+		index, err := strconv.Atoi(strings.TrimSuffix(suffix, compressSuffix))
+		if err != nil || index < 1 {
+			continue
+		}
+		backups = append(backups, backup{name: name, index: index, compressed: compressed})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].index < backups[j].index })
+	return backups, nil
+}
+
+// backupName 方法返回编号为index的旧文件的名字。
+func (h *RotatingHandler) backupName(index int, compressed bool) string {
+	name := h.path + "." + strconv.Itoa(index)
+	if compressed {
+		name += compressSuffix
+	}
+	return name
+}
+
+// compressFile ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// compressFile 方法用gzip将src压缩成dst，成功之后删除src，失败时保留src并删除不完整的dst。
+func compressFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+		}
+	}()
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
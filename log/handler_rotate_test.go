@@ -0,0 +1,212 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// msgFormat 只输出日志消息和换行符，便于计算文件大小。
+var msgFormat = FormatFunc(func(r *Record) []byte {
+	return []byte(r.Msg + "\n")
+})
+
+// fakeClock 是一个只有手动调用 Advance 方法时才会前进的时钟。
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestRotatingHandler 在临时目录里创建一个使用假时钟的 RotatingHandler。
+func newTestRotatingHandler(t *testing.T, config RotateConfig) (*RotatingHandler, *fakeClock, string) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}
+	path := filepath.Join(t.TempDir(), "node.log")
+	h, err := RotatingFileHandler(path, msgFormat, config)
+	assert.Nil(t, err)
+	h.now = clock.Now
+	h.openedAt = clock.Now()
+	t.Cleanup(func() { h.Close() })
+	return h, clock, path
+}
+
+// dirContents 返回日志目录里每个文件的内容，压缩过的文件会被解压。
+func dirContents(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	contents := make(map[string]string)
+	for _, entry := range entries {
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		assert.Nil(t, err)
+		var r io.Reader = f
+		if strings.HasSuffix(entry.Name(), compressSuffix) {
+			zr, err := gzip.NewReader(f)
+			assert.Nil(t, err)
+			r = zr
+		}
+		data, err := io.ReadAll(r)
+		assert.Nil(t, err)
+		f.Close()
+		contents[entry.Name()] = string(data)
+	}
+	return contents
+}
+
+func logMessages(h Handler, msgs ...string) {
+	for _, msg := range msgs {
+		h.Log(&Record{Msg: msg})
+	}
+}
+
+func TestRotatingHandlerSize(t *testing.T) {
+	h, _, path := newTestRotatingHandler(t, RotateConfig{MaxSize: 8, MaxFiles: 2})
+	logMessages(h, "aaa", "bbb", "ccc", "dddddddddd", "eee")
+	assert.Equal(t, map[string]string{
+		"node.log":   "eee\n",
+		"node.log.1": "dddddddddd\n",
+		"node.log.2": "ccc\n",
+	}, dirContents(t, filepath.Dir(path)))
+}
+
+func TestRotatingHandlerInterval(t *testing.T) {
+	h, clock, path := newTestRotatingHandler(t, RotateConfig{Interval: time.Hour, Compress: true})
+	logMessages(h, "a", "b")
+	clock.Advance(59 * time.Minute)
+	logMessages(h, "c")
+	clock.Advance(time.Minute)
+	logMessages(h, "d")
+	clock.Advance(2 * time.Hour)
+	logMessages(h, "e")
+	assert.Equal(t, map[string]string{
+		"node.log":      "e\n",
+		"node.log.1.gz": "d\n",
+		"node.log.2.gz": "a\nb\nc\n",
+	}, dirContents(t, filepath.Dir(path)))
+
+	// 手动轮转，MaxFiles为0时保留所有旧文件
+	assert.Nil(t, h.Rotate())
+	logMessages(h, "f")
+	assert.Equal(t, map[string]string{
+		"node.log":      "f\n",
+		"node.log.1.gz": "e\n",
+		"node.log.2.gz": "d\n",
+		"node.log.3.gz": "a\nb\nc\n",
+	}, dirContents(t, filepath.Dir(path)))
+}
+
+func TestRotatingHandlerReopen(t *testing.T) {
+	h, _, path := newTestRotatingHandler(t, RotateConfig{MaxSize: 100})
+	logMessages(h, "a")
+	// 模拟logrotate移走日志文件
+	assert.Nil(t, os.Rename(path, path+"-moved"))
+	logMessages(h, "b")
+	assert.Nil(t, h.Reopen())
+	logMessages(h, "c")
+	assert.Equal(t, map[string]string{
+		"node.log":       "c\n",
+		"node.log-moved": "a\nb\n",
+	}, dirContents(t, filepath.Dir(path)))
+
+	// 已有的内容计入文件大小
+	assert.Nil(t, h.Close())
+	h, err := RotatingFileHandler(path, msgFormat, RotateConfig{MaxSize: 3})
+	assert.Nil(t, err)
+	logMessages(h, "d")
+	assert.Nil(t, h.Close())
+	assert.Equal(t, "c\n", dirContents(t, filepath.Dir(path))["node.log.1"])
+	assert.Equal(t, os.ErrClosed, h.Log(&Record{Msg: "e"}))
+
+	_, err = RotatingFileHandler(path, msgFormat, RotateConfig{MaxFiles: -1})
+	assert.NotNil(t, err)
+}
+
+func TestRotatingHandlerShiftError(t *testing.T) {
+	h, _, path := newTestRotatingHandler(t, RotateConfig{MaxSize: 8, MaxFiles: 1})
+	// 非空的目录无法被 os.Remove 删除，轮转时超出 MaxFiles 的旧文件就删不掉了
+	assert.Nil(t, os.Mkdir(path+".1", 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(path+".1", "keep"), nil, 0644))
+
+	assert.Nil(t, h.Log(&Record{Msg: "aaaaaa"}))
+	// 轮转失败，但是日志依然被写入
+	assert.NotNil(t, h.Log(&Record{Msg: "bbb"}))
+	// 失败之后不会在每一条日志上都重试轮转
+	assert.Nil(t, h.Log(&Record{Msg: "c"}))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "aaaaaa\nbbb\nc\n", string(data))
+
+	// 旧文件可以删除之后，轮转恢复正常
+	assert.Nil(t, os.RemoveAll(path+".1"))
+	assert.Nil(t, h.Log(&Record{Msg: "ddddd"}))
+	assert.Equal(t, map[string]string{
+		"node.log":   "ddddd\n",
+		"node.log.1": "aaaaaa\nbbb\nc\n",
+	}, dirContents(t, filepath.Dir(path)))
+}
+
+func TestRotatingHandlerCloseError(t *testing.T) {
+	h, _, path := newTestRotatingHandler(t, RotateConfig{MaxSize: 8})
+	assert.Nil(t, h.Log(&Record{Msg: "aaaaaa"}))
+	// 提前关闭底层的文件，轮转时关闭文件就会失败
+	assert.Nil(t, h.file.Close())
+	assert.NotNil(t, h.Log(&Record{Msg: "bbb"}))
+	// 轮转依然完成了，之后的日志写入新的文件
+	assert.Nil(t, h.Log(&Record{Msg: "c"}))
+	assert.Equal(t, map[string]string{
+		"node.log":   "bbb\nc\n",
+		"node.log.1": "aaaaaa\n",
+	}, dirContents(t, filepath.Dir(path)))
+
+	// Reopen 同样如此
+	assert.Nil(t, h.file.Close())
+	assert.NotNil(t, h.Reopen())
+	assert.Nil(t, h.Log(&Record{Msg: "d"}))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "bbb\nc\nd\n", string(data))
+}
+
+func TestRotatingHandlerConcurrent(t *testing.T) {
+	h, _, path := newTestRotatingHandler(t, RotateConfig{MaxSize: 64})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logMessages(h, "0123456")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 每个文件都恰好容纳8条日志，没有日志丢失或者交错
+	contents := dirContents(t, filepath.Dir(path))
+	assert.Equal(t, 100, len(contents))
+	var names []string
+	for name, content := range contents {
+		assert.Equal(t, strings.Repeat("0123456\n", 8), content, name)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, "node.log", names[0])
+}
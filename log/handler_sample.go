@@ -0,0 +1,216 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 SampleHandler
+
+// sampleBucket ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// sampleBucket 记录了一类相同的日志在当前时间窗口内的输出情况，summary是窗口结束时用来报告被抑制的日志数量的模板。
+type sampleBucket struct {
+	start      time.Time // 当前时间窗口的开始时间
+	count      int       // 当前时间窗口内已经输出的日志数量
+	suppressed int       // 当前时间窗口内被抑制的日志数量
+	summary    Record
+}
+
+// SamplingHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SamplingHandler 是 SampleHandler 方法返回的限流器，now用来获取当前时间，测试时可以替换成假的时钟。后台的goroutine
+// 每收到一次tick就检查一遍所有的时间窗口，报告已经结束的窗口里被抑制的日志数量。
+type SamplingHandler struct {
+	n        int
+	interval time.Duration
+	keys     []string
+	h        Handler
+	now      func() time.Time
+
+	buckets   map[string]*sampleBucket
+	lastSweep time.Time
+	lock      sync.Mutex
+
+	stopTicker func()
+	quit       chan struct{}
+	exited     chan struct{}
+	closeOnce  sync.Once
+}
+
+// SampleHandler ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SampleHandler 方法对相同的日志进行限流：日志消息相同，并且keys里的每个键在 Ctx 里对应的值也相同的日志被认为是
+// 相同的，它们在每interval时间内最多输出n条，多出来的日志被抑制。时间窗口结束之后，如果有日志被抑制，就以原日志的
+// 等级输出一条"Suppressed log records"日志，在suppressed_msg里说明被抑制的日志消息，并附上被抑制的数量以及keys
+// 对应的值。例如，同一个peer的"Dropping peer"日志每分钟最多输出5条：
+//
+//	sampled, err := SampleHandler(5, time.Minute, []string{"peer"}, h)
+//	if err != nil {
+//		return err
+//	}
+//	defer sampled.Close()
+//
+// 后台的goroutine每隔interval检查一次过期的时间窗口，所以即使之后再也没有日志，被抑制的日志数量最迟也会在窗口结束
+// 之后的一个interval内被报告。不再使用时应当调用 Close 方法结束后台goroutine。n小于0或者interval不大于0时返回错误。
+func SampleHandler(n int, interval time.Duration, keys []string, h Handler) (*SamplingHandler, error) {
+	if n < 0 || interval <= 0 {
+		return nil, fmt.Errorf("invalid sample config: n=%d interval=%v", n, interval)
+	}
+	ticker := time.NewTicker(interval)
+	s := newSampler(n, interval, keys, h, time.Now, ticker.C)
+	s.stopTicker = ticker.Stop
+	return s, nil
+}
+
+// newSampler 方法创建一个使用给定时钟的 SamplingHandler，后台goroutine每从tick里收到一个值就检查一次过期的时间窗口。
+func newSampler(n int, interval time.Duration, keys []string, h Handler, now func() time.Time, tick <-chan time.Time) *SamplingHandler {
+	s := &SamplingHandler{
+		n:          n,
+		interval:   interval,
+		keys:       keys,
+		h:          h,
+		now:        now,
+		buckets:    make(map[string]*sampleBucket),
+		stopTicker: func() {},
+		quit:       make(chan struct{}),
+		exited:     make(chan struct{}),
+	}
+	go s.loop(tick)
+	return s
+}
+
+// Log ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Log 方法实现了 Handler 接口，它先报告所有已经结束的时间窗口里被抑制的日志，然后决定是否输出当前的日志。
+func (s *SamplingHandler) Log(r *Record) error {
+	id, values := s.identify(r)
+
+	s.lock.Lock()
+	now := s.now()
+	var summaries []*Record
+	if now.Sub(s.lastSweep) >= s.interval {
+		summaries = s.sweep(now)
+		s.lastSweep = now
+	}
+	b, ok := s.buckets[id]
+	if !ok || now.Sub(b.start) >= s.interval {
+		if ok && b.suppressed > 0 {
+			summaries = append(summaries, b.report())
+		}
+		b = &sampleBucket{start: now, summary: Record{
+			Time:     r.Time,
+			Lvl:      r.Lvl,
+			Msg:      "Suppressed log records",
+			Ctx:      append([]interface{}{"suppressed_msg", r.Msg}, values...),
+			Call:     r.Call,
+			KeyNames: r.KeyNames,
+		}}
+		s.buckets[id] = b
+	}
+	pass := b.count < s.n
+	if pass {
+		b.count++
+	} else {
+		b.suppressed++
+	}
+	s.lock.Unlock()
+
+	s.logAll(summaries)
+	if pass {
+		return s.h.Log(r)
+	}
+	return nil
+}
+
+// Close ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Close 方法结束后台goroutine，并立即报告所有时间窗口里被抑制的日志，包括还没有结束的时间窗口。Close 之后依然可以
+// 输出日志，只是被抑制的日志数量不会再被定时报告。重复调用 Close 方法不会产生任何影响。
+func (s *SamplingHandler) Close() error {
+	s.closeOnce.Do(func() {
+		s.stopTicker()
+		close(s.quit)
+		<-s.exited
+
+		s.lock.Lock()
+		var summaries []*Record
+		for id, b := range s.buckets {
+			if b.suppressed > 0 {
+				summaries = append(summaries, b.report())
+			}
+			delete(s.buckets, id)
+		}
+		s.lock.Unlock()
+		s.logAll(summaries)
+	})
+	return nil
+}
+
+// loop 方法运行在后台goroutine里，每收到一次tick就报告一次已经结束的时间窗口，直到 Close 方法被调用。
+func (s *SamplingHandler) loop(tick <-chan time.Time) {
+	defer close(s.exited)
+
+	for {
+		select {
+		case <-tick:
+			s.lock.Lock()
+			now := s.now()
+			summaries := s.sweep(now)
+			s.lastSweep = now
+			s.lock.Unlock()
+			s.logAll(summaries)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// logAll 方法输出报告被抑制的日志数量的日志，调用者不能持有锁。
+func (s *SamplingHandler) logAll(summaries []*Record) {
+	for _, summary := range summaries {
+		_ = s.h.Log(summary)
+	}
+}
+
+// sweep 方法删除所有已经结束的时间窗口，并返回报告其中被抑制的日志数量的日志，调用者必须持有锁。
+func (s *SamplingHandler) sweep(now time.Time) []*Record {
+	var summaries []*Record
+	for id, b := range s.buckets {
+		if now.Sub(b.start) < s.interval {
+			continue
+		}
+		if b.suppressed > 0 {
+			summaries = append(summaries, b.report())
+		}
+		delete(s.buckets, id)
+	}
+	return summaries
+}
+
+// identify ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// identify 方法根据日志消息和keys对应的值为日志生成一个标识，相同的日志具有相同的标识，同时返回keys和对应的值组成的
+// 键值对，用于报告被抑制的日志。Ctx 里没有的键对应的值为nil。
+func (s *SamplingHandler) identify(r *Record) (string, []interface{}) {
+	var id strings.Builder
+	id.WriteString(r.Msg)
+	values := make([]interface{}, 0, 2*len(s.keys))
+	for _, key := range s.keys {
+		v, _ := lookupKey(r, key)
+		fmt.Fprintf(&id, "\x00%s=%v", key, v)
+		values = append(values, key, v)
+	}
+	return id.String(), values
+}
+
+// report 方法返回报告被抑制的日志数量的日志。
+func (b *sampleBucket) report() *Record {
+	summary := b.summary
+	summary.Ctx = append(summary.Ctx[:len(summary.Ctx):len(summary.Ctx)], "suppressed", b.suppressed)
+	return &summary
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleHandler(t *testing.T) {
+	var records []*Record
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}
+	h := newSampler(2, time.Minute, []string{"peer"}, recordCollector(&records), clock.Now, nil)
+	defer h.Close()
+	l := New()
+	l.SetHandler(h)
+
+	for i := 0; i < 5; i++ {
+		l.Warn("Dropping peer", "peer", "a", "attempt", i)
+		l.Warn("Dropping peer", "peer", "b", "attempt", i)
+	}
+	l.Info("Imported block")
+	// 每个peer各输出2条，其他键的值不影响是否相同
+	assert.Equal(t, 5, len(records))
+
+	// 时间窗口结束之后，任意一条日志都会触发报告
+	clock.Advance(time.Minute)
+	records = records[:0]
+	l.Info("Imported block")
+	assert.Equal(t, 3, len(records))
+	summaries := map[interface{}]*Record{records[0].Ctx[3]: records[0], records[1].Ctx[3]: records[1]}
+	for _, peer := range []string{"a", "b"} {
+		summary := summaries[peer]
+		assert.Equal(t, "Suppressed log records", summary.Msg)
+		assert.Equal(t, LvlWarn, summary.Lvl)
+		assert.Equal(t, []interface{}{"suppressed_msg", "Dropping peer", "peer", peer, "suppressed", 3}, summary.Ctx)
+	}
+	assert.Equal(t, "Imported block", records[2].Msg)
+
+	// 新的时间窗口重新计数
+	records = records[:0]
+	clock.Advance(30 * time.Second)
+	for i := 0; i < 3; i++ {
+		l.Warn("Dropping peer", "peer", "a")
+	}
+	assert.Equal(t, 2, len(records))
+	clock.Advance(59 * time.Second)
+	l.Warn("Dropping peer", "peer", "a")
+	assert.Equal(t, 2, len(records))
+	clock.Advance(time.Second)
+	l.Warn("Dropping peer", "peer", "a")
+	assert.Equal(t, 4, len(records))
+	assert.Equal(t, []interface{}{"suppressed_msg", "Dropping peer", "peer", "a", "suppressed", 2}, records[2].Ctx)
+	assert.Equal(t, "Dropping peer", records[3].Msg)
+}
+
+func TestSampleHandlerPeriodicReport(t *testing.T) {
+	var records []*Record
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}
+	tick := make(chan time.Time)
+	h := newSampler(1, time.Minute, nil, recordCollector(&records), clock.Now, tick)
+	l := New()
+	l.SetHandler(h)
+
+	for i := 0; i < 4; i++ {
+		l.Warn("Dropping peer")
+	}
+	assert.Equal(t, 1, len(records))
+
+	// 时间窗口还没有结束，不会报告
+	tick <- clock.Now()
+	tick <- clock.Now()
+	assert.Equal(t, 1, len(records))
+
+	// 之后再也没有日志，后台goroutine依然会报告被抑制的日志。tick是无缓冲的通道，第二次发送成功时第一次tick已经处理完了
+	clock.Advance(time.Minute)
+	tick <- clock.Now()
+	tick <- clock.Now()
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "Suppressed log records", records[1].Msg)
+	assert.Equal(t, []interface{}{"suppressed_msg", "Dropping peer", "suppressed", 3}, records[1].Ctx)
+
+	// Close 会立即报告还没有结束的时间窗口
+	l.Warn("Dropping peer")
+	l.Warn("Dropping peer")
+	assert.Nil(t, h.Close())
+	assert.Equal(t, 4, len(records))
+	assert.Equal(t, []interface{}{"suppressed_msg", "Dropping peer", "suppressed", 1}, records[3].Ctx)
+	assert.Nil(t, h.Close())
+}
+
+func TestSampleHandlerTicker(t *testing.T) {
+	records := make(chan *Record, 10)
+	h, err := SampleHandler(1, 10*time.Millisecond, nil, FuncHandler(func(r *Record) error {
+		records <- r
+		return nil
+	}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer h.Close()
+	l := New()
+	l.SetHandler(h)
+	l.Warn("Dropping peer")
+	l.Warn("Dropping peer")
+	<-records
+	select {
+	case r := <-records:
+		assert.Equal(t, "Suppressed log records", r.Msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("suppressed records were not reported")
+	}
+}
+
+func TestSampleHandlerInvalidConfig(t *testing.T) {
+	for _, test := range []struct {
+		n        int
+		interval time.Duration
+	}{
+		{-1, time.Minute},
+		{1, 0},
+		{1, -time.Second},
+	} {
+		h, err := SampleHandler(test.n, test.interval, nil, DiscardHandler())
+		assert.Nil(t, h)
+		assert.NotNil(t, err, "n=%d interval=%v", test.n, test.interval)
+	}
+	// n为0时抑制所有的日志，这是合法的配置
+	h, err := SampleHandler(0, time.Minute, nil, DiscardHandler())
+	assert.Nil(t, err)
+	assert.Nil(t, h.Close())
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errNetDown  = errors.New("connection reset")
+	errDiskFull = errors.New("no space left on device")
+)
+
+// failingHandler 记录自己被调用的次数，并且总是返回err。
+type failingHandler struct {
+	err   error
+	calls int
+}
+
+func (h *failingHandler) Log(r *Record) error {
+	h.calls++
+	return h.err
+}
+
+func TestMultiHandler(t *testing.T) {
+	var records []*Record
+	net, disk := &failingHandler{err: errNetDown}, &failingHandler{err: errDiskFull}
+
+	h := MultiHandler(net, recordCollector(&records), disk)
+	err := h.Log(&Record{Msg: "hello"})
+	assert.EqualError(t, err, "handler 0: connection reset; handler 2: no space left on device")
+	assert.True(t, errors.Is(err, errNetDown))
+	assert.True(t, errors.Is(err, errDiskFull))
+	assert.Equal(t, 1, net.calls)
+	assert.Equal(t, 1, disk.calls)
+	assert.Equal(t, 1, len(records))
+
+	// 所有的 Handler 都成功时不返回错误
+	h = MultiHandler(recordCollector(&records), recordCollector(&records))
+	assert.Nil(t, h.Log(&Record{Msg: "hello"}))
+	assert.Equal(t, 3, len(records))
+	assert.Nil(t, MultiHandler().Log(&Record{}))
+}
+
+func TestFailoverHandler(t *testing.T) {
+	var records []*Record
+	net, disk := &failingHandler{err: errNetDown}, &failingHandler{err: errDiskFull}
+
+	h := FailoverHandler(net, disk, recordCollector(&records), recordCollector(&records))
+	assert.Nil(t, h.Log(&Record{Msg: "hello", Ctx: []interface{}{"k", "v"}}))
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, []interface{}{"k", "v", "failover_err_0", errNetDown, "failover_err_1", errDiskFull}, records[0].Ctx)
+
+	// 第一个 Handler 成功时不会尝试后面的 Handler
+	h = FailoverHandler(recordCollector(&records), net)
+	assert.Nil(t, h.Log(&Record{Msg: "hello"}))
+	assert.Equal(t, 2, len(records))
+	assert.Nil(t, records[1].Ctx)
+	assert.Equal(t, 1, net.calls)
+
+	// 全部失败时返回最后一个错误
+	h = FailoverHandler(net, disk)
+	assert.Equal(t, errDiskFull, h.Log(&Record{Msg: "hello"}))
+	assert.Equal(t, 2, net.calls)
+	assert.Equal(t, 2, disk.calls)
+}
+
+func TestMatchFilterHandler(t *testing.T) {
+	var records []*Record
+	l := New("module", "p2p")
+	l.SetHandler(MatchFilterHandler("peer", "a", recordCollector(&records)))
+	l.Info("hello", "peer", "a")
+	l.Info("hello", "peer", "b")
+	l.Info("hello")
+	l.Info("hello", "peer", []byte("a"))
+	assert.Equal(t, 1, len(records))
+
+	// 不可比较的值不会引发panic
+	records = records[:0]
+	l.SetHandler(MatchFilterHandler("ids", []int{1, 2}, recordCollector(&records)))
+	l.Info("hello", "ids", []int{1, 2})
+	l.Info("hello", "ids", []int{1})
+	assert.Equal(t, 1, len(records))
+
+	// 日志等级、日志消息以及 Ctx 形式的键值对
+	records = records[:0]
+	l.SetHandler(MatchFilterHandler(lvlKey, LvlError, MatchFilterHandler(msgKey, "boom", recordCollector(&records))))
+	l.Error("boom")
+	l.Warn("boom")
+	l.Error("bang")
+	assert.Equal(t, 1, len(records))
+
+	records = records[:0]
+	l = New()
+	l.SetHandler(MatchAnyFilterHandler("module", []interface{}{"eth", "p2p"}, recordCollector(&records)))
+	l.Info("hello", "module", "p2p")
+	l.Info("hello", Ctx{"module": "eth"})
+	l.Info("hello", Ctx{"module": "les"})
+	// 有多个相同的键时只看第一个
+	l.Info("hello", "module", "les", "module", "eth")
+	assert.Equal(t, 2, len(records))
+}
@@ -14,6 +14,7 @@ import (
 	"io"
 	"math/big"
 	"reflect"
+	"strings"
 )
 
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
@@ -25,10 +26,7 @@ import (
 // Decode 方法接受两个参数，第一个参数是一个 io.Reader，RLP编码数据被存储在里面，第二个参数是一个指针，
 // 将被编码的数据解码到该指针里面。
 func Decode(r io.Reader, val interface{}) error {
-	stream := streamPool.Get().(*Stream)
-	defer streamPool.Put(stream)
-	stream.Reset(r, 0)
-	return stream.Decode(val)
+	return DecodeWithOptions(r, val, DecodeOptions{})
 }
 
 // DecodeBytes ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
@@ -36,10 +34,52 @@ func Decode(r io.Reader, val interface{}) error {
 // DecodeBytes 方法接受两个参数，第一个参数是一个字节切片，里面存储了原始的RLP编码数据，第二个参数是一个指针，
 // 将被编码的数据解码到该指针里面。
 func DecodeBytes(bz []byte, val interface{}) error {
+	return DecodeBytesWithOptions(bz, val, DecodeOptions{})
+}
+
+// DecodeWithOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeWithOptions 方法与 Decode 方法一样，但是解码过程受到opts给出的资源限制，适合用来解码来自不可信来源的数据。
+func DecodeWithOptions(r io.Reader, val interface{}, opts DecodeOptions) error {
+	stream := streamPool.Get().(*Stream)
+	defer streamPool.Put(stream)
+	stream.Reset(r, 0)
+	stream.opts = opts
+	return stream.Decode(val)
+}
+
+// DecodeBytesWithOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeBytesWithOptions 方法与 DecodeBytes 方法一样，但是解码过程受到opts给出的资源限制。
+func DecodeBytesWithOptions(bz []byte, val interface{}, opts DecodeOptions) error {
+	r := bytes.NewReader(bz)
+	stream := streamPool.Get().(*Stream)
+	defer streamPool.Put(stream)
+	stream.Reset(r, uint64(len(bz)))
+	stream.opts = opts
+	stream.input = bz
+	if err := stream.Decode(val); err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return ErrMoreThanOneValue
+	}
+	return nil
+}
+
+// DecodeBytesNoCopy ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeBytesNoCopy 方法与 DecodeBytes 方法一样，但是解码结果里所有的[]byte和 RawValue（包括在 DecodeRLP 方法里调用
+// Stream.Bytes 和 Stream.Raw 得到的结果）都直接引用bz里的对应部分，而不是复制一份，这样解码很大的数据时不会使内存占用翻倍。
+// 作为代价，只要解码结果还在使用，bz就不能被修改或者复用，反过来解码结果里的这些字节切片也不能被修改，否则bz会被一同修改。
+// 只需要让个别字段引用输入数据时，可以为这些字段设置`rlp:"alias"`标签，然后使用 DecodeBytes 方法解码。
+func DecodeBytesNoCopy(bz []byte, val interface{}) error {
 	r := bytes.NewReader(bz)
 	stream := streamPool.Get().(*Stream)
 	defer streamPool.Put(stream)
 	stream.Reset(r, uint64(len(bz)))
+	stream.input = bz
+	stream.noCopy = true
 	if err := stream.Decode(val); err != nil {
 		return err
 	}
@@ -66,6 +106,12 @@ var (
 	ErrElemTooLarge     = errors.New("rlp: element is larger than containing list")
 	ErrValueTooLarge    = errors.New("rlp: value size exceeds available input length")
 	ErrMoreThanOneValue = errors.New("rlp: input contains more than one value")
+	ErrMapKeyDuplicate  = errors.New("rlp: duplicate map key")
+	ErrMapKeyOrder      = errors.New("rlp: map keys not in canonical order")
+	ErrDepthLimit       = errors.New("rlp: list nesting depth exceeds limit")
+	ErrListLengthLimit  = errors.New("rlp: list element count exceeds limit")
+	ErrStringSizeLimit  = errors.New("rlp: string size exceeds limit")
+	ErrAllocLimit       = errors.New("rlp: total allocation exceeds limit")
 )
 
 // 定义内部错误
@@ -76,36 +122,121 @@ var (
 	errDecodeIntoNil = errors.New("rlp: pointer given to Decode must not be nil")
 	errNoPointer     = errors.New("rlp: interface given to Decode must be a pointer")
 	errNotInList     = errors.New("rlp: call of ListEnd outside of any list")
+	errIterateDepth  = errors.New("rlp: Iterate callback changed the list nesting depth")
 )
 
 // 自定义错误类型
 
-// decodeError ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
+// DecodeError ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
+//
+// DecodeError 定义解码时可能遇到的错误，它记录了出错的Go类型、出错的编码值在输入数据里的绝对偏移量、实际遇到的编码类型和期
+// 望的编码类型，以及从被解码的顶层类型到出错字段的结构化路径，例如"Block.Txs[12].To"。调用者可以通过 errors.As 获取该错误，
+// 从而精确地记录出错位置；通过 errors.Is 则可以判断底层的错误原因，例如 ErrCanonInt、ErrExpectedList。
 //
-// decodeError 定义解码时可能遇到的错误
-type decodeError struct {
-	msg string
-	typ reflect.Type
-	ctx []string
+// Offset 和 Kind 是在 Stream.Decode 返回时根据 Stream 的读取位置填写的：一般情况下 Offset 指向出错的编码值的编码前缀的
+// 第一个字节；对于列表里的元素太多或太少这类错误，Offset 指向列表里出问题的位置，此时 Kind 为 List。
+type DecodeError struct {
+	Type     reflect.Type // 出错时正在解码的Go类型
+	Offset   uint64       // 出错位置在输入数据里的绝对偏移量
+	Kind     Kind         // 出错位置实际遇到的编码类型
+	Expected Kind         // 期望的编码类型，只有当错误是编码类型不匹配时才与 Kind 不同
+
+	msg         string
+	err         error        // 底层的错误原因，可能为nil
+	root        reflect.Type // 传给 Stream.Decode 的顶层类型
+	ctx         []string     // 从出错字段开始，由内向外记录的路径
+	located     bool         // Offset 和 Kind 是否已经被填写过
+	expectedSet bool         // Expected 是否在创建错误时就已经确定了
 }
 
-func (err *decodeError) Error() string {
+func (err *DecodeError) Error() string {
 	ctx := ""
 	if len(err.ctx) > 0 {
 		ctx = ", decoding into "
+		if err.root != nil {
+			ctx += fmt.Sprintf("(%v)", err.root)
+		}
 		for i := len(err.ctx) - 1; i >= 0; i-- {
 			ctx += err.ctx[i]
 		}
 	}
-	return fmt.Sprintf("rlp: %s for %v%s", err.msg, err.typ, ctx)
+	return fmt.Sprintf("rlp: %s for %v%s", err.msg, err.Type, ctx)
+}
+
+// Unwrap 方法返回底层的错误原因，使得 errors.Is(err, ErrCanonInt) 这样的判断可以成立。
+func (err *DecodeError) Unwrap() error {
+	return err.err
+}
+
+// Path ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Path 方法返回从顶层类型到出错字段的路径，例如"Block.Txs[12].To"，如果错误就发生在顶层类型上，则返回空字符串。
+func (err *DecodeError) Path() string {
+	elems := err.PathElems()
+	if len(elems) == 0 {
+		return ""
+	}
+	path := ""
+	if err.root != nil {
+		path = err.root.Name()
+		if path == "" {
+			path = err.root.String()
+		}
+	}
+	return path + strings.Join(elems, "")
+}
+
+// PathElems ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// PathElems 方法由外向内返回路径的各个组成部分，结构体字段的形式为".Name"，列表元素的形式为"[i]"，map的键和值分别为
+// "[i].key"和"[i].value"。
+func (err *DecodeError) PathElems() []string {
+	elems := make([]string, len(err.ctx))
+	for i := range err.ctx {
+		elems[i] = err.ctx[len(err.ctx)-1-i]
+	}
+	return elems
+}
+
+// locate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// locate 方法根据 Stream 当前的状态填写错误的 Offset、Kind 和 Expected 字段。由于解码器在遇到错误后会立即返回，不会再读
+// 取数据，所以此时 Stream 最近一次解析的编码前缀就是出错的编码值。嵌套调用 Stream.Decode 时，只有最内层的那一次会生效。
+func (err *DecodeError) locate(s *Stream) {
+	if err.located {
+		return
+	}
+	err.located = true
+	switch err.err {
+	case EOL, errNotAtEOL:
+		err.Offset, err.Kind = s.pos, List
+	default:
+		err.Offset, err.Kind = s.valuePos, s.valueKind
+	}
+	if err.expectedSet {
+		return
+	}
+	switch err.err {
+	case ErrExpectedList:
+		err.Expected = List
+	case ErrExpectedString:
+		err.Expected = String
+	default:
+		err.Expected = err.Kind
+	}
 }
 
 // addErrorContext ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
 //
-// addErrorContext 该方法接受两个参数：error 和一个字符串ctx，如果给定的error的类型是 *decodeError，
-// 则将参数ctx添加到 *decodeError.ctx 中。
+// addErrorContext 该方法接受两个参数：error 和一个字符串ctx，如果给定的error的类型是 *DecodeError，
+// 则将参数ctx添加到 *DecodeError.ctx 中。
 func addErrorContext(err error, ctx string) error {
-	if decErr, ok := err.(*decodeError); ok {
+	if decErr, ok := err.(*DecodeError); ok {
+		if decErr.root != nil {
+			// 错误来自嵌套的 Stream.Decode 调用（例如在 DecodeRLP 方法里），把内层的顶层类型也记录到路径里
+			decErr.ctx = append(decErr.ctx, fmt.Sprintf("(%v)", decErr.root))
+			decErr.root = nil
+		}
 		decErr.ctx = append(decErr.ctx, ctx)
 	}
 	return err
@@ -115,23 +246,41 @@ func addErrorContext(err error, ctx string) error {
 //
 // wrapStreamError 方法接受两个入参：error 和 reflect.Type，如果给定的 error 属于以下自定义的错误：
 //
-//	ErrCanonInt、ErrCanonSize、ErrExpectedList、ErrExpectedString、errUintOverflow、errNotAtEOL
+//	ErrCanonInt、ErrCanonSize、ErrExpectedList、ErrExpectedString、errUintOverflow、errNotAtEOL、
+//	ErrMapKeyDuplicate、ErrMapKeyOrder、ErrDepthLimit、ErrListLengthLimit、ErrStringSizeLimit、ErrAllocLimit、
+//	ErrElemTooLarge、ErrValueTooLarge
 //
-// 则将给定的错误包装成 *decodeError。
+// 则将给定的错误包装成 *DecodeError。
 func wrapStreamError(err error, typ reflect.Type) error {
 	switch err {
 	case ErrCanonInt:
-		return &decodeError{msg: "non-canonical integer (leading zero bytes)", typ: typ}
+		return &DecodeError{msg: "non-canonical integer (leading zero bytes)", Type: typ, err: err}
 	case ErrCanonSize:
-		return &decodeError{msg: "non-canonical size information", typ: typ}
+		return &DecodeError{msg: "non-canonical size information", Type: typ, err: err}
 	case ErrExpectedList:
-		return &decodeError{msg: "expected input list", typ: typ}
+		return &DecodeError{msg: "expected input list", Type: typ, err: err}
 	case ErrExpectedString:
-		return &decodeError{msg: "expected input string or byte", typ: typ}
+		return &DecodeError{msg: "expected input string or byte", Type: typ, err: err}
 	case errUintOverflow:
-		return &decodeError{msg: "input string too long", typ: typ}
+		return &DecodeError{msg: "input string too long", Type: typ, err: err}
 	case errNotAtEOL:
-		return &decodeError{msg: "input list has too many elements", typ: typ}
+		return &DecodeError{msg: "input list has too many elements", Type: typ, err: err}
+	case ErrMapKeyDuplicate:
+		return &DecodeError{msg: "duplicate map key", Type: typ, err: err}
+	case ErrMapKeyOrder:
+		return &DecodeError{msg: "map keys not in canonical order", Type: typ, err: err}
+	case ErrDepthLimit:
+		return &DecodeError{msg: "list nesting too deep", Type: typ, err: err}
+	case ErrListLengthLimit:
+		return &DecodeError{msg: "input list has too many elements for limit", Type: typ, err: err}
+	case ErrStringSizeLimit:
+		return &DecodeError{msg: "input string too large for limit", Type: typ, err: err}
+	case ErrAllocLimit:
+		return &DecodeError{msg: "allocation limit exceeded", Type: typ, err: err}
+	case ErrElemTooLarge:
+		return &DecodeError{msg: "element is larger than containing list", Type: typ, err: err}
+	case ErrValueTooLarge:
+		return &DecodeError{msg: "value size exceeds available input length", Type: typ, err: err}
 	}
 	return err
 }
@@ -148,12 +297,20 @@ var EmptyList = []byte{0xC0}
 func makeDecoder(typ reflect.Type, tag rlpstruct.Tag) (decoder, error) {
 	kind := typ.Kind()
 	switch {
+	case tag.Signed:
+		return makeSignedDecoder(typ)
+	case typ == rawValueType && tag.Alias:
+		return decodeRawValueAlias, nil
 	case typ == rawValueType:
 		return decodeRawValue, nil
 	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
 		return decodeBigIntPtr, nil
 	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
 		return decodeBigIntNoPtr, nil
+	case typ == reflect.PtrTo(u256Type):
+		return decodeU256Ptr, nil
+	case typ == u256Type:
+		return decodeU256NoPtr, nil
 	case reflect.PtrTo(typ).Implements(decoderInterface):
 		return decodeDecoder, nil
 	case isUint(kind):
@@ -168,6 +325,8 @@ func makeDecoder(typ reflect.Type, tag rlpstruct.Tag) (decoder, error) {
 		return makeStructDecoder(typ)
 	case kind == reflect.Slice || kind == reflect.Array:
 		return makeListDecoder(typ, tag)
+	case kind == reflect.Map:
+		return makeMapDecoder(typ)
 	case kind == reflect.Pointer:
 		return makePtrDecoder(typ, tag)
 	default:
@@ -213,13 +372,48 @@ func decodeBigIntPtr(s *Stream, val reflect.Value) error {
 	return nil
 }
 
+// decodeU256Ptr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeU256Ptr 方法将输入解码为 *U256，如果目标指针为nil，则为其分配一个新的 U256。
+func decodeU256Ptr(s *Stream, val reflect.Value) error {
+	x := val.Interface().(*U256)
+	if x == nil {
+		x = new(U256)
+		val.Set(reflect.ValueOf(x))
+	}
+	if err := s.ReadUint256(x); err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	return nil
+}
+
+// decodeU256NoPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeU256NoPtr 方法将输入解码为 U256，解码的目标总是可寻址的，所以直接取它的地址进行解码，不会分配内存。
+func decodeU256NoPtr(s *Stream, val reflect.Value) error {
+	if err := s.ReadUint256(val.Addr().Interface().(*U256)); err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	return nil
+}
+
 // decodeRawValue ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
 // decodeRawValue 方法实现 decoder 函数句柄，读取stream底层的输入，将其解码为 RawValue。
 func decodeRawValue(s *Stream, val reflect.Value) error {
 	r, err := s.Raw()
 	if err != nil {
-		return err
+		return wrapStreamError(err, val.Type())
+	}
+	val.SetBytes(r)
+	return nil
+}
+
+// decodeRawValueAlias 方法为设置了"alias"标签的 RawValue 字段解码，结果尽可能直接引用输入数据。
+func decodeRawValueAlias(s *Stream, val reflect.Value) error {
+	r, err := s.RawNoCopy()
+	if err != nil {
+		return wrapStreamError(err, val.Type())
 	}
 	val.SetBytes(r)
 	return nil
@@ -240,11 +434,61 @@ func decodeUint(s *Stream, val reflect.Value) error {
 	return nil
 }
 
+// makeSignedDecoder ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSignedDecoder 方法为设置了"signed"标签的字段生成解码器，与 makeSignedWriter 相对应。
+func makeSignedDecoder(typ reflect.Type) (decoder, error) {
+	switch {
+	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
+		return decodeSignedBigIntPtr, nil
+	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
+		return decodeSignedBigIntNoPtr, nil
+	case isInt(typ.Kind()):
+		return decodeInt, nil
+	default:
+		return nil, fmt.Errorf("rlp: type %v does not support the \"signed\" tag", typ)
+	}
+}
+
+// decodeInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeInt 方法实现了 decoder 函数句柄，读取stream底层经过zig-zag变换的输入，将其解码为有符号整数。
+func decodeInt(s *Stream, val reflect.Value) error {
+	num, err := s.int(val.Type().Bits())
+	if err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	val.SetInt(num)
+	return nil
+}
+
+// decodeSignedBigIntPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeSignedBigIntPtr 方法与 decodeBigIntPtr 方法类似，区别在于它会对解码结果做zig-zag逆变换，因此可以得到负数。
+func decodeSignedBigIntPtr(s *Stream, val reflect.Value) error {
+	x := val.Interface().(*big.Int)
+	if x == nil {
+		x = new(big.Int)
+		val.Set(reflect.ValueOf(x))
+	}
+	if err := s.decodeSignedBigInt(x); err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	return nil
+}
+
+// decodeSignedBigIntNoPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeSignedBigIntNoPtr 方法与 decodeBigIntNoPtr 方法类似，区别在于它会对解码结果做zig-zag逆变换。
+func decodeSignedBigIntNoPtr(s *Stream, val reflect.Value) error {
+	return decodeSignedBigIntPtr(s, val.Addr())
+}
+
 // decodeBool ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
 // decodeBool 方法实现了 decoder 函数句柄，读取stream底层的输入，将其解码为bool类型。
 func decodeBool(s *Stream, val reflect.Value) error {
-	b, err := s.bool()
+	b, err := s.Bool()
 	if err != nil {
 		return wrapStreamError(err, val.Type())
 	}
@@ -262,6 +506,9 @@ func makeListDecoder(typ reflect.Type, tag rlpstruct.Tag) (decoder, error) {
 		if typ.Kind() == reflect.Array {
 			return decodeByteArray, nil
 		}
+		if tag.Alias {
+			return decodeByteSliceAlias, nil
+		}
 		return decodeByteSlice, nil
 	}
 	// 如果是非字节数组或者字节切片，就要根据数组和切片中存储的数据类型来生成对应的解码器了
@@ -287,6 +534,86 @@ func makeListDecoder(typ reflect.Type, tag rlpstruct.Tag) (decoder, error) {
 	return d, nil
 }
 
+// makeMapDecoder ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeMapDecoder 方法为map类型生成解码器，它是 makeMapWriter 的逆过程：输入必须是由[key, value]键值对组成的列表，
+// 并且键值对必须按照key的编码结果严格升序排列，遇到重复的key或者顺序不对的key都会返回错误，这样才能保证每个map只有唯一
+// 的一种编码形式。解码结果总是一个新创建的map，目标位置原有的map不会被修改。
+func makeMapDecoder(typ reflect.Type) (decoder, error) {
+	if !isOrderableMapKey(typ.Key()) {
+		return nil, fmt.Errorf("rlp: map key type %v is not orderable", typ.Key())
+	}
+	keyInfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tag{})
+	if keyInfo.decoderErr != nil {
+		return nil, keyInfo.decoderErr
+	}
+	if keyInfo.writerErr != nil {
+		return nil, keyInfo.writerErr
+	}
+	elemInfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tag{})
+	if elemInfo.decoderErr != nil {
+		return nil, elemInfo.decoderErr
+	}
+	d := func(stream *Stream, value reflect.Value) error {
+		if _, err := stream.ListStart(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		m := reflect.MakeMap(typ)
+		keyBuf := encBufferPool.Get().(*encBuffer)
+		defer encBufferPool.Put(keyBuf)
+		var prevKey []byte
+		for i := 0; ; i++ {
+			ctx := fmt.Sprint("[", i, "]")
+			if _, err := stream.ListStart(); err == EOL {
+				break
+			} else if err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			// 每个键值对都会分配一个key和一个value
+			if err := stream.alloc(uint64(typ.Key().Size()) + uint64(typ.Elem().Size())); err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			key := reflect.New(typ.Key()).Elem()
+			if err := keyInfo.decoder(stream, key); err == EOL {
+				return addErrorContext(&DecodeError{msg: "too few elements", Type: typ, err: EOL}, ctx)
+			} else if err != nil {
+				return addErrorContext(err, ctx+".key")
+			}
+			// 解码过程保证了输入是规范的编码，所以对key重新编码就能得到它在输入里的编码形式
+			keyBuf.reset()
+			if err := keyInfo.writer(key, keyBuf); err != nil {
+				return err
+			}
+			keyEnc := keyBuf.makeBytes()
+			if prevKey != nil {
+				switch c := bytes.Compare(prevKey, keyEnc); {
+				case c == 0:
+					return addErrorContext(wrapStreamError(ErrMapKeyDuplicate, typ), ctx)
+				case c > 0:
+					return addErrorContext(wrapStreamError(ErrMapKeyOrder, typ), ctx)
+				}
+			}
+			prevKey = keyEnc
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := elemInfo.decoder(stream, elem); err == EOL {
+				return addErrorContext(&DecodeError{msg: "too few elements", Type: typ, err: EOL}, ctx)
+			} else if err != nil {
+				return addErrorContext(err, ctx+".value")
+			}
+			if err := stream.ListEnd(); err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			m.SetMapIndex(key, elem)
+		}
+		if err := stream.ListEnd(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		value.Set(m)
+		return nil
+	}
+	return d, nil
+}
+
 // makeStructDecoder ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
 // makeStructDecoder
@@ -302,11 +629,11 @@ func makeStructDecoder(typ reflect.Type) (decoder, error) {
 		}
 	}
 	var d decoder = func(stream *Stream, value reflect.Value) error {
-		if _, err = stream.ListStart(); err != nil {
+		if _, err := stream.ListStart(); err != nil {
 			return wrapStreamError(err, typ)
 		}
 		for i, f := range fields {
-			err = f.info.decoder(stream, value.Field(f.index))
+			err := f.info.decoder(stream, value.Field(f.index))
 			if err == EOL {
 				if f.optional {
 					// optional后面的字段都设置为零值
@@ -317,7 +644,7 @@ func makeStructDecoder(typ reflect.Type) (decoder, error) {
 					break
 				}
 				// 列表里面的数据读完了，但是结构体里的数据还没填充完，说明rlp编码数据太少了
-				return &decodeError{msg: "too few elements", typ: typ}
+				return &DecodeError{msg: "too few elements", Type: typ, err: EOL}
 			} else if err != nil {
 				return addErrorContext(err, "."+typ.Field(f.index).Name)
 			}
@@ -350,6 +677,9 @@ func makeSimplePtrDecoder(eTyp reflect.Type, info *typeInfo) decoder {
 	return func(stream *Stream, value reflect.Value) error {
 		newVal := value
 		if value.IsNil() {
+			if err := stream.alloc(uint64(eTyp.Size())); err != nil {
+				return wrapStreamError(err, reflect.PtrTo(eTyp))
+			}
 			newVal = reflect.New(eTyp)
 		}
 		if err := info.decoder(stream, newVal.Elem()); err == nil {
@@ -377,7 +707,7 @@ func makeNilPtrDecoder(eTyp reflect.Type, info *typeInfo, tag rlpstruct.Tag) dec
 		}
 		if kind != Byte && size == 0 {
 			if kind != nilKind {
-				return &decodeError{msg: fmt.Sprintf("wrong kind of empty value (got %v, want %v)", kind, nilKind), typ: typ}
+				return &DecodeError{msg: fmt.Sprintf("wrong kind of empty value (got %v, want %v)", kind, nilKind), Type: typ, Expected: nilKind, expectedSet: true}
 			}
 			stream.kind = -1
 			value.Set(nilPtr)
@@ -385,6 +715,9 @@ func makeNilPtrDecoder(eTyp reflect.Type, info *typeInfo, tag rlpstruct.Tag) dec
 		}
 		newVal := value
 		if value.IsNil() {
+			if err := stream.alloc(uint64(eTyp.Size())); err != nil {
+				return wrapStreamError(err, typ)
+			}
 			newVal = reflect.New(eTyp)
 		}
 		if err = info.decoder(stream, newVal.Elem()); err == nil {
@@ -406,7 +739,7 @@ func decodeInterface(s *Stream, val reflect.Value) error {
 	}
 	kind, _, err := s.Kind()
 	if err != nil {
-		return err
+		return wrapStreamError(err, val.Type())
 	}
 	if kind == List {
 		slice := reflect.New(reflect.TypeOf([]interface{}{})).Elem()
@@ -417,7 +750,7 @@ func decodeInterface(s *Stream, val reflect.Value) error {
 	} else {
 		b, err := s.Bytes()
 		if err != nil {
-			return err
+			return wrapStreamError(err, val.Type())
 		}
 		val.Set(reflect.ValueOf(b))
 	}
@@ -443,33 +776,43 @@ func decodeByteSlice(s *Stream, val reflect.Value) error {
 	return nil
 }
 
+// decodeByteSliceAlias 方法为设置了"alias"标签的字节切片字段解码，结果尽可能直接引用输入数据。
+func decodeByteSliceAlias(s *Stream, val reflect.Value) error {
+	b, err := s.BytesNoCopy()
+	if err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	val.SetBytes(b)
+	return nil
+}
+
 // decodeByteArray ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
 // decodeByteArray
 func decodeByteArray(s *Stream, val reflect.Value) error {
 	kind, size, err := s.Kind()
 	if err != nil {
-		return err
+		return wrapStreamError(err, val.Type())
 	}
 	slice := byteArrayBytes(val, val.Len())
 	switch kind {
 	case Byte:
 		if len(slice) == 0 {
-			return &decodeError{msg: "input string too long", typ: val.Type()}
+			return &DecodeError{msg: "input string too long", Type: val.Type()}
 		} else if len(slice) > 1 {
-			return &decodeError{msg: "input string too short", typ: val.Type()}
+			return &DecodeError{msg: "input string too short", Type: val.Type()}
 		}
 		slice[0] = s.byteVal
 		s.kind = -1
 	case String:
 		if uint64(len(slice)) < size {
-			return &decodeError{msg: "input string too long", typ: val.Type()}
+			return &DecodeError{msg: "input string too long", Type: val.Type()}
 		}
 		if uint64(len(slice)) > size {
-			return &decodeError{msg: "input string too short", typ: val.Type()}
+			return &DecodeError{msg: "input string too short", Type: val.Type()}
 		}
 		if err = s.readFull(slice); err != nil {
-			return err
+			return wrapStreamError(err, val.Type())
 		}
 		if size == 1 && slice[0] < 0x80 {
 			return wrapStreamError(ErrCanonSize, val.Type())
@@ -497,7 +840,7 @@ func decodeListArray(s *Stream, val reflect.Value, elemDec decoder) error {
 		}
 	}
 	if i < length {
-		return &decodeError{msg: "input list has too few elements", typ: val.Type()}
+		return &DecodeError{msg: "input list has too few elements", Type: val.Type(), err: EOL}
 	}
 	// 如果此时EC部分还有数据没有被读取完毕，则ListEnd方法会报错
 	return wrapStreamError(s.ListEnd(), val.Type())
@@ -514,6 +857,9 @@ func decodeSliceElems(s *Stream, val reflect.Value, elemDec decoder) error {
 			if newCap < 4 {
 				newCap = 4
 			}
+			if err := s.alloc(uint64(newCap) * uint64(val.Type().Elem().Size())); err != nil {
+				return wrapStreamError(err, val.Type())
+			}
 			newVal := reflect.MakeSlice(val.Type(), val.Len(), newCap)
 			reflect.Copy(newVal, val)
 			val.Set(newVal)
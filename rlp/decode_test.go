@@ -3,9 +3,11 @@ package rlp
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
 	"strings"
@@ -110,6 +112,84 @@ func TestStreamList(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestStreamIterate(t *testing.T) {
+	// 逐个解码列表里的元素
+	s := NewStream(bytes.NewReader(unhex("c80102030405060708")), 0)
+	var got []uint64
+	err := s.Iterate(func(i int, elem *Stream) error {
+		v, err := elem.Uint64()
+		assert.Equal(t, len(got), i)
+		got = append(got, v)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, got)
+
+	// 没有被消费的元素会被自动跳过，不影响列表后面的数据
+	s = NewStream(bytes.NewReader(unhex("CC CA 01 83616263 C20203 05 80 07")), 0)
+	_, err = s.ListStart()
+	assert.Nil(t, err)
+	var kinds []Kind
+	err = s.Iterate(func(i int, elem *Stream) error {
+		kind, _, err := elem.Kind()
+		kinds = append(kinds, kind)
+		if i == 3 {
+			// 消费当前元素之后，再去查看下一个元素的类型，下一个元素不应该被跳过
+			if _, err := elem.Uint64(); err != nil {
+				return err
+			}
+			_, _, err = elem.Kind()
+		}
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []Kind{Byte, String, List, Byte, String}, kinds)
+	v, err := s.Uint64()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), v)
+	assert.Nil(t, s.ListEnd())
+
+	// 嵌套的列表也可以继续迭代
+	s = NewStream(bytes.NewReader(unhex("C6C20102C20304")), 0)
+	var sum uint64
+	err = s.Iterate(func(i int, elem *Stream) error {
+		return elem.Iterate(func(j int, elem *Stream) error {
+			v, err := elem.Uint64()
+			sum += v
+			return err
+		})
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), sum)
+
+	// 回调函数返回的错误会被原样返回
+	stop := errors.New("stop")
+	s = NewStream(bytes.NewReader(unhex("C3010203")), 0)
+	err = s.Iterate(func(i int, elem *Stream) error {
+		if i == 1 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+
+	// 回调函数不能改变列表的嵌套深度
+	s = NewStream(bytes.NewReader(unhex("C3C20102")), 0)
+	err = s.Iterate(func(i int, elem *Stream) error {
+		_, err := elem.ListStart()
+		return err
+	})
+	assert.Equal(t, errIterateDepth, err)
+
+	// 依然受到输入长度和列表大小的限制
+	s = NewStream(bytes.NewReader(unhex("C3010203")), 2)
+	assert.Equal(t, ErrValueTooLarge, s.Iterate(func(int, *Stream) error { return nil }))
+	s = NewStream(bytes.NewReader(unhex("C283616263")), 0)
+	assert.Equal(t, ErrElemTooLarge, s.Iterate(func(int, *Stream) error { return nil }))
+	s = NewStream(bytes.NewReader(unhex("01")), 0)
+	assert.Equal(t, ErrExpectedList, s.Iterate(func(int, *Stream) error { return nil }))
+}
+
 func TestStreamRaw(t *testing.T) {
 	tests := []struct {
 		intput string
@@ -171,6 +251,96 @@ func TestStreamReadBytes(t *testing.T) {
 	}
 }
 
+func TestStreamTypedReads(t *testing.T) {
+	tests := []struct {
+		input string
+		read  string
+		value interface{}
+		err   string
+	}{
+		{input: "80", read: "Bool", value: false},
+		{input: "01", read: "Bool", value: true},
+		{input: "02", read: "Bool", err: "rlp: invalid boolean value: 2"},
+		{input: "8101", read: "Bool", err: "rlp: non-canonical size information"},
+		{input: "c0", read: "Bool", err: "rlp: expected String or Byte"},
+
+		{input: "80", read: "Uint8", value: uint8(0)},
+		{input: "81ff", read: "Uint8", value: uint8(0xff)},
+		{input: "820100", read: "Uint8", err: "rlp: uint overflow"},
+		{input: "8100", read: "Uint8", err: "rlp: non-canonical size information"},
+		{input: "82ffff", read: "Uint16", value: uint16(0xffff)},
+		{input: "83010000", read: "Uint16", err: "rlp: uint overflow"},
+		{input: "820001", read: "Uint16", err: "rlp: non-canonical integer format"},
+		{input: "84ffffffff", read: "Uint32", value: uint32(0xffffffff)},
+		{input: "850100000000", read: "Uint32", err: "rlp: uint overflow"},
+
+		{input: "80", read: "BigInt", value: big.NewInt(0)},
+		{input: "820400", read: "BigInt", value: big.NewInt(1024)},
+		{input: "89010000000000000000", read: "BigInt", value: new(big.Int).Lsh(big.NewInt(1), 64)},
+		{input: "820004", read: "BigInt", err: "rlp: non-canonical integer format"},
+		{input: "c0", read: "BigInt", err: "rlp: expected String or Byte"},
+
+		{input: "80", read: "ReadUint256", value: U256{}},
+		{input: "89010000000000000000", read: "ReadUint256", value: U256{0, 1}},
+		{input: "820004", read: "ReadUint256", err: "rlp: non-canonical integer format"},
+		{input: "a1" + strings.Repeat("01", 33), read: "ReadUint256", err: "rlp: uint overflow"},
+	}
+
+	for _, test := range tests {
+		name := fmt.Sprintf("input_%s/%s", test.input, test.read)
+		t.Run(name, func(t *testing.T) {
+			s := NewStream(bytes.NewReader(unhex(test.input)), 0)
+			var (
+				value interface{}
+				err   error
+			)
+			switch test.read {
+			case "Bool":
+				value, err = s.Bool()
+			case "Uint8":
+				value, err = s.Uint8()
+			case "Uint16":
+				value, err = s.Uint16()
+			case "Uint32":
+				value, err = s.Uint32()
+			case "BigInt":
+				value, err = s.BigInt()
+			case "ReadUint256":
+				var z U256
+				err = s.ReadUint256(&z)
+				value = z
+			}
+			if test.err == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, test.value, value)
+			} else if assert.NotNil(t, err) {
+				assert.Equal(t, test.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestStreamMoreDataInList(t *testing.T) {
+	s := NewStream(bytes.NewReader(unhex("C3 01 C102")), 0)
+	assert.False(t, s.MoreDataInList())
+	_, err := s.ListStart()
+	assert.Nil(t, err)
+	assert.True(t, s.MoreDataInList())
+	_, err = s.Uint8()
+	assert.Nil(t, err)
+	assert.True(t, s.MoreDataInList())
+	_, err = s.ListStart()
+	assert.Nil(t, err)
+	assert.True(t, s.MoreDataInList())
+	_, err = s.Uint8()
+	assert.Nil(t, err)
+	assert.False(t, s.MoreDataInList())
+	assert.Nil(t, s.ListEnd())
+	assert.False(t, s.MoreDataInList())
+	assert.Nil(t, s.ListEnd())
+	assert.False(t, s.MoreDataInList())
+}
+
 func TestDecodeErrors(t *testing.T) {
 	r := bytes.NewReader(nil)
 
@@ -349,6 +519,130 @@ func TestDecodeBigInt(t *testing.T) {
 	}
 }
 
+func TestDecodeSigned(t *testing.T) {
+	var decodeTests = []decodeTest{
+		{input: "C480808080", ptr: new(signedFields), value: signedFields{C: big.NewInt(0)}},
+		{input: "D00181C882025789020000000000000000", ptr: new(signedFields), value: signedFields{A: -1, B: 100, C: big.NewInt(-300), D: *new(big.Int).Lsh(big.NewInt(1), 64)}},
+		{input: "CD81FF88FFFFFFFFFFFFFFFF0280", ptr: new(signedFields), value: signedFields{A: -128, B: math.MinInt64, C: big.NewInt(1)}},
+		{input: "C5820100808080", ptr: new(signedFields), error: "rlp: input string too long for int8, decoding into (rlp.signedFields).A"},
+		{input: "C5808200018080", ptr: new(signedFields), error: "rlp: non-canonical integer (leading zero bytes) for int64, decoding into (rlp.signedFields).B"},
+		{input: "C480800080", ptr: new(signedFields), error: "rlp: non-canonical integer (leading zero bytes) for *big.Int, decoding into (rlp.signedFields).C"},
+		{input: "C5808081058080", ptr: new(signedFields), error: "rlp: non-canonical size information for *big.Int, decoding into (rlp.signedFields).C"},
+		{input: "C101", ptr: new(signedUintField), error: `rlp: invalid struct tag "signed" for rlp.signedUintField.A (tag "signed" is only allowed to be set on the signed integer or big.Int type field)`},
+	}
+	for i, test := range decodeTests {
+		runD(t, fd, test, i)
+	}
+}
+
+func TestDecodeMap(t *testing.T) {
+	var decodeTests = []decodeTest{
+		{input: "C0", ptr: new(map[string]uint), value: map[string]uint{}},
+		{input: "C6C26101C26202", ptr: new(map[string]uint), value: map[string]uint{"a": 1, "b": 2}},
+		{input: "CAC20161C28080C381C878", ptr: new(map[uint]string), value: map[uint]string{200: "x", 0: "", 1: "a"}},
+		{input: "C5C482010201", ptr: new(map[[2]byte]uint), value: map[[2]byte]uint{{1, 2}: 1}},
+		{input: "C6C561C3C26201", ptr: new(map[string]map[string]uint), value: map[string]map[string]uint{"a": {"b": 1}}},
+		{input: "C6C26202C26101", ptr: new(map[string]uint), error: "rlp: map keys not in canonical order for map[string]uint, decoding into (map[string]uint)[1]"},
+		{input: "C6C26101C26102", ptr: new(map[string]uint), error: "rlp: duplicate map key for map[string]uint, decoding into (map[string]uint)[1]"},
+		{input: "C2C161", ptr: new(map[string]uint), error: "rlp: too few elements for map[string]uint, decoding into (map[string]uint)[0]"},
+		{input: "C4C3610102", ptr: new(map[string]uint), error: "rlp: input list has too many elements for map[string]uint, decoding into (map[string]uint)[0]"},
+		{input: "C3C2C001", ptr: new(map[uint]uint), error: "rlp: expected input string or byte for uint, decoding into (map[uint]uint)[0].key"},
+		{input: "C3C201C0", ptr: new(map[uint]uint), error: "rlp: expected input string or byte for uint, decoding into (map[uint]uint)[0].value"},
+		{input: "C26101", ptr: new(map[string]uint), error: "rlp: expected input list for map[string]uint, decoding into (map[string]uint)[0]"},
+		{input: "01", ptr: new(map[string]uint), error: "rlp: expected input list for map[string]uint"},
+		{input: "C0", ptr: new(map[int]bool), error: "rlp: map key type int is not orderable"},
+	}
+	for i, test := range decodeTests {
+		runD(t, fd, test, i)
+	}
+
+	// 对 *big.Int 作为key的map进行往返测试，由于key是指针，无法直接用 reflect.DeepEqual 比较
+	bz, err := EncodeToBytes(map[*big.Int]bool{big.NewInt(1): true, big.NewInt(128): false})
+	assert.Nil(t, err)
+	var m map[*big.Int]bool
+	assert.Nil(t, DecodeBytes(bz, &m))
+	assert.Len(t, m, 2)
+	for k, v := range m {
+		assert.Equal(t, k.Uint64() == 1, v)
+	}
+}
+
+func TestDecodeU256(t *testing.T) {
+	var decodeTests = []decodeTest{
+		{input: "80", ptr: new(U256), value: U256{}},
+		{input: "7F", ptr: new(U256), value: U256{127}},
+		{input: "820400", ptr: new(U256), value: U256{1024}},
+		{input: "89010000000000000000", ptr: new(U256), value: U256{0, 1}},
+		{input: "A0FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", ptr: new(U256), value: U256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}},
+		{input: "820400", ptr: new(*U256), value: NewU256(1024)},
+		{input: "C3808080", ptr: new(u256Fields), value: u256Fields{B: new(U256)}},
+		{input: "A1010000000000000000000000000000000000000000000000000000000000000000", ptr: new(U256), error: "rlp: input string too long for rlp.U256"},
+		{input: "8105", ptr: new(U256), error: "rlp: non-canonical size information for rlp.U256"},
+		{input: "820004", ptr: new(U256), error: "rlp: non-canonical integer (leading zero bytes) for rlp.U256"},
+		{input: "C0", ptr: new(U256), error: "rlp: expected input string or byte for rlp.U256"},
+		{input: "C3800080", ptr: new(u256Fields), error: "rlp: non-canonical integer (leading zero bytes) for *rlp.U256, decoding into (rlp.u256Fields).B"},
+	}
+	for i, test := range decodeTests {
+		runD(t, fd, test, i)
+	}
+}
+
+type errPathTx struct {
+	To    [2]byte
+	Value uint
+}
+
+type errPathBlock struct {
+	Number uint
+	Txs    []errPathTx
+}
+
+func TestDecodeErrorLocation(t *testing.T) {
+	tests := []struct {
+		input    string
+		ptr      interface{}
+		offset   uint64
+		kind     Kind
+		expected Kind
+		path     string
+		cause    error
+	}{
+		{input: "CE 01 CC C4820102 02 C6820304 8200FF", ptr: new(errPathBlock), offset: 12, kind: String, expected: String, path: "errPathBlock.Txs[1].Value", cause: ErrCanonInt},
+		{input: "C2 01 05", ptr: new(errPathBlock), offset: 2, kind: Byte, expected: List, path: "errPathBlock.Txs", cause: ErrExpectedList},
+		{input: "C6 01 C4 C3820102", ptr: new(errPathBlock), offset: 7, kind: List, expected: List, path: "errPathBlock.Txs[0]", cause: EOL},
+		{input: "C8 01 C6 C5820102 02 03", ptr: new(errPathBlock), offset: 8, kind: List, expected: List, path: "errPathBlock.Txs[0]", cause: errNotAtEOL},
+		{input: "C5 C4 C26201 02", ptr: new(map[string]uint), offset: 2, kind: List, expected: String, path: "map[string]uint[0].key", cause: ErrExpectedString},
+		{input: "C0", ptr: new(uint), offset: 0, kind: List, expected: String, path: "", cause: ErrExpectedString},
+		{input: "C7 01 C5 C4820102 83", ptr: new(errPathBlock), offset: 7, kind: String, expected: String, path: "errPathBlock.Txs[0].Value", cause: ErrElemTooLarge},
+		{input: "C2 01 C5", ptr: new(errPathBlock), offset: 2, kind: List, expected: List, path: "errPathBlock.Txs", cause: ErrElemTooLarge},
+		{input: "83 01", ptr: new([3]byte), offset: 0, kind: String, expected: String, path: "", cause: ErrValueTooLarge},
+	}
+	for i, test := range tests {
+		err := DecodeBytes(unhex(test.input), test.ptr)
+		var decErr *DecodeError
+		if !assert.True(t, errors.As(err, &decErr), "test %d: got error %v", i, err) {
+			continue
+		}
+		assert.Equal(t, test.offset, decErr.Offset, "test %d: offset", i)
+		assert.Equal(t, test.kind, decErr.Kind, "test %d: kind", i)
+		assert.Equal(t, test.expected, decErr.Expected, "test %d: expected kind", i)
+		assert.Equal(t, test.path, decErr.Path(), "test %d: path", i)
+		assert.True(t, errors.Is(err, test.cause), "test %d: cause", i)
+	}
+
+	// 偏移量是相对于整个输入的，与前面已经解码的值无关
+	s := NewStream(bytes.NewReader(unhex("8363617401 C2 01 05")), 0)
+	var str string
+	var u uint
+	assert.Nil(t, s.Decode(&str))
+	assert.Nil(t, s.Decode(&u))
+	assert.Equal(t, uint64(5), s.InputOffset())
+	var decErr *DecodeError
+	assert.True(t, errors.As(s.Decode(new(errPathBlock)), &decErr))
+	assert.Equal(t, uint64(7), decErr.Offset)
+	assert.Equal(t, []string{".Txs"}, decErr.PathElems())
+}
+
 type bigIntStruct struct {
 	I *big.Int
 	B string
@@ -503,7 +797,7 @@ func TestDecodeInterface(t *testing.T) {
 		{input: "C0", ptr: new(interface{}), value: []interface{}{}},
 		{input: "C50183040404", ptr: new(interface{}), value: []interface{}{[]byte{1}, []byte{4, 4, 4}}},
 		{input: "C3010203", ptr: new([]io.Reader), error: "rlp: type io.Reader is not RLP-serializable"},
-		{input: "c330f9c030f93030ce3030303030303030bd303030303030", ptr: new(interface{}), error: "rlp: element is larger than containing list"},
+		{input: "c330f9c030f93030ce3030303030303030bd303030303030", ptr: new(interface{}), error: "rlp: element is larger than containing list for interface {}, decoding into (interface {})[1]"},
 	}
 	for i, test := range decodeTests {
 		runD(t, fd, test, i)
@@ -543,3 +837,187 @@ func TestBigIntPtr(t *testing.T) {
 	b := rTyp.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{})))
 	t.Log(b)
 }
+
+// nestedLists 返回depth层嵌套的空列表的编码结果，例如depth为2时返回c1c0。
+func nestedLists(depth int) []byte {
+	enc := []byte{0xC0}
+	for i := 1; i < depth; i++ {
+		buf := make([]byte, headSize(uint64(len(enc))), headSize(uint64(len(enc)))+len(enc))
+		putHead(buf, 0xC0, 0xF7, uint64(len(enc)))
+		enc = append(buf, enc...)
+	}
+	return enc
+}
+
+// repeatedList 返回由n个elem组成的列表的编码结果。
+func repeatedList(elem []byte, n int) []byte {
+	content := bytes.Repeat(elem, n)
+	head := make([]byte, headSize(uint64(len(content))))
+	putHead(head, 0xC0, 0xF7, uint64(len(content)))
+	return append(head, content...)
+}
+
+func TestDecodeOptions(t *testing.T) {
+	type bigElem struct {
+		A [64]uint64 `rlp:"optional"`
+	}
+	type bigPtrElem struct {
+		A uint
+		B [4096]byte `rlp:"optional"`
+	}
+	// 100个按key升序排列的键值对[i, c0]，每个键值对只占3个字节
+	var mapEntries []byte
+	for i := 1; i <= 100; i++ {
+		mapEntries = append(mapEntries, 0xC2, byte(i), 0xC0)
+	}
+	mapInput := repeatedList(mapEntries, 1)
+	tests := []struct {
+		name  string
+		input []byte
+		ptr   interface{}
+		opts  DecodeOptions
+		err   error
+	}{
+		// 2000层嵌套只需要不到8KB的输入，却会导致同样深度的递归调用
+		{name: "depth", input: nestedLists(2000), ptr: new(interface{}), opts: DecodeOptions{MaxDepth: 64}, err: ErrDepthLimit},
+		{name: "depth ok", input: nestedLists(64), ptr: new(interface{}), opts: DecodeOptions{MaxDepth: 64}},
+		{name: "list length", input: repeatedList([]byte{0x80}, 100000), ptr: new([][]byte), opts: DecodeOptions{MaxListLength: 1000}, err: ErrListLengthLimit},
+		{name: "list length ok", input: repeatedList([]byte{0x80}, 1000), ptr: new([][]byte), opts: DecodeOptions{MaxListLength: 1000}},
+		{name: "list length struct", input: unhex("c3 01 02 03"), ptr: new(struct{ A, B, C uint8 }), opts: DecodeOptions{MaxListLength: 2}, err: ErrListLengthLimit},
+		{name: "string size", input: append(unhex("b90400"), make([]byte, 1024)...), ptr: new([]byte), opts: DecodeOptions{MaxStringSize: 1023}, err: ErrStringSizeLimit},
+		{name: "string size ok", input: append(unhex("b90400"), make([]byte, 1024)...), ptr: new([]byte), opts: DecodeOptions{MaxStringSize: 1024}},
+		{name: "string size raw", input: append(unhex("b90400"), make([]byte, 1024)...), ptr: new(RawValue), opts: DecodeOptions{MaxStringSize: 1023}, err: ErrStringSizeLimit},
+		// 每个c0只占1个字节，却会让切片扩容出512字节的元素
+		{name: "alloc slice", input: repeatedList([]byte{0xC0}, 1000), ptr: new([]bigElem), opts: DecodeOptions{MaxAlloc: 64 * 1024}, err: ErrAllocLimit},
+		{name: "alloc strings", input: repeatedList(append(unhex("b8c8"), make([]byte, 200)...), 100), ptr: new([]string), opts: DecodeOptions{MaxAlloc: 10000}, err: ErrAllocLimit},
+		{name: "alloc big int", input: append(unhex("b848 01"), make([]byte, 71)...), ptr: new(*big.Int), opts: DecodeOptions{MaxAlloc: 71}, err: ErrAllocLimit},
+		// 每个c180只占2个字节，却会让指针指向一个新分配的4KB结构体
+		{name: "alloc pointers", input: repeatedList(unhex("c180"), 100), ptr: new([]*bigPtrElem), opts: DecodeOptions{MaxAlloc: 8192}, err: ErrAllocLimit},
+		{name: "alloc map", input: mapInput, ptr: new(map[uint64]bigElem), opts: DecodeOptions{MaxAlloc: 8192}, err: ErrAllocLimit},
+		{name: "alloc ok", input: repeatedList(append(unhex("b8c8"), make([]byte, 200)...), 10), ptr: new([]string), opts: DecodeOptions{MaxAlloc: 10000}},
+	}
+	for _, test := range tests {
+		err := DecodeBytesWithOptions(test.input, test.ptr, test.opts)
+		if test.err == nil {
+			assert.Nil(t, err, test.name)
+			continue
+		}
+		assert.True(t, errors.Is(err, test.err), "%s: got %v", test.name, err)
+		// 不设置限制时同样的输入可以被正常解码
+		assert.Nil(t, DecodeBytes(test.input, reflect.New(reflect.TypeOf(test.ptr).Elem()).Interface()), test.name)
+	}
+}
+
+func TestDecodeOptionsStream(t *testing.T) {
+	// 输入长度不受限制时，声明了4GB长度的字符串会在分配内存之前被拒绝
+	input := unhex("bb ffffffff 00")
+	var bz []byte
+	err := DecodeWithOptions(newPlainReader(input), &bz, DecodeOptions{MaxStringSize: 1 << 20})
+	assert.True(t, errors.Is(err, ErrStringSizeLimit), "%v", err)
+
+	// Iterate 同样受到列表长度和嵌套深度的限制
+	s := NewStreamWithOptions(bytes.NewReader(repeatedList([]byte{0x01}, 10)), 0, DecodeOptions{MaxListLength: 5})
+	n := 0
+	err = s.Iterate(func(i int, elem *Stream) error {
+		n++
+		return nil
+	})
+	assert.Equal(t, ErrListLengthLimit, err)
+	assert.Equal(t, 5, n)
+
+	s = NewStreamWithOptions(bytes.NewReader(nestedLists(3)), 0, DecodeOptions{MaxDepth: 2})
+	_, err = s.ListStart()
+	assert.Nil(t, err)
+	_, err = s.ListStart()
+	assert.Nil(t, err)
+	_, err = s.ListStart()
+	assert.Equal(t, ErrDepthLimit, err)
+
+	// Reset 会连同限制一起清除
+	s.Reset(bytes.NewReader(nestedLists(3)), 0)
+	var v interface{}
+	assert.Nil(t, s.Decode(&v))
+}
+
+// aliases 判断bz是否引用了input里的数据。
+func aliases(bz, input []byte) bool {
+	if len(bz) == 0 {
+		return false
+	}
+	for i := range input {
+		if &input[i] == &bz[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDecodeBytesNoCopy(t *testing.T) {
+	type receipt struct {
+		Status  []byte
+		Logs    RawValue
+		Bloom   []byte
+		Message string
+		Items   []interface{}
+	}
+	input := unhex("D3 01 C3820102 83AABBCC 83616263 C5 05 83DDEEFF")
+	var r receipt
+	assert.Nil(t, DecodeBytesNoCopy(input, &r))
+	assert.Equal(t, []byte{0x01}, r.Status)
+	assert.Equal(t, RawValue(unhex("C3820102")), r.Logs)
+	assert.Equal(t, []byte{0xAA, 0xBB, 0xCC}, r.Bloom)
+	assert.Equal(t, "abc", r.Message)
+	assert.Equal(t, []interface{}{[]byte{0x05}, []byte{0xDD, 0xEE, 0xFF}}, r.Items)
+	for _, bz := range [][]byte{r.Status, r.Logs, r.Bloom, r.Items[0].([]byte), r.Items[1].([]byte)} {
+		assert.True(t, aliases(bz, input), "%x", bz)
+		assert.Equal(t, len(bz), cap(bz))
+	}
+
+	// 修改输入会影响解码结果，而对解码结果调用append不会覆盖输入里后面的数据
+	input[len(input)-1] = 0x00
+	assert.Equal(t, []byte{0xDD, 0xEE, 0x00}, r.Items[1])
+	_ = append(r.Bloom, 0x11)
+	assert.Equal(t, byte(0x83), input[10])
+
+	// 与 DecodeBytes 一样检查规范编码和多余的数据
+	assert.Equal(t, ErrMoreThanOneValue, DecodeBytesNoCopy(unhex("8180 02"), new([]byte)))
+	assert.True(t, errors.Is(DecodeBytesNoCopy(unhex("8101"), new([]byte)), ErrCanonSize))
+
+	// 从 streamPool 里取出的 Stream 不能沿用上一次 DecodeBytesWithOptions 设置的限制
+	for i := 0; i < 10; i++ {
+		assert.True(t, errors.Is(DecodeBytesWithOptions(unhex("C3010203"), new([]uint), DecodeOptions{MaxListLength: 1}), ErrListLengthLimit))
+		var list []uint
+		assert.Nil(t, DecodeBytesNoCopy(unhex("C3010203"), &list))
+		assert.Equal(t, []uint{1, 2, 3}, list)
+	}
+}
+
+func TestDecodeAliasTag(t *testing.T) {
+	type aliased struct {
+		Data   []byte   `rlp:"alias"`
+		Raw    RawValue `rlp:"alias"`
+		Copied []byte
+	}
+	input := unhex("CB 83010203 C20405 83060708")
+	var v aliased
+	assert.Nil(t, DecodeBytes(input, &v))
+	assert.True(t, aliases(v.Data, input))
+	assert.True(t, aliases(v.Raw, input))
+	assert.False(t, aliases(v.Copied, input))
+	assert.Equal(t, aliased{Data: []byte{1, 2, 3}, Raw: unhex("C20405"), Copied: []byte{6, 7, 8}}, v)
+
+	// 被引用的数据不计入 DecodeOptions.MaxAlloc
+	assert.Nil(t, DecodeBytesWithOptions(input, &v, DecodeOptions{MaxAlloc: 3}))
+	assert.True(t, errors.Is(DecodeBytesWithOptions(input, &v, DecodeOptions{MaxAlloc: 2}), ErrAllocLimit))
+
+	// 输入不在内存里时只能复制
+	var w aliased
+	assert.Nil(t, Decode(bytes.NewReader(input), &w))
+	assert.False(t, aliases(w.Data, input))
+	assert.Equal(t, v, w)
+
+	_, err := cachedDecoder(reflect.TypeOf(struct {
+		A uint `rlp:"alias"`
+	}{}))
+	assert.EqualError(t, err, `rlp: invalid struct tag "alias" for struct { A uint "rlp:\"alias\"" }.A (tag "alias" is only allowed to be set on the byte slice type field)`)
+}
@@ -0,0 +1,185 @@
+package rlp
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// DumpOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DumpOptions 用来控制 Dump 方法的输出格式。
+type DumpOptions struct {
+	// Indent 每一层嵌套列表使用的缩进，为空的话默认使用两个空格
+	Indent string
+	// ShowOffsets 如果被设置为true，那么每个元素的注释里都会标出该元素在输入数据里的偏移量，例如"@12"
+	ShowOffsets bool
+}
+
+// Dump ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Dump 方法将任意的rlp编码数据bz以人类可读的文本形式写入到w里，bz里可以包含多个连续的编码值。列表用"["和"]"包裹起来，
+// 其中的元素按照嵌套深度进行缩进；字符串和单个字节以"0x"开头的16进制形式展示，如果它们全部由可打印的ASCII字符组成，
+// 则会在注释里同时给出其文本形式。例如[]interface{}{"cat", []uint{1, 1024}}的编码结果会被展示为：
+//
+//	[
+//	  0x636174  # "cat"
+//	  [
+//	    0x01
+//	    0x0400
+//	  ]
+//	]
+//
+// 与 Decode 不同，Dump 在遇到 readKind 会拒绝的非规范编码时（例如本该被编码成单个字节的值被编码成了长度为1的字符串）
+// 不会停下来，而是在注释里标出"non-canonical"并继续往下展示；只有当数据被截断或者长度信息超出了输入的范围时才会停止，
+// 此时已经展示的内容会保留，并在出错的位置写入一行错误注释，同时返回错误。"#"之后的内容都是注释，所以 Dump 的输出可以
+// 被 cmd/rlpdump 的 -reverse 模式重新组装成rlp编码数据。
+func Dump(w io.Writer, bz []byte, opts DumpOptions) error {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	d := &dumper{w: w, opts: opts}
+	err := d.dumpValues(bz, 0, 0)
+	if d.err != nil {
+		return d.err
+	}
+	return err
+}
+
+// dumper ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// dumper 在 Dump 的过程中维护输出目标和配置，err 记录第一次写入 w 时遇到的错误。
+type dumper struct {
+	w    io.Writer
+	opts DumpOptions
+	err  error
+}
+
+// dumpValues ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// dumpValues 方法逐个展示bz里的编码值，offset是bz[0]在原始输入里的偏移量，depth是当前的嵌套深度。
+func (d *dumper) dumpValues(bz []byte, offset, depth int) error {
+	for len(bz) > 0 {
+		kind, prefixSize, contentSize, note, err := dumpReadKind(bz)
+		if err != nil {
+			d.line(depth, fmt.Sprintf("# error at offset %d: %v", offset, err))
+			return fmt.Errorf("rlp: dump failed at offset %d: %w", offset, err)
+		}
+		var notes []string
+		if d.opts.ShowOffsets {
+			notes = append(notes, "@"+strconv.Itoa(offset))
+		}
+		content := bz[prefixSize : prefixSize+contentSize]
+		switch kind {
+		case List:
+			if note != "" {
+				notes = append(notes, note)
+			}
+			d.line(depth, "[", notes...)
+			err = d.dumpValues(content, offset+int(prefixSize), depth+1)
+			d.line(depth, "]")
+			if err != nil {
+				return err
+			}
+		default:
+			if isPrintableASCII(content) {
+				notes = append(notes, strconv.Quote(string(content)))
+			}
+			if note != "" {
+				notes = append(notes, note)
+			}
+			d.line(depth, fmt.Sprintf("0x%x", content), notes...)
+		}
+		size := int(prefixSize + contentSize)
+		bz = bz[size:]
+		offset += size
+	}
+	return nil
+}
+
+// line 方法按照给定的嵌套深度写入一行内容，notes 会以注释的形式跟在内容后面。
+func (d *dumper) line(depth int, text string, notes ...string) {
+	if d.err != nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(d.opts.Indent, depth))
+	b.WriteString(text)
+	if len(notes) > 0 {
+		b.WriteString("  # ")
+		b.WriteString(strings.Join(notes, ", "))
+	}
+	b.WriteString("\n")
+	_, d.err = io.WriteString(d.w, b.String())
+}
+
+// dumpReadKind ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// dumpReadKind 方法是 readKind 方法的宽松版本，它与 readKind 一样解析编码前缀，但是遇到非规范的编码时不会报错，而是通过
+// note 返回具体的原因，只有当数据被截断或者长度信息超出输入范围时才会返回错误。
+func dumpReadKind(bz []byte) (k Kind, prefixSize, contentSize uint64, note string, err error) {
+	if len(bz) == 0 {
+		return 0, 0, 0, "", io.ErrUnexpectedEOF
+	}
+	b := bz[0]
+	switch {
+	case b < 0x80:
+		k, prefixSize, contentSize = Byte, 0, 1
+	case b < 0xB8:
+		k, prefixSize, contentSize = String, 1, uint64(b-0x80)
+		if contentSize == 1 && len(bz) > 1 && bz[1] < 0x80 {
+			note = "non-canonical: single byte below 0x80 must be encoded as itself"
+		}
+	case b < 0xC0:
+		k, prefixSize = String, 1+uint64(b-0xB7)
+		contentSize, note, err = dumpReadSize(bz[1:], int(b-0xB7))
+	case b < 0xF8:
+		k, prefixSize, contentSize = List, 1, uint64(b-0xC0)
+	default:
+		k, prefixSize = List, 1+uint64(b-0xF7)
+		contentSize, note, err = dumpReadSize(bz[1:], int(b-0xF7))
+	}
+	if err != nil {
+		return 0, 0, 0, "", err
+	}
+	if contentSize > uint64(len(bz))-prefixSize {
+		return 0, 0, 0, "", ErrValueTooLarge
+	}
+	return k, prefixSize, contentSize, note, nil
+}
+
+// dumpReadSize ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// dumpReadSize 方法是 readSize 方法的宽松版本，它从bz里读取length个字节作为长度信息，长度信息带有前导零或者小于56的情况
+// 都会通过 note 指出来。
+func dumpReadSize(bz []byte, length int) (size uint64, note string, err error) {
+	if length > len(bz) {
+		return 0, "", io.ErrUnexpectedEOF
+	}
+	for _, b := range bz[:length] {
+		size = size<<8 | uint64(b)
+	}
+	switch {
+	case bz[0] == 0:
+		note = "non-canonical: size has leading zero bytes"
+	case size < 56:
+		note = "non-canonical: size below 56 must use the short form"
+	}
+	return size, note, nil
+}
+
+// isPrintableASCII 方法判断给定的字节切片是否非空且全部由可打印的ASCII字符组成。
+func isPrintableASCII(bz []byte) bool {
+	if len(bz) == 0 {
+		return false
+	}
+	for _, b := range bz {
+		if b < 0x20 || b > 0x7E {
+			return false
+		}
+	}
+	return true
+}
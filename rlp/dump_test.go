@@ -0,0 +1,63 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   DumpOptions
+		output string
+		err    error
+	}{
+		{
+			input:  "C9 83636174 C4 01 820400",
+			output: "[\n  0x636174  # \"cat\"\n  [\n    0x01\n    0x0400\n  ]\n]\n",
+		},
+		{
+			input:  "80 C0 05",
+			output: "0x\n[\n]\n0x05\n",
+		},
+		{
+			input:  "C5 83636174 7A",
+			opts:   DumpOptions{Indent: "\t", ShowOffsets: true},
+			output: "[  # @0\n\t0x636174  # @1, \"cat\"\n\t0x7a  # @5, \"z\"\n]\n",
+		},
+		{
+			// 非规范的编码会被标记出来，但不会中断展示
+			input:  "C5 8105 B801AA C0",
+			output: "[\n  0x05  # non-canonical: single byte below 0x80 must be encoded as itself\n  0xaa  # non-canonical: size below 56 must use the short form\n]\n[\n]\n",
+		},
+		{
+			input:  "B90000",
+			output: "0x  # non-canonical: size has leading zero bytes\n",
+		},
+		{
+			// 数据被截断时，已经展示的内容会保留下来
+			input:  "C5 01 C3 836162",
+			output: "[\n  0x01\n  [\n    # error at offset 3: rlp: value size exceeds available input length\n  ]\n]\n",
+			err:    ErrValueTooLarge,
+		},
+		{
+			input:  "B9",
+			output: "# error at offset 0: unexpected EOF\n",
+			err:    io.ErrUnexpectedEOF,
+		},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		err := Dump(&buf, unhex(test.input), test.opts)
+		if test.err == nil {
+			assert.Nil(t, err, "test %d", i)
+		} else {
+			assert.True(t, errors.Is(err, test.err), "test %d: got error %v", i, err)
+		}
+		assert.Equal(t, test.output, buf.String(), "test %d", i)
+	}
+}
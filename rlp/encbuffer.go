@@ -14,10 +14,11 @@ import (
 //
 // encBuffer 结构体被用于在编码数据时存储编码结果。
 type encBuffer struct {
-	str          []byte     // str 包含了除列表头之外的所有编码信息
-	lHeads       []listHead // 存储了所有列表头信息，官方源码的写法是"lheads"
-	lHeadsSize   int        // 官方源码写法是"lhsize"，表示所有头加一起的长度
-	auxiliaryBuf [9]byte    // 官方源码写法是"sizebuf"
+	str          []byte        // str 包含了除列表头之外的所有编码信息
+	lHeads       []listHead    // 存储了所有列表头信息，官方源码的写法是"lheads"
+	lHeadsSize   int           // 官方源码写法是"lhsize"，表示所有头加一起的长度
+	auxiliaryBuf [9]byte       // 官方源码写法是"sizebuf"
+	opts         EncodeOptions // 编码选项，见 EncodeOptions
 }
 
 // encBufferPool ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
@@ -42,10 +43,12 @@ func getEncBuffer() *encBuffer {
 //	buf.lHeadsSize = 0
 //	buf.str = buf.str[:0]
 //	buf.lHeads = buf.lHeads[:0]
+//	buf.opts = EncodeOptions{}
 func (buf *encBuffer) reset() {
 	buf.lHeadsSize = 0
 	buf.str = buf.str[:0]
 	buf.lHeads = buf.lHeads[:0]
+	buf.opts = EncodeOptions{}
 }
 
 // size ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
@@ -142,6 +145,17 @@ func (buf *encBuffer) writeUint64(i uint64) {
 	}
 }
 
+// writeInt64 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeInt64 方法接受一个64位的有符号整数作为输入参数，先对其进行zig-zag变换，得到一个无符号整数，然后调用 writeUint64
+// 方法将其编码到 encBuffer.str 里，例如：
+//   - 0：append(str, 0x80)
+//   - -1：append(str, 0x01)
+//   - 100：append(str, []byte{0x81, 0xC8})
+func (buf *encBuffer) writeInt64(i int64) {
+	buf.writeUint64(zigzag(i))
+}
+
 // writeBytes ♏ |作者：吴翔宇| 🍁 |日期：2022/11/4|
 //
 // writeBytes 方法接受一个字节切片bz，该方法的目的就是将字节切片bz编码到 encBuffer.str 里。当bz满足不同情况时，编码方式也不
@@ -201,6 +215,29 @@ func (buf *encBuffer) writeBigInt(i *big.Int) {
 	buf.str = append(buf.str, enc...)
 }
 
+// writeSignedBigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeSignedBigInt 方法接受一个可以是负数的大整数i，与 writeInt64 方法一样，先对其进行zig-zag变换：非负数i被映射为2i，
+// 负数i被映射为-2i-1，然后调用 writeBigInt 方法将变换后的结果编码到 encBuffer.str 里。
+func (buf *encBuffer) writeSignedBigInt(i *big.Int) {
+	buf.writeBigInt(zigzagBig(i))
+}
+
+// writeUint256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeUint256 方法将256位无符号整数z编码到 encBuffer.str 里，编码结果与值相同的 big.Int 一样。z最多只有32个字节，所以
+// 编码前缀总是0x80+字节数，整个过程不会分配额外的内存。
+func (buf *encBuffer) writeUint256(z *U256) {
+	if z.IsUint64() {
+		buf.writeUint64(z.Uint64())
+		return
+	}
+	n := z.ByteLen()
+	enc := z.Bytes32()
+	buf.str = append(buf.str, 0x80+byte(n))
+	buf.str = append(buf.str, enc[32-n:]...)
+}
+
 // encodeStringHeader ♏ |作者：吴翔宇| 🍁 |日期：2022/11/4|
 //
 // encodeStringHeader 方法接受一个整型size作为输入，顾名思义，该方法的作用就是在编码字符串数据时，将字符串的长度编码到 encBuffer.str
@@ -497,6 +534,29 @@ func (encBuf EncodeBuffer) WriteBigInt(i *big.Int) {
 	encBuf.buf.writeBigInt(i)
 }
 
+// WriteInt64 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// WriteInt64 方法接受一个64位的有符号整数，对其进行zig-zag变换之后编码到 EncodeBuffer.buf.str 里，编码结果与设置了
+// `rlp:"signed"`标签的有符号整数字段的编码结果一致。
+func (encBuf EncodeBuffer) WriteInt64(i int64) {
+	encBuf.buf.writeInt64(i)
+}
+
+// WriteSignedBigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// WriteSignedBigInt 方法接受一个可以是负数的大整数i，对其进行zig-zag变换之后编码到 EncodeBuffer.buf.str 里，编码结果与
+// 设置了`rlp:"signed"`标签的 *big.Int 字段的编码结果一致。
+func (encBuf EncodeBuffer) WriteSignedBigInt(i *big.Int) {
+	encBuf.buf.writeSignedBigInt(i)
+}
+
+// WriteUint256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// WriteUint256 方法将256位无符号整数z编码到 EncodeBuffer.buf.str 里，编码结果与 U256 类型字段的编码结果一致。
+func (encBuf EncodeBuffer) WriteUint256(z *U256) {
+	encBuf.buf.writeUint256(z)
+}
+
 // WriteBytes ♏ |作者：吴翔宇| 🍁 |日期：2022/11/5|
 //
 // WriteBytes 方法接受一个字节切片bz，该方法的目的就是将字节切片bz编码到 EncodeBuffer.buf.str 里。当bz满足不同情况时，编码
@@ -1,12 +1,16 @@
 package rlp
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
 	"io"
 	"math/big"
 	"reflect"
+	"runtime"
+	"sort"
+	"sync"
 )
 
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
@@ -57,6 +61,68 @@ func EncodeToReader(x interface{}) (size int, r io.Reader, err error) {
 	return buf.size(), &encReader{buf: buf}, nil
 }
 
+// EncodeOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodeOptions 定义了编码时的可选行为，零值表示与 Encode 完全相同的行为：
+//   - ParallelThreshold：元素个数不小于该值的切片（元素不是字节）会被分成若干段，交给多个goroutine同时编码，0表示不启用；
+//   - MaxWorkers：并行编码一个切片时最多使用多少个goroutine，0表示使用 runtime.GOMAXPROCS(0) 个。
+//
+// 并行编码的结果与顺序编码逐字节相同，只有在元素的编码本身开销较大（例如由很多结构体组成的切片）时才能带来收益。并行编码
+// 期间切片里的元素会被多个goroutine同时读取，所以自定义的 EncodeRLP 方法必须能够被并发调用。
+type EncodeOptions struct {
+	ParallelThreshold int
+	MaxWorkers        int
+}
+
+// workers 方法返回编码一个长度为length的切片时应该使用多少个goroutine，返回1表示顺序编码。
+func (opts EncodeOptions) workers(length int) int {
+	if opts.ParallelThreshold <= 0 || length < opts.ParallelThreshold {
+		return 1
+	}
+	n := opts.MaxWorkers
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	if n > length {
+		n = length
+	}
+	return n
+}
+
+// EncodeWithOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodeWithOptions 方法与 Encode 方法一样，但是编码过程使用opts给出的选项。
+func EncodeWithOptions(w io.Writer, x interface{}, opts EncodeOptions) error {
+	if buf := encBufferFromWriter(w); buf != nil {
+		prev := buf.opts
+		buf.opts = opts
+		defer func() { buf.opts = prev }()
+		return buf.encode(x)
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.opts = opts
+	if err := buf.encode(x); err != nil {
+		return err
+	}
+	return buf.writeTo(w)
+}
+
+// EncodeToBytesWithOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodeToBytesWithOptions 方法与 EncodeToBytes 方法一样，但是编码过程使用opts给出的选项，例如：
+//
+//	enc, err := rlp.EncodeToBytesWithOptions(receipts, rlp.EncodeOptions{ParallelThreshold: 1024})
+func EncodeToBytesWithOptions(x interface{}, opts EncodeOptions) ([]byte, error) {
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.opts = opts
+	if err := buf.encode(x); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
 
 // 定义 Encoder 接口
@@ -164,12 +230,20 @@ func putHead(buf []byte, smallTag, largeTag byte, size uint64) int {
 func makeWriter(typ reflect.Type, tag rlpstruct.Tag) (writer, error) {
 	kind := typ.Kind()
 	switch {
+	case tag.Signed:
+		// 设置了"signed"标签的字段需要先做zig-zag变换，所以必须放在最前面，避免 big.Int 被当成非负整数编码
+		return makeSignedWriter(typ)
 	case typ == rawValueType:
 		return writeRawValue, nil
 	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
 		return writeBigIntPtr, nil
 	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
 		return writeBigIntNoPtr, nil
+	case typ == reflect.PtrTo(u256Type):
+		return writeU256Ptr, nil
+	case typ == u256Type:
+		// U256 的底层类型是数组，必须放在数组的case之前，否则会被当成由无符号整数组成的列表进行编码
+		return writeU256NoPtr, nil
 	case kind == reflect.Pointer:
 		// 指针可能是指针的指针，因此我们需要递归地去发现该指针所指向的数据类型
 		return makePtrWriter(typ, tag)
@@ -191,6 +265,8 @@ func makeWriter(typ reflect.Type, tag rlpstruct.Tag) (writer, error) {
 		return makeSliceWriter(typ, tag)
 	case kind == reflect.Struct:
 		return makeStructWriter(typ)
+	case kind == reflect.Map:
+		return makeMapWriter(typ)
 	case kind == reflect.Interface:
 		return writeInterface, nil
 	default:
@@ -248,6 +324,57 @@ func writeUint(val reflect.Value, buf *encBuffer) error {
 	return nil
 }
 
+// makeSignedWriter ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSignedWriter 方法为设置了"signed"标签的字段生成编码器，这类字段只能是有符号整数、big.Int 或者 *big.Int，它们都会
+// 先经过zig-zag变换，再被当成无符号整数进行编码，编码规则如下：
+//
+//	0 -> 0x80，-1 -> 0x01，1 -> 0x02，-2 -> 0x03，2 -> 0x04 ... -64 -> 0x7F，64 -> 0x8180
+func makeSignedWriter(typ reflect.Type) (writer, error) {
+	switch {
+	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
+		return writeSignedBigIntPtr, nil
+	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
+		return writeSignedBigIntNoPtr, nil
+	case isInt(typ.Kind()):
+		return writeInt, nil
+	default:
+		return nil, fmt.Errorf("rlp: type %v does not support the \"signed\" tag", typ)
+	}
+}
+
+// writeInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeInt 方法接受两个参数：有符号整数的 reflect.Value 和一个 *encBuffer 实例，该方法调用 *encBuffer.writeInt64 方法
+// 将给定的整数编码进 *encBuffer.str 里。
+func writeInt(val reflect.Value, buf *encBuffer) error {
+	buf.writeInt64(val.Int())
+	return nil
+}
+
+// writeSignedBigIntPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeSignedBigIntPtr 方法与 writeBigIntPtr 方法类似，区别在于它允许大整数是负数，大整数会先经过zig-zag变换再被编码，
+// 如果给定的 *big.Int 是一个空指针，则会把该大整数看成是"0"进行编码。
+func writeSignedBigIntPtr(val reflect.Value, buf *encBuffer) error {
+	ptr := val.Interface().(*big.Int)
+	if ptr == nil {
+		buf.str = append(buf.str, 0x80)
+		return nil
+	}
+	buf.writeSignedBigInt(ptr)
+	return nil
+}
+
+// writeSignedBigIntNoPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeSignedBigIntNoPtr 方法与 writeBigIntNoPtr 方法类似，区别在于它允许大整数是负数。
+func writeSignedBigIntNoPtr(val reflect.Value, buf *encBuffer) error {
+	i := val.Interface().(big.Int)
+	buf.writeSignedBigInt(&i)
+	return nil
+}
+
 // writeBool ♏ |作者：吴翔宇| 🍁 |日期：2022/11/8|
 //
 // writeBool 方法接受两个参数：bool 的 reflect.Value 和一个 *encBuffer 实例，该方法调用 *encBuffer.writeBool 方法将布尔
@@ -354,6 +481,36 @@ func makeEncodeWriter(typ reflect.Type) (writer, error) {
 	return w, nil
 }
 
+// writeU256Ptr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeU256Ptr 方法编码 *U256 类型的值，nil指针被编码为0x80，也就是0。
+func writeU256Ptr(val reflect.Value, buf *encBuffer) error {
+	ptr := val.Interface().(*U256)
+	if ptr == nil {
+		buf.str = append(buf.str, 0x80)
+		return nil
+	}
+	buf.writeUint256(ptr)
+	return nil
+}
+
+// writeU256NoPtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeU256NoPtr 方法编码 U256 类型的值。与 writeBigIntNoPtr 不同，这里不通过 val.Interface() 获取值，因为把一个32字节
+// 的数组装进接口需要分配内存，而是直接从 reflect.Value 里读出4个64位无符号整数。
+func writeU256NoPtr(val reflect.Value, buf *encBuffer) error {
+	if val.CanAddr() {
+		buf.writeUint256(val.Addr().Interface().(*U256))
+		return nil
+	}
+	var z U256
+	for i := range z {
+		z[i] = val.Index(i).Uint()
+	}
+	buf.writeUint256(&z)
+	return nil
+}
+
 // makeByteArrayWriter ♏ |作者：吴翔宇| 🍁 |日期：2022/11/9|
 //
 // makeByteArrayWriter 方法接受某个字节数组的 reflect.Type，该方法为字节数组生成一个编码器，对于长度为0的数组，其编码结果就是0x80，
@@ -421,6 +578,9 @@ func makeSliceWriter(typ reflect.Type, tag rlpstruct.Tag) (writer, error) {
 		// 如果这个切片是某个结构体中定义的最后一个字段
 		w = func(value reflect.Value, buffer *encBuffer) error {
 			length := value.Len() // 计算切片长度
+			if workers := buffer.opts.workers(length); workers > 1 {
+				return writeElemsParallel(value, info.writer, buffer, workers, false)
+			}
 			for i := 0; i < length; i++ {
 				// 将切片里的元素逐个编码到 *encBuffer.str 里，这里的逻辑我们要明白，由于该结构体字段的tag被标记为
 				// "rlp:tail"，那么就不会将该切片当成列表进行编码，而是对该切片里的数据进行逐一编码。
@@ -439,6 +599,9 @@ func makeSliceWriter(typ reflect.Type, tag rlpstruct.Tag) (writer, error) {
 				buffer.str = append(buffer.str, 0xC0)
 				return nil
 			}
+			if workers := buffer.opts.workers(length); workers > 1 {
+				return writeElemsParallel(value, info.writer, buffer, workers, true)
+			}
 			// 在 *encBuffer 里面加一个列表头，并返回列表头的索引值（列表头数量减1）
 			listOffset := buffer.listStart()
 			for i := 0; i < length; i++ {
@@ -453,6 +616,130 @@ func makeSliceWriter(typ reflect.Type, tag rlpstruct.Tag) (writer, error) {
 	return w, nil
 }
 
+// writeElemsParallel ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// writeElemsParallel 方法把切片value分成workers段连续的元素，每一段在一个goroutine里被编码到从 encBufferPool 里取出的
+// *encBuffer 里，这些 *encBuffer 的编码选项是零值，所以嵌套的切片不会再次被并行编码。全部编码完成之后，各段的长度之和就是
+// 列表内容的长度，list为true时先用 listHead.encodeHead 写入列表头，然后按顺序把各段的编码结果拼接到buffer里，因此结果与
+// 顺序编码逐字节相同。如果有多段编码出错，返回排在最前面的那一段的错误，这也是顺序编码会遇到的第一个错误。
+func writeElemsParallel(value reflect.Value, elemWriter writer, buffer *encBuffer, workers int, list bool) error {
+	length := value.Len()
+	chunks := make([]*encBuffer, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := range chunks {
+		chunks[i] = getEncBuffer()
+		wg.Add(1)
+		go func(chunk *encBuffer, lo, hi int, err *error) {
+			defer wg.Done()
+			for j := lo; j < hi; j++ {
+				if *err = elemWriter(value.Index(j), chunk); *err != nil {
+					return
+				}
+			}
+		}(chunks[i], length*i/workers, length*(i+1)/workers, &errs[i])
+	}
+	wg.Wait()
+	defer func() {
+		for _, chunk := range chunks {
+			encBufferPool.Put(chunk)
+		}
+	}()
+	total := 0
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		total += chunk.size()
+	}
+	if list {
+		head := listHead{size: total}
+		buffer.str = append(buffer.str, head.encodeHead(buffer.auxiliaryBuf[:])...)
+	}
+	pos := len(buffer.str)
+	buffer.str = append(buffer.str, make([]byte, total)...)
+	for _, chunk := range chunks {
+		chunk.copyTo(buffer.str[pos:])
+		pos += chunk.size()
+	}
+	return nil
+}
+
+// makeMapWriter ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeMapWriter 方法为map类型生成编码器。map会被编码成一个由[key, value]键值对组成的列表，为了让编码结果是确定的，
+// 键值对按照key的rlp编码结果的字节序进行升序排列，例如map[string]uint{"b": 2, "a": 1}会被编码成[["a", 1], ["b", 2]]。
+// map的key必须是可排序的类型：无符号整数、字符串、字节数组或者 *big.Int；如果两个不同的key的编码结果相同（比如两个数值
+// 相同的 *big.Int 指针），编码会失败，否则解码时无法还原出原来的map。
+func makeMapWriter(typ reflect.Type) (writer, error) {
+	if !isOrderableMapKey(typ.Key()) {
+		return nil, fmt.Errorf("rlp: map key type %v is not orderable", typ.Key())
+	}
+	keyInfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tag{})
+	if keyInfo.writerErr != nil {
+		return nil, keyInfo.writerErr
+	}
+	elemInfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tag{})
+	if elemInfo.writerErr != nil {
+		return nil, elemInfo.writerErr
+	}
+	w := func(value reflect.Value, buffer *encBuffer) error {
+		if value.Len() == 0 {
+			buffer.str = append(buffer.str, 0xC0)
+			return nil
+		}
+		entries, err := sortMapEntries(value, keyInfo.writer)
+		if err != nil {
+			return err
+		}
+		listOffset := buffer.listStart()
+		for _, entry := range entries {
+			pairOffset := buffer.listStart()
+			// key的编码结果一定是字符串，不包含列表头，所以可以直接追加到 encBuffer.str 后面
+			buffer.str = append(buffer.str, entry.key...)
+			if err = elemInfo.writer(entry.value, buffer); err != nil {
+				return err
+			}
+			buffer.listEnd(pairOffset)
+		}
+		buffer.listEnd(listOffset)
+		return nil
+	}
+	return w, nil
+}
+
+// mapEntry 存储map里的一个键值对，其中key是键的rlp编码结果。
+type mapEntry struct {
+	key   []byte
+	value reflect.Value
+}
+
+// sortMapEntries ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// sortMapEntries 方法对map里的每个key进行编码，然后按照编码结果对键值对进行排序，如果有两个key的编码结果相同，则返回错误。
+func sortMapEntries(value reflect.Value, keyWriter writer) ([]mapEntry, error) {
+	keyBuf := encBufferPool.Get().(*encBuffer)
+	defer encBufferPool.Put(keyBuf)
+	entries := make([]mapEntry, 0, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		keyBuf.reset()
+		if err := keyWriter(iter.Key(), keyBuf); err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key: keyBuf.makeBytes(), value: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	for i := 1; i < len(entries); i++ {
+		if bytes.Equal(entries[i-1].key, entries[i].key) {
+			return nil, fmt.Errorf("rlp: duplicate key encoding %x in map of type %v", entries[i].key, value.Type())
+		}
+	}
+	return entries, nil
+}
+
 // makeStructWriter ♏ |作者：吴翔宇| 🍁 |日期：2022/11/9|
 //
 // makeStructWriter 方法接受一个参数：某结构体的 reflect.Type，该方法为给定的结构体生成编码器，注意，给定的结构体
@@ -476,7 +763,7 @@ func makeStructWriter(typ reflect.Type) (writer, error) {
 			// 将一整个结构体数据看成是一个列表，结构体里的每个字段看成是列表里的元素
 			listOffset := buffer.listStart()
 			for _, f := range fields {
-				if err = f.info.writer(value.Field(f.index), buffer); err != nil {
+				if err := f.info.writer(value.Field(f.index), buffer); err != nil {
 					return err
 				}
 			}
@@ -497,7 +784,7 @@ func makeStructWriter(typ reflect.Type) (writer, error) {
 			listOffset := buffer.listStart()
 			for i := 0; i <= lastFieldIndex; i++ {
 				// tag被设置为"rlp:optional"且值不为空的字段参与编码
-				if err = fields[i].info.writer(value.Field(fields[i].index), buffer); err != nil {
+				if err := fields[i].info.writer(value.Field(fields[i].index), buffer); err != nil {
 					return err
 				}
 			}
@@ -588,3 +875,30 @@ func intSize(i uint64) int {
 		}
 	}
 }
+
+// zigzag ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// zigzag 方法对给定的有符号整数做zig-zag变换，将其映射为一个无符号整数：非负数x被映射为2x，负数x被映射为-2x-1，
+// 变换前后的值一一对应，所以每个有符号整数都只有唯一的一种编码结果。
+func zigzag(i int64) uint64 {
+	return uint64(i<<1) ^ uint64(i>>63)
+}
+
+// zigzagBig ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// zigzagBig 方法对可以是负数的大整数做zig-zag变换，规则与 zigzag 方法相同。
+func zigzagBig(i *big.Int) *big.Int {
+	z := new(big.Int).Lsh(i, 1)
+	if i.Sign() < 0 {
+		z.Neg(z)
+		z.Sub(z, big.NewInt(1))
+	}
+	return z
+}
+
+// unzigzag ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// unzigzag 方法是 zigzag 方法的逆变换。
+func unzigzag(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
 	"strconv"
@@ -77,6 +79,15 @@ func run(t *testing.T, f func(val interface{}) ([]byte, error), test encTest, se
 	if err == nil && !bytes.Equal(output, unhex(test.output)) {
 		t.Errorf("%d: encode result mismatch\ngot: 	%X\nwant: 	%s\nvalue: 	%#v\ntype:	%T", serial, output, test.output, test.val, test.val)
 	}
+	if test.error == "" {
+		// EncodedSize 计算出的长度必须与真正编码得到的长度相同
+		size, err := EncodedSize(test.val)
+		if err != nil {
+			t.Errorf("%d: unexpected EncodedSize error: %v\nvalue: 	%#v\ntype: 	%T", serial, err, test.val, test.val)
+		} else if size != len(unhex(test.output)) {
+			t.Errorf("%d: EncodedSize mismatch\ngot: 	%d\nwant: 	%d\nvalue: 	%#v\ntype:	%T", serial, size, len(unhex(test.output)), test.val, test.val)
+		}
+	}
 }
 
 func f(val interface{}) ([]byte, error) {
@@ -293,6 +304,93 @@ type optionalPtrFieldNil struct {
 	B *[3]byte `rlp:"optional,nil"`
 }
 
+type signedFields struct {
+	A int8     `rlp:"signed"`
+	B int64    `rlp:"signed"`
+	C *big.Int `rlp:"signed"`
+	D big.Int  `rlp:"signed"`
+}
+
+type signedUintField struct {
+	A uint `rlp:"signed"`
+}
+
+func TestEncodeSigned(t *testing.T) {
+	var encTests = []encTest{
+		{val: signedFields{}, output: "C480808080"},
+		{val: signedFields{A: -1, B: 100, C: big.NewInt(-300), D: *new(big.Int).Lsh(big.NewInt(1), 64)}, output: "D00181C882025789020000000000000000"},
+		{val: signedFields{A: -128, B: math.MinInt64, C: big.NewInt(1)}, output: "CD81FF88FFFFFFFFFFFFFFFF0280"},
+		{val: signedFields{A: 127, B: math.MaxInt64, C: big.NewInt(-1)}, output: "CD81FE88FFFFFFFFFFFFFFFE0180"},
+		{val: signedUintField{A: 1}, error: `rlp: invalid struct tag "signed" for rlp.signedUintField.A (tag "signed" is only allowed to be set on the signed integer or big.Int type field)`},
+	}
+	for i, test := range encTests {
+		run(t, f, test, i)
+	}
+}
+
+func TestEncodeMap(t *testing.T) {
+	var encTests = []encTest{
+		{val: map[string]uint{}, output: "C0"},
+		{val: map[string]uint(nil), output: "C0"},
+		{val: map[string]uint{"b": 2, "a": 1}, output: "C6C26101C26202"},
+		// 键值对按照key的编码结果排序，所以0（0x80）排在1（0x01）的后面
+		{val: map[uint]string{200: "x", 0: "", 1: "a"}, output: "CAC20161C28080C381C878"},
+		{val: map[[2]byte]uint{{1, 2}: 1}, output: "C5C482010201"},
+		{val: map[*big.Int]bool{big.NewInt(256): true}, output: "C5C482010001"},
+		{val: map[string]map[string]uint{"a": {"b": 1}}, output: "C6C561C3C26201"},
+		{val: map[*big.Int]bool{big.NewInt(1): true, big.NewInt(1): false}, error: "rlp: duplicate key encoding 01 in map of type map[*big.Int]bool"},
+		{val: map[int]bool{1: true}, error: "rlp: map key type int is not orderable"},
+		{val: map[string]func(){"a": nil}, error: "rlp: type func() is not RLP-serializable"},
+	}
+	for i, test := range encTests {
+		run(t, f, test, i)
+	}
+}
+
+type u256Fields struct {
+	A U256
+	B *U256
+	C U256 `rlp:"optional"`
+}
+
+func TestEncodeU256(t *testing.T) {
+	var encTests = []encTest{
+		{val: U256{}, output: "80"},
+		{val: *NewU256(127), output: "7F"},
+		{val: NewU256(128), output: "8180"},
+		{val: NewU256(1024), output: "820400"},
+		{val: (*U256)(nil), output: "80"},
+		{val: U256{0, 1}, output: "89010000000000000000"},
+		{val: U256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}, output: "A0FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"},
+		{val: u256Fields{A: *NewU256(1)}, output: "C20180"},
+		{val: u256Fields{B: NewU256(1024), C: U256{0, 0, 0, 1}}, output: "DE808204009901000000000000000000000000000000000000000000000000"},
+	}
+	for i, test := range encTests {
+		run(t, f, test, i)
+	}
+}
+
+func TestEncodeBufferSigned(t *testing.T) {
+	for _, i := range []int64{0, 1, -1, 63, -64, 64, math.MaxInt64, math.MinInt64} {
+		buf := new(bytes.Buffer)
+		w := NewEncodeBuffer(buf)
+		w.WriteInt64(i)
+		w.WriteSignedBigInt(big.NewInt(i))
+		assert.Nil(t, w.Flush())
+		// 有符号整数与有符号大整数的编码结果应当一致
+		half := len(buf.Bytes()) / 2
+		assert.Equal(t, buf.Bytes()[:half], buf.Bytes()[half:])
+
+		s := NewStream(bytes.NewReader(buf.Bytes()), 0)
+		got, err := s.Int64()
+		assert.Nil(t, err)
+		assert.Equal(t, i, got)
+		gotBig, err := s.SignedBigInt()
+		assert.Nil(t, err)
+		assert.Equal(t, big.NewInt(i), gotBig)
+	}
+}
+
 func TestName(t *testing.T) {
 	dec, _ := hex.DecodeString("c401010203")
 	t.Log(dec)
@@ -422,3 +520,143 @@ func TestOther(t *testing.T) {
 	assert.Nil(t, err)
 	t.Log(bz)
 }
+
+type parallelElem struct {
+	Nonce   uint64
+	Payload []byte
+	Nested  [][]uint
+	Value   *big.Int
+	Custom  *testEncoder
+	Rest    []string `rlp:"tail"`
+}
+
+// parallelElems 生成n个长度各不相同的元素，使得各段的编码结果有长有短，列表头也有长有短。
+func parallelElems(n int) []parallelElem {
+	elems := make([]parallelElem, n)
+	for i := range elems {
+		elems[i] = parallelElem{
+			Nonce:   uint64(i),
+			Payload: bytes.Repeat([]byte{byte(i)}, i%70),
+			Nested:  [][]uint{make([]uint, i%5), {uint(i)}},
+			Value:   big.NewInt(int64(i) << 40),
+			Custom:  &testEncoder{},
+			Rest:    []string{strconv.Itoa(i)},
+		}
+	}
+	return elems
+}
+
+func TestEncodeParallel(t *testing.T) {
+	type tailHolder struct {
+		A    uint
+		Tail []parallelElem `rlp:"tail"`
+	}
+	for _, n := range []int{0, 1, 3, 7, 100, 1000} {
+		elems := parallelElems(n)
+		inputs := []interface{}{elems, &tailHolder{A: 1, Tail: elems}, [][]parallelElem{elems, elems[:n/2]}, []interface{}{elems}}
+		for _, input := range inputs {
+			want, err := EncodeToBytes(input)
+			assert.Nil(t, err)
+			for _, opts := range []EncodeOptions{{}, {ParallelThreshold: 1}, {ParallelThreshold: 2, MaxWorkers: 3}, {ParallelThreshold: 64, MaxWorkers: 1000}} {
+				got, err := EncodeToBytesWithOptions(input, opts)
+				assert.Nil(t, err)
+				assert.Equal(t, want, got, "n=%d %T %+v", n, input, opts)
+
+				var buf bytes.Buffer
+				assert.Nil(t, EncodeWithOptions(&buf, input, opts))
+				assert.Equal(t, want, buf.Bytes(), "n=%d %T %+v", n, input, opts)
+			}
+		}
+	}
+
+	// 通过 EncodeBuffer 编码时，选项只在本次调用中有效
+	var out bytes.Buffer
+	w := NewEncodeBuffer(&out)
+	assert.Nil(t, EncodeWithOptions(w, parallelElems(10), EncodeOptions{ParallelThreshold: 2}))
+	assert.Equal(t, EncodeOptions{}, w.buf.opts)
+	assert.Nil(t, w.Flush())
+	want, _ := EncodeToBytes(parallelElems(10))
+	assert.Equal(t, want, out.Bytes())
+}
+
+func TestEncodeParallelError(t *testing.T) {
+	elems := parallelElems(100)
+	elems[30].Custom = &testEncoder{err: errors.New("first")}
+	elems[80].Custom = &testEncoder{err: errors.New("second")}
+	for i := 0; i < 10; i++ {
+		_, err := EncodeToBytesWithOptions(elems, EncodeOptions{ParallelThreshold: 2, MaxWorkers: 4})
+		assert.EqualError(t, err, "first")
+	}
+	elems[30].Value = big.NewInt(-1)
+	_, err := EncodeToBytesWithOptions(elems, EncodeOptions{ParallelThreshold: 2, MaxWorkers: 4})
+	assert.Equal(t, ErrNegativeBigInt, err)
+}
+
+func BenchmarkEncodeParallel(b *testing.B) {
+	elems := parallelElems(100000)
+	for _, opts := range []EncodeOptions{{}, {ParallelThreshold: 1024}} {
+		b.Run(fmt.Sprintf("threshold=%d", opts.ParallelThreshold), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := EncodeToBytesWithOptions(elems, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// sizedEncoder 实现了 Sizer 接口，EncodedSize 不会调用它的 EncodeRLP 方法。
+type sizedEncoder struct {
+	n       int
+	encoded *bool
+}
+
+func (e *sizedEncoder) EncodeRLP(w io.Writer) error {
+	*e.encoded = true
+	_, err := w.Write(make([]byte, e.n))
+	return err
+}
+
+func (e *sizedEncoder) EncodedSizeRLP() (int, error) {
+	return e.n, nil
+}
+
+func TestEncodedSize(t *testing.T) {
+	for _, n := range []int{0, 1, 55, 56, 1000} {
+		for _, input := range []interface{}{parallelElems(n), map[uint64][]string{1: {"a"}, 300: make([]string, n)}} {
+			want, err := EncodeToBytes(input)
+			assert.Nil(t, err)
+			size, err := EncodedSize(input)
+			assert.Nil(t, err)
+			assert.Equal(t, len(want), size, "n=%d %T", n, input)
+		}
+	}
+
+	// 实现了 Sizer 接口的 Encoder 不会被编码
+	encoded := false
+	value := struct{ E *sizedEncoder }{&sizedEncoder{n: 60, encoded: &encoded}}
+	size, err := EncodedSize(&value)
+	assert.Nil(t, err)
+	assert.Equal(t, 62, size)
+	assert.False(t, encoded)
+	enc, err := EncodeToBytes(&value)
+	assert.Nil(t, err)
+	assert.Equal(t, len(enc), size)
+	assert.True(t, encoded)
+
+	// 错误与编码时相同
+	errTests := []interface{}{
+		big.NewInt(-1),
+		[]*testEncoder{{}, {err: errors.New("test error")}},
+		testEncoder{},
+		map[[2]uint]bool{},
+		make(chan bool),
+	}
+	for i, input := range errTests {
+		_, encErr := EncodeToBytes(input)
+		_, err := EncodedSize(input)
+		assert.NotNil(t, err, "test %d", i)
+		assert.EqualError(t, err, encErr.Error(), "test %d", i)
+	}
+}
@@ -0,0 +1,253 @@
+package rlp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 类型化信封（EIP-2718）
+
+// LegacyEnvelopeType ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// LegacyEnvelopeType 代表传统格式的数据，例如EIP-2718之前的交易，它们直接被编码成一个rlp列表，前面没有类型字节。
+// 类型字节的取值范围是[0x00, MaxEnvelopeType]，这样类型化数据的第一个字节永远不会与rlp列表的编码前缀（不小于0xC0）
+// 混淆，解码时据此区分两种格式。
+const (
+	LegacyEnvelopeType byte = 0x00
+	MaxEnvelopeType    byte = 0x7F
+)
+
+// 定义类型化信封相关的错误
+
+var (
+	ErrUnknownEnvelopeType = errors.New("rlp: unknown envelope type")
+	ErrEmptyEnvelope       = errors.New("rlp: empty typed envelope")
+)
+
+// Envelope ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Envelope 是一个类型化信封，Type 是类型字节，Payload 是承载的数据，它的类型必须是通过 RegisterEnvelope 方法为 Type
+// 注册的原型类型。类型化数据有两种编码形式：
+//  1. 规范形式：type || rlp(payload)，例如交易哈希就是对这种形式计算的，由 MarshalBinary 和 UnmarshalBinary 方法处理；
+//  2. 嵌入形式：当信封作为其他数据的一部分被编码时（例如区块里的交易列表），规范形式会被包装成一个rlp字符串，由
+//     EncodeRLP 和 DecodeRLP 方法处理，因此 Envelope 可以直接作为结构体字段或者切片元素被编码和解码。
+//
+// 传统类型（LegacyEnvelopeType）的数据在两种形式下都被编码成 rlp(payload) 这样一个列表。
+type Envelope struct {
+	Type    byte
+	Payload interface{}
+}
+
+// envelopeRegistry 记录类型字节与原型类型之间的双向映射关系。
+var envelopeRegistry = struct {
+	sync.RWMutex
+	types map[byte]reflect.Type
+	bytes map[reflect.Type]byte
+}{types: make(map[byte]reflect.Type), bytes: make(map[reflect.Type]byte)}
+
+// RegisterEnvelope ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RegisterEnvelope 方法为类型字节typ注册一个原型prototype，解码时会根据类型字节创建一个与原型类型相同的新值来存放数据，
+// 如果原型是指针，则 Envelope.Payload 也是指针。该方法通常在包的 init 方法里被调用，类型字节超出范围、重复注册、原型
+// 为nil、原型类型无法被rlp编码或者传统类型的原型没有被编码成列表时，该方法会panic。
+func RegisterEnvelope(typ byte, prototype interface{}) {
+	if typ > MaxEnvelopeType {
+		panic(fmt.Sprintf("rlp: envelope type 0x%02x out of range", typ))
+	}
+	if prototype == nil {
+		panic("rlp: nil envelope prototype")
+	}
+	rTyp := reflect.TypeOf(prototype)
+	if _, err := cachedWriter(rTyp); err != nil {
+		panic(err)
+	}
+	if _, err := cachedDecoder(rTyp); err != nil {
+		panic(err)
+	}
+	// 传统类型的数据前面没有类型字节，解码时依靠列表前缀与类型化数据区分，所以它的原型必须被编码成列表
+	if typ == LegacyEnvelopeType {
+		bz, err := EncodeToBytes(prototype)
+		if err != nil {
+			panic(err)
+		}
+		if kind, _, _, err := Split(bz); err != nil || kind != List {
+			panic(fmt.Sprintf("rlp: legacy envelope prototype %v does not encode as a list", rTyp))
+		}
+	}
+	envelopeRegistry.Lock()
+	defer envelopeRegistry.Unlock()
+	if old, ok := envelopeRegistry.types[typ]; ok {
+		panic(fmt.Sprintf("rlp: envelope type 0x%02x already registered for %v", typ, old))
+	}
+	if old, ok := envelopeRegistry.bytes[rTyp]; ok {
+		panic(fmt.Sprintf("rlp: %v already registered as envelope type 0x%02x", rTyp, old))
+	}
+	envelopeRegistry.types[typ] = rTyp
+	envelopeRegistry.bytes[rTyp] = typ
+}
+
+// NewEnvelope ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewEnvelope 方法根据payload的类型查找注册过的类型字节，然后将payload装进信封里。
+func NewEnvelope(payload interface{}) (*Envelope, error) {
+	envelopeRegistry.RLock()
+	typ, ok := envelopeRegistry.bytes[reflect.TypeOf(payload)]
+	envelopeRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("rlp: envelope payload type %T is not registered", payload)
+	}
+	return &Envelope{Type: typ, Payload: payload}, nil
+}
+
+// IsLegacy 方法判断信封是否是传统类型。
+func (e Envelope) IsLegacy() bool {
+	return e.Type == LegacyEnvelopeType
+}
+
+// EncodeRLP ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodeRLP 方法实现了 Encoder 接口，按照嵌入形式编码信封：传统类型被编码成列表，其他类型被编码成包装了规范形式的rlp字符串。
+func (e Envelope) EncodeRLP(w io.Writer) error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	if e.IsLegacy() {
+		return Encode(w, e.Payload)
+	}
+	bz, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return Encode(w, bz)
+}
+
+// MarshalBinary ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MarshalBinary 方法返回信封的规范形式：传统类型是 rlp(payload)，其他类型是 type || rlp(payload)。
+func (e Envelope) MarshalBinary() ([]byte, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err := buf.encode(e.Payload); err != nil {
+		return nil, err
+	}
+	if e.IsLegacy() {
+		return buf.makeBytes(), nil
+	}
+	out := make([]byte, 1+buf.size())
+	out[0] = e.Type
+	buf.copyTo(out[1:])
+	return out, nil
+}
+
+// check 方法检查信封的类型字节已经被注册，并且 Payload 的类型与注册的原型类型一致。
+func (e Envelope) check() error {
+	rTyp, err := lookupEnvelope(e.Type)
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(e.Payload) != rTyp {
+		return fmt.Errorf("rlp: envelope type 0x%02x expects payload of type %v, got %T", e.Type, rTyp, e.Payload)
+	}
+	return nil
+}
+
+// DecodeRLP ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeRLP 方法实现了 Decoder 接口，解码嵌入形式的信封：如果下一个值是列表，则按照传统类型解码，否则读取字符串的内容，
+// 再按照规范形式解码。
+func (e *Envelope) DecodeRLP(s *Stream) error {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == List {
+		target, payload, err := newEnvelopePayload(LegacyEnvelopeType)
+		if err != nil {
+			return err
+		}
+		if err = s.Decode(target); err != nil {
+			return err
+		}
+		e.Type, e.Payload = LegacyEnvelopeType, payload()
+		return nil
+	}
+	bz, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	return e.decodeTyped(bz)
+}
+
+// UnmarshalBinary ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// UnmarshalBinary 方法解码规范形式的信封，第一个字节不小于0xC0时按照传统类型的列表解码，否则第一个字节就是类型字节。
+func (e *Envelope) UnmarshalBinary(bz []byte) error {
+	if len(bz) > 0 && bz[0] >= 0xC0 {
+		target, payload, err := newEnvelopePayload(LegacyEnvelopeType)
+		if err != nil {
+			return err
+		}
+		if err = DecodeBytes(bz, target); err != nil {
+			return err
+		}
+		e.Type, e.Payload = LegacyEnvelopeType, payload()
+		return nil
+	}
+	return e.decodeTyped(bz)
+}
+
+// decodeTyped 方法解码 type || rlp(payload) 形式的数据，传统类型不允许以这种形式出现。
+func (e *Envelope) decodeTyped(bz []byte) error {
+	if len(bz) == 0 {
+		return ErrEmptyEnvelope
+	}
+	typ := bz[0]
+	if typ == LegacyEnvelopeType || typ > MaxEnvelopeType {
+		return fmt.Errorf("%w 0x%02x", ErrUnknownEnvelopeType, typ)
+	}
+	target, payload, err := newEnvelopePayload(typ)
+	if err != nil {
+		return err
+	}
+	if err = DecodeBytes(bz[1:], target); err != nil {
+		return err
+	}
+	e.Type, e.Payload = typ, payload()
+	return nil
+}
+
+// lookupEnvelope 方法返回为typ注册的原型类型。
+func lookupEnvelope(typ byte) (reflect.Type, error) {
+	envelopeRegistry.RLock()
+	rTyp, ok := envelopeRegistry.types[typ]
+	envelopeRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w 0x%02x", ErrUnknownEnvelopeType, typ)
+	}
+	return rTyp, nil
+}
+
+// newEnvelopePayload ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// newEnvelopePayload 方法为类型字节typ创建一个新的原型值，返回的target是一个可以传给 Decode 方法的指针，payload方法
+// 在解码完成之后返回与原型类型相同的值：如果原型是指针，则直接返回target，否则返回target所指向的值。
+func newEnvelopePayload(typ byte) (target interface{}, payload func() interface{}, err error) {
+	rTyp, err := lookupEnvelope(typ)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rTyp.Kind() == reflect.Pointer {
+		ptr := reflect.New(rTyp.Elem())
+		return ptr.Interface(), ptr.Interface, nil
+	}
+	ptr := reflect.New(rTyp)
+	return ptr.Interface(), func() interface{} { return ptr.Elem().Interface() }, nil
+}
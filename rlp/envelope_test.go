@@ -0,0 +1,140 @@
+package rlp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type envLegacy struct {
+	Nonce uint64
+	To    []byte
+}
+
+type envAccess struct {
+	Chain uint64
+	Nonce uint64
+	List  []string
+}
+
+type envDynamic struct {
+	Nonce uint64
+	Tip   *U256
+}
+
+type envBlock struct {
+	Number uint64
+	Txs    []Envelope
+}
+
+func init() {
+	RegisterEnvelope(LegacyEnvelopeType, envLegacy{})
+	RegisterEnvelope(0x01, envAccess{})
+	RegisterEnvelope(0x02, &envDynamic{})
+}
+
+func TestEnvelopeEncoding(t *testing.T) {
+	tests := []struct {
+		env       Envelope
+		canonical string // MarshalBinary 的结果
+		embedded  string // EncodeRLP 的结果
+	}{
+		{Envelope{LegacyEnvelopeType, envLegacy{1, []byte{0xAA}}}, "C3 01 81AA", "C3 01 81AA"},
+		{Envelope{0x01, envAccess{1, 2, []string{"a"}}}, "01 C4 01 02 C161", "86 01 C4 01 02 C161"},
+		{Envelope{0x02, &envDynamic{Nonce: 3, Tip: NewU256(1024)}}, "02 C4 03 820400", "86 02 C4 03 820400"},
+	}
+	for _, test := range tests {
+		canonical, err := test.env.MarshalBinary()
+		assert.Nil(t, err)
+		assert.Equal(t, unhex(test.canonical), canonical)
+		embedded, err := EncodeToBytes(test.env)
+		assert.Nil(t, err)
+		assert.Equal(t, unhex(test.embedded), embedded)
+
+		var dec Envelope
+		assert.Nil(t, dec.UnmarshalBinary(canonical))
+		assert.Equal(t, test.env, dec)
+		dec = Envelope{}
+		assert.Nil(t, DecodeBytes(embedded, &dec))
+		assert.Equal(t, test.env, dec)
+	}
+}
+
+func TestEnvelopeInList(t *testing.T) {
+	legacy, err := NewEnvelope(envLegacy{1, []byte{0xAA}})
+	assert.Nil(t, err)
+	typed, err := NewEnvelope(envAccess{1, 2, []string{"a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x01), typed.Type)
+
+	block := envBlock{Number: 9, Txs: []Envelope{*legacy, *typed}}
+	bz, err := EncodeToBytes(block)
+	assert.Nil(t, err)
+	assert.Equal(t, unhex("CD 09 CB C30181AA 8601C40102C161"), bz)
+
+	var dec envBlock
+	assert.Nil(t, DecodeBytes(bz, &dec))
+	assert.Equal(t, block, dec)
+
+	_, err = NewEnvelope(42)
+	assert.NotNil(t, err)
+}
+
+func TestEnvelopeErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		unknown bool
+	}{
+		// 未注册的类型字节
+		{input: "05 C0", unknown: true},
+		// 传统类型不能以 type || rlp(payload) 的形式出现
+		{input: "00 C30181AA", unknown: true},
+		// 0x80到0xBF之间的字节既不是类型字节，也不是列表前缀
+		{input: "80", unknown: true},
+		{input: "B8 01", unknown: true},
+		// 只有类型字节，没有数据
+		{input: "01"},
+		// 数据后面还有多余的字节
+		{input: "01 C401 02C161 00"},
+		// 数据与原型类型不匹配
+		{input: "01 C3 010203"},
+		{input: "C3 01 81AA 00"},
+		{input: ""},
+	}
+	for _, test := range tests {
+		var dec Envelope
+		err := dec.UnmarshalBinary(unhex(test.input))
+		assert.NotNil(t, err, test.input)
+		assert.Equal(t, test.unknown, errors.Is(err, ErrUnknownEnvelopeType), "%s: %v", test.input, err)
+	}
+	var dec Envelope
+	assert.Equal(t, ErrEmptyEnvelope, dec.UnmarshalBinary(nil))
+
+	// 嵌入形式的字符串里包含未注册的类型字节
+	err := DecodeBytes(unhex("82 05C0"), &dec)
+	assert.True(t, errors.Is(err, ErrUnknownEnvelopeType), "%v", err)
+
+	// 编码时类型字节与 Payload 的类型必须和注册的一致
+	_, err = EncodeToBytes(Envelope{0x05, envAccess{}})
+	assert.True(t, errors.Is(err, ErrUnknownEnvelopeType))
+	_, err = EncodeToBytes(Envelope{0x01, &envAccess{}})
+	assert.NotNil(t, err)
+	_, err = (Envelope{0x02, envDynamic{}}).MarshalBinary()
+	assert.NotNil(t, err)
+}
+
+func TestRegisterEnvelope(t *testing.T) {
+	assert.Panics(t, func() { RegisterEnvelope(0x80, envAccess{}) })
+	assert.Panics(t, func() { RegisterEnvelope(0x03, nil) })
+	assert.Panics(t, func() { RegisterEnvelope(0x01, struct{ A uint }{}) })
+	assert.Panics(t, func() { RegisterEnvelope(0x03, envAccess{}) })
+	assert.Panics(t, func() { RegisterEnvelope(0x03, struct{ F func() }{}) })
+	// 传统类型的原型必须被编码成列表，否则无法与类型化数据区分
+	assert.PanicsWithValue(t, "rlp: legacy envelope prototype uint64 does not encode as a list", func() {
+		RegisterEnvelope(LegacyEnvelopeType, uint64(0))
+	})
+	assert.PanicsWithValue(t, "rlp: legacy envelope prototype []uint8 does not encode as a list", func() {
+		RegisterEnvelope(LegacyEnvelopeType, []byte{})
+	})
+}
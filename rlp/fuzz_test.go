@@ -0,0 +1,303 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 下面这些结构体覆盖了解码器里最容易出错的几种形态：optional、tail、nil指针、interface和字节数组。注意 fuzzOptional
+// 里的optional字段都是指针类型，这和以太坊区块头里的 BaseFee 等字段一致：值类型的optional字段如果在输入里被显式地编码
+// 为零值，解码器会接受它，但重新编码时会把它省略掉，所以对值类型的optional字段来说"解码成功即可逐字节还原"并不成立。
+
+type fuzzOptional struct {
+	A uint64
+	B *uint64   `rlp:"optional"`
+	C *big.Int  `rlp:"optional"`
+	D *[]string `rlp:"optional"`
+}
+
+type fuzzTail struct {
+	A    uint16
+	B    []byte
+	Tail []RawValue `rlp:"tail"`
+}
+
+type fuzzInner struct {
+	X uint32
+	Y string
+}
+
+type fuzzNil struct {
+	A *fuzzInner `rlp:"nil"`
+	B *fuzzInner `rlp:"nilString"`
+	C *[4]byte   `rlp:"nilList"`
+	D *uint64
+	E *[]uint8
+}
+
+type fuzzIface struct {
+	A interface{}
+	B []interface{}
+}
+
+type fuzzBytes struct {
+	A [1]byte
+	B [4]byte
+	C [20]byte
+	D []byte
+	E [][2]byte
+}
+
+type fuzzMisc struct {
+	Bool   bool
+	Str    string
+	Signed int64 `rlp:"signed"`
+	Big    big.Int
+	U      U256
+	Map    map[string]uint16
+	Inner  []fuzzInner
+}
+
+// fuzzTargets 列出了 FuzzDecodeBytes 尝试解码的所有目标类型。
+var fuzzTargets = []func() interface{}{
+	func() interface{} { return new(uint64) },
+	func() interface{} { return new(bool) },
+	func() interface{} { return new(string) },
+	func() interface{} { return new([]byte) },
+	func() interface{} { return new(big.Int) },
+	func() interface{} { return new(U256) },
+	func() interface{} { return new(interface{}) },
+	func() interface{} { return new([]uint64) },
+	func() interface{} { return new(RawValue) },
+	func() interface{} { return new(fuzzOptional) },
+	func() interface{} { return new(fuzzTail) },
+	func() interface{} { return new(fuzzNil) },
+	func() interface{} { return new(fuzzIface) },
+	func() interface{} { return new(fuzzBytes) },
+	func() interface{} { return new(fuzzMisc) },
+}
+
+// fuzzSeedValues 的编码结果会被作为 FuzzDecodeBytes 和 FuzzSplit 的种子，它们与 testdata/fuzz 目录下的种子语料一起
+// 为模糊测试提供起点，后者主要收集了各种不合法的输入。
+var fuzzSeedValues = []interface{}{
+	uint64(0),
+	uint64(1024),
+	true,
+	"dog",
+	[]byte{0x80},
+	big.NewInt(0xFFFFFFFF),
+	NewU256(1 << 40),
+	[]interface{}{[]byte("cat"), []interface{}{}},
+	&fuzzOptional{A: 1},
+	&fuzzOptional{A: 1, C: big.NewInt(0)},
+	&fuzzOptional{A: 1, D: &[]string{"a", "bc"}},
+	&fuzzTail{A: 2, B: []byte{1, 2}, Tail: []RawValue{unhex("01"), unhex("C0")}},
+	&fuzzNil{},
+	&fuzzNil{A: &fuzzInner{X: 1}, C: &[4]byte{1, 2, 3, 4}, D: new(uint64)},
+	&fuzzIface{A: []byte{1}, B: []interface{}{[]byte{}, []interface{}{[]byte{2}}}},
+	&fuzzBytes{A: [1]byte{0x7F}, B: [4]byte{0x80}, D: []byte{0}, E: [][2]byte{{1, 2}}},
+	&fuzzMisc{Str: "\x00", Signed: -1, Map: map[string]uint16{"a": 1, "b": 2}, Inner: []fuzzInner{{1, "x"}}},
+}
+
+func addFuzzSeeds(f *testing.F) {
+	for _, v := range fuzzSeedValues {
+		enc, err := EncodeToBytes(v)
+		if err != nil {
+			f.Fatalf("can't encode seed %#v: %v", v, err)
+		}
+		f.Add(enc)
+	}
+}
+
+// FuzzDecodeBytes 把任意输入依次解码到 fuzzTargets 里的每一种类型，只要解码成功，重新编码的结果就必须与输入逐字节相同，
+// 也就是说解码器不能接受任何非规范的编码。
+func FuzzDecodeBytes(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		for _, target := range fuzzTargets {
+			v := target()
+			if err := DecodeBytes(input, v); err != nil {
+				continue
+			}
+			enc, err := EncodeToBytes(v)
+			if err != nil {
+				t.Fatalf("%T: decoded input %x but can't re-encode: %v", v, input, err)
+			}
+			if !bytes.Equal(enc, input) {
+				t.Fatalf("%T: input %x re-encodes to %x", v, input, enc)
+			}
+		}
+	})
+}
+
+// FuzzSplit 检查 Split 和 CountValues 对同一份输入的结论是否一致，并且 Split 切分出来的每个值都必须是规范的编码：
+// 用切分得到的内容重新构造编码头，结果应当与原始输入完全一样。
+func FuzzSplit(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		n, countErr := CountValues(input)
+		var (
+			rest     = input
+			count    int
+			splitErr error
+		)
+		for len(rest) > 0 {
+			kind, content, r, err := Split(rest)
+			if err != nil {
+				splitErr = err
+				break
+			}
+			elem := rest[:len(rest)-len(r)]
+			if want := reencodeSplit(kind, content); !bytes.Equal(elem, want) {
+				t.Fatalf("Split accepted non-canonical value %x, canonical form is %x", elem, want)
+			}
+			count++
+			rest = r
+		}
+		switch {
+		case (countErr == nil) != (splitErr == nil):
+			t.Fatalf("CountValues error %v, Split error %v", countErr, splitErr)
+		case countErr == nil && n != count:
+			t.Fatalf("CountValues returned %d, Split found %d values", n, count)
+		}
+	})
+}
+
+// reencodeSplit 方法根据 Split 返回的类型和内容重新构造出规范的编码。
+func reencodeSplit(kind Kind, content []byte) []byte {
+	if kind == Byte {
+		return content
+	}
+	buf := new(bytes.Buffer)
+	w := NewEncodeBuffer(buf)
+	if kind == List {
+		index := w.ListStart()
+		w.Write(content)
+		w.ListEnd(index)
+	} else {
+		w.WriteBytes(content)
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// FuzzEncodeRoundTrip 用模糊测试生成的数据构造 fuzzMisc 等结构体，编码之后再解码回来，解码结果必须与原值相等，并且再次
+// 编码的结果也必须与第一次编码的结果相同。
+func FuzzEncodeRoundTrip(f *testing.F) {
+	f.Add(uint64(0), []byte{}, "", int64(0), false)
+	f.Add(uint64(127), []byte{0x80}, "dog", int64(-1), true)
+	f.Add(^uint64(0), bytes.Repeat([]byte{0xFF}, 40), strings.Repeat("x", 56), int64(-1<<63), true)
+	f.Fuzz(func(t *testing.T, a uint64, b []byte, s string, i int64, flag bool) {
+		if b == nil {
+			b = []byte{}
+		}
+		misc := &fuzzMisc{
+			Bool:   flag,
+			Str:    s,
+			Signed: i,
+			Map:    map[string]uint16{s: uint16(a)},
+			Inner:  []fuzzInner{{X: uint32(a), Y: s}},
+		}
+		misc.Big.SetBytes(b)
+		misc.U.SetBytes(b)
+		// 没有 nil 标签的指针在值为nil时会被编码为空值，解码后得到的却是指向零值的指针，所以位于中间的optional字段不能为nil
+		opt := &fuzzOptional{A: a}
+		if flag || len(b) > 0 {
+			opt.B = &a
+			opt.C = new(big.Int).SetBytes(b)
+		}
+		if flag {
+			opt.D = &[]string{s}
+		}
+		values := []interface{}{
+			misc,
+			opt,
+			&fuzzNil{A: &fuzzInner{X: uint32(i), Y: s}, D: &a, E: &b},
+			&fuzzIface{A: b, B: []interface{}{[]byte(s), []interface{}{b}}},
+		}
+		for _, v := range values {
+			enc, err := EncodeToBytes(v)
+			if err != nil {
+				t.Fatalf("%T: can't encode: %v", v, err)
+			}
+			dec := reflect.New(reflect.TypeOf(v).Elem())
+			if err := DecodeBytes(enc, dec.Interface()); err != nil {
+				t.Fatalf("%T: can't decode %x: %v", v, enc, err)
+			}
+			reenc, err := EncodeToBytes(dec.Interface())
+			if err != nil {
+				t.Fatalf("%T: can't re-encode: %v", v, err)
+			}
+			if !bytes.Equal(enc, reenc) {
+				t.Fatalf("%T: encoding %x re-encodes to %x", v, enc, reenc)
+			}
+			if m, ok := dec.Interface().(*fuzzMisc); ok {
+				// big.Int 的内部表示不唯一，所以单独比较它的值
+				if m.Big.Cmp(&misc.Big) != 0 {
+					t.Fatalf("big.Int mismatch: got %v, want %v", &m.Big, &misc.Big)
+				}
+				m.Big, misc.Big = big.Int{}, big.Int{}
+			}
+			if o, ok := dec.Interface().(*fuzzOptional); ok && o.C != nil && opt.C != nil && o.C.Cmp(opt.C) == 0 {
+				o.C = opt.C
+			}
+			if !reflect.DeepEqual(v, dec.Interface()) {
+				t.Fatalf("%T: decoded value mismatch:\ngot  %#v\nwant %#v", v, dec.Interface(), v)
+			}
+		}
+	})
+}
+
+// TestFuzzCorpus 检查 testdata/fuzz 目录下的种子语料里，ErrCanon 家族的每一个错误都至少被一个输入触发。
+func TestFuzzCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "fuzz", "FuzzDecodeBytes", "*"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, files)
+
+	canonErrs := []error{ErrCanonInt, ErrCanonSize}
+	found := make(map[error]bool)
+	for _, file := range files {
+		input, err := readFuzzCorpusFile(file)
+		if !assert.Nil(t, err, file) {
+			continue
+		}
+		for _, target := range fuzzTargets {
+			err := DecodeBytes(input, target())
+			for _, canonErr := range canonErrs {
+				if errors.Is(err, canonErr) {
+					found[canonErr] = true
+				}
+			}
+		}
+	}
+	for _, canonErr := range canonErrs {
+		assert.True(t, found[canonErr], "no corpus entry triggers %q", canonErr)
+	}
+}
+
+// readFuzzCorpusFile 方法读取一个"go test fuzz v1"格式的种子文件，该文件里只有一个 []byte 类型的参数。
+func readFuzzCorpusFile(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "go test fuzz v1" {
+		return nil, errors.New("malformed corpus file")
+	}
+	arg := strings.TrimSuffix(strings.TrimPrefix(lines[1], "[]byte("), ")")
+	s, err := strconv.Unquote(arg)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,175 @@
+package rlp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 泛型API
+
+// DecodeTo ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeTo 方法将bz解码成一个T类型的值并返回，规则与 DecodeBytes 相同，省去了先声明变量再取地址的麻烦，例如：
+//
+//	tx, err := rlp.DecodeTo[Transaction](bz)
+func DecodeTo[T any](bz []byte) (T, error) {
+	var x T
+	err := DecodeBytes(bz, &x)
+	return x, err
+}
+
+// EncodeSlice ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodeSlice 方法将items编码成一个列表，结果与 EncodeToBytes(items) 相同，但是在编译期就限定了参数必须是切片。
+func EncodeSlice[T any](items []T) ([]byte, error) {
+	return EncodeToBytes(items)
+}
+
+// typeOf 方法返回类型参数T的 reflect.Type，T是接口类型时也能得到接口类型本身，而不是nil。
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 RawList
+
+// RawList ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RawList 是一个元素类型为T的列表，它以编码后的形式保存列表的内容，解码时只检查内容由若干个完整的rlp编码值组成，并记下元
+// 素的个数，直到调用 Items 或者 Item 方法时才借助 typeInfo 里缓存的解码器把元素解码出来。这样可以持有大量已编码的交易，
+// 而不用为每一笔交易都分配内存。RawList 的零值是一个空列表，它的编码结果与 []T 完全相同，例如：
+//
+//	type Block struct {
+//		Header Header
+//		Txs    rlp.RawList[Transaction]
+//	}
+//
+// 与 RawValue 一样，解码时不会检查元素是否能被解码成T，这类错误会在 Items 或者 Item 方法里返回。
+type RawList[T any] struct {
+	content []byte
+	length  int
+}
+
+// Len 方法返回列表里元素的个数。
+func (l RawList[T]) Len() int {
+	return l.length
+}
+
+// Content 方法返回列表的内容，也就是去掉列表头之后的编码结果，返回的切片不能被修改。
+func (l RawList[T]) Content() []byte {
+	return l.content
+}
+
+// Bytes ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Bytes 方法返回整个列表的编码结果，包括列表头。
+func (l RawList[T]) Bytes() []byte {
+	head := listHead{size: len(l.content)}
+	var buf [9]byte
+	return append(head.encodeHead(buf[:]), l.content...)
+}
+
+// Items ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Items 方法把列表里的所有元素依次解码成T，返回的错误里带有出错元素的下标，例如"decoding into [3].Nonce"。
+func (l RawList[T]) Items() ([]T, error) {
+	dec, err := cachedDecoder(typeOf[T]())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, l.length)
+	stream := NewStream(bytes.NewReader(l.content), uint64(len(l.content)))
+	for i := range items {
+		if err = dec(stream, reflect.ValueOf(&items[i]).Elem()); err != nil {
+			return nil, addErrorContext(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return items, nil
+}
+
+// Item ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Item 方法只解码第i个元素，为了找到该元素需要跳过它前面的所有元素，但是被跳过的元素不会被解码。
+func (l RawList[T]) Item(i int) (T, error) {
+	var x T
+	if i < 0 || i >= l.length {
+		return x, fmt.Errorf("rlp: index %d out of range for RawList of length %d", i, l.length)
+	}
+	rest := l.content
+	for ; i > 0; i-- {
+		_, _, rest, _ = Split(rest)
+	}
+	elem, _, err := splitValue(rest)
+	if err != nil {
+		return x, err
+	}
+	err = DecodeBytes(elem, &x)
+	return x, err
+}
+
+// Append ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Append 方法用T的编码器把x编码之后追加到列表的末尾。
+func (l *RawList[T]) Append(x T) error {
+	w, err := cachedWriter(typeOf[T]())
+	if err != nil {
+		return err
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err = w(reflect.ValueOf(&x).Elem(), buf); err != nil {
+		return err
+	}
+	l.content = append(l.content, buf.makeBytes()...)
+	l.length++
+	return nil
+}
+
+// AppendRaw ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// AppendRaw 方法把一个已经编码好的元素追加到列表的末尾，bz必须恰好是一个rlp编码值，但是不检查它能否被解码成T。
+func (l *RawList[T]) AppendRaw(bz []byte) error {
+	if _, err := singleValue(bz); err != nil {
+		return err
+	}
+	l.content = append(l.content, bz...)
+	l.length++
+	return nil
+}
+
+// EncodeRLP 方法实现了 Encoder 接口，直接写出列表头和已编码的内容。
+func (l RawList[T]) EncodeRLP(w io.Writer) error {
+	_, err := w.Write(l.Bytes())
+	return err
+}
+
+// EncodedSizeRLP 方法实现了 Sizer 接口。
+func (l RawList[T]) EncodedSizeRLP() (int, error) {
+	return headSize(uint64(len(l.content))) + len(l.content), nil
+}
+
+// DecodeRLP ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeRLP 方法实现了 Decoder 接口，它读出整个列表，检查其内容由若干个完整的rlp编码值组成，然后保存列表的内容。使用
+// DecodeBytesNoCopy 解码时保存的内容直接引用输入数据，所以这里限制了切片的容量，避免之后调用 Append 方法时改写输入数据。
+func (l *RawList[T]) DecodeRLP(s *Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	content, _, err := SplitList(raw)
+	if err != nil {
+		return err
+	}
+	n, err := CountValues(content)
+	if err != nil {
+		return err
+	}
+	l.content, l.length = content[:len(content):len(content)], n
+	return nil
+}
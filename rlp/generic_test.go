@@ -0,0 +1,103 @@
+package rlp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTo(t *testing.T) {
+	dog, err := DecodeTo[Dog](unhex("c5 826161 c180"))
+	assert.Nil(t, err)
+	assert.Equal(t, Dog{Name: "aa", Child: &Dog{}}, dog)
+
+	n, err := DecodeTo[uint16](unhex("820100"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(256), n)
+
+	_, err = DecodeTo[uint8](unhex("820100"))
+	assert.EqualError(t, err, "rlp: input string too long for uint8")
+
+	enc, err := EncodeSlice([]jsonInner{{Name: "a"}, {Data: [2]byte{1, 2}}})
+	assert.Nil(t, err)
+	assert.Equal(t, unhex("ca c4 61820000 c4 80820102"), enc)
+	items, err := DecodeTo[[]jsonInner](enc)
+	assert.Nil(t, err)
+	assert.Equal(t, []jsonInner{{Name: "a"}, {Data: [2]byte{1, 2}}}, items)
+}
+
+func TestRawList(t *testing.T) {
+	// 零值是一个空列表
+	var list RawList[jsonInner]
+	assert.Equal(t, 0, list.Len())
+	enc, err := EncodeToBytes(list)
+	assert.Nil(t, err)
+	assert.Equal(t, unhex("c0"), enc)
+
+	assert.Nil(t, list.Append(jsonInner{Name: "a"}))
+	assert.Nil(t, list.Append(jsonInner{Data: [2]byte{1, 2}}))
+	assert.Nil(t, list.AppendRaw(unhex("c4 62 820304")))
+	assert.Equal(t, 3, list.Len())
+	assert.Equal(t, unhex("c4 61820000 c4 80820102 c4 62820304"), list.Content())
+
+	want := []jsonInner{{Name: "a"}, {Data: [2]byte{1, 2}}, {Name: "b", Data: [2]byte{3, 4}}}
+	wantEnc, _ := EncodeToBytes(want)
+	assert.Equal(t, wantEnc, list.Bytes())
+	enc, err = EncodeToBytes(&list)
+	assert.Nil(t, err)
+	assert.Equal(t, wantEnc, enc)
+	size, err := EncodedSize(list)
+	assert.Nil(t, err)
+	assert.Equal(t, len(wantEnc), size)
+
+	items, err := list.Items()
+	assert.Nil(t, err)
+	assert.Equal(t, want, items)
+	item, err := list.Item(2)
+	assert.Nil(t, err)
+	assert.Equal(t, want[2], item)
+	_, err = list.Item(3)
+	assert.EqualError(t, err, "rlp: index 3 out of range for RawList of length 3")
+
+	assert.Equal(t, ErrMoreThanOneValue, list.AppendRaw(unhex("01 02")))
+	assert.Equal(t, 3, list.Len())
+}
+
+func TestRawListDecode(t *testing.T) {
+	type block struct {
+		Number uint64
+		Txs    RawList[jsonInner]
+	}
+	// 解码时只检查元素的编码格式，元素能否被解码成T要等到调用 Items 时才知道
+	input := unhex("ca 05 c8 c4 61820000 c2 80 01")
+	var b block
+	assert.Nil(t, DecodeBytes(input, &b))
+	assert.Equal(t, uint64(5), b.Number)
+	assert.Equal(t, 2, b.Txs.Len())
+	_, err := b.Txs.Items()
+	assert.EqualError(t, err, "rlp: input string too short for [2]uint8, decoding into [1].Data")
+	item, err := b.Txs.Item(0)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonInner{Name: "a"}, item)
+
+	// 解码再编码得到相同的结果
+	enc, err := EncodeToBytes(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, input, enc)
+
+	// 内容本身的编码错误在解码时就会被发现
+	assert.Equal(t, ErrValueTooLarge, DecodeBytes(unhex("c4 05 c2 c3 61"), &b))
+	assert.True(t, errors.Is(DecodeBytes(unhex("c2 05 80"), &b), ErrExpectedList))
+
+	// 零拷贝解码之后调用 Append 不会改写输入数据
+	var pair struct {
+		Txs  RawList[jsonInner]
+		Tail []uint `rlp:"tail"`
+	}
+	input = unhex("c8 c5 c4 61820000 01 02")
+	assert.Nil(t, DecodeBytesNoCopy(input, &pair))
+	assert.Nil(t, pair.Txs.Append(jsonInner{}))
+	assert.Equal(t, unhex("c8 c5 c4 61820000 01 02"), input)
+	assert.Equal(t, []uint{1, 2}, pair.Tail)
+}
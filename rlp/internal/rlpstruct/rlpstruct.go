@@ -42,6 +42,22 @@ func (t Type) DefaultNilValue() NilKind {
 	return NilKindList
 }
 
+// isSignable ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isSignable 方法判断 Type 描述的类型能否设置"signed"标签，有符号整数、big.Int 和 *big.Int 可以设置该标签。由于 Type
+// 里没有记录类型所在的包，所以这里只能依据类型名"Int"来判断是否是 big.Int，更严格的检查留给 makeWriter 和 makeDecoder。
+func (t Type) isSignable() bool {
+	switch {
+	case t.Kind >= reflect.Int && t.Kind <= reflect.Int64:
+		return true
+	case t.Kind == reflect.Struct && t.Name == "Int":
+		return true
+	case t.Kind == reflect.Ptr && t.Elem != nil:
+		return t.Elem.Kind == reflect.Struct && t.Elem.Name == "Int"
+	}
+	return false
+}
+
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
 
 // Field ♏ |作者：吴翔宇| 🍁 |日期：2022/10/29|
@@ -99,6 +115,13 @@ type Tag struct {
 	Tail bool
 	// Ignored 结构体字段的编码规则如果被设置成`rlp:"-"`，那么Ignored被设置为true。编码规则被设置为`rlp:"-"`的字段不参与编码。
 	Ignored bool
+	// Signed 如果结构体字段的tag被设置为`rlp:"signed"`，那么Signed被设置为true。只有有符号整数（int、int8...int64）、big.Int
+	// 和*big.Int类型的字段才能设置"signed"，这些字段会先经过zig-zag变换，被映射成无符号整数之后再进行编码：0->0、-1->1、1->2、
+	// -2->3...，这样绝对值较小的负数也只需要很少的字节就能编码。
+	Signed bool
+	// Alias 如果结构体字段的tag被设置为`rlp:"alias"`，那么Alias被设置为true。只有[]byte和 RawValue 这类字节切片类型的字段才
+	// 能设置"alias"，用 DecodeBytes 解码时，这些字段会直接引用输入数据里的对应部分，而不是复制一份新的数据。
+	Alias bool
 }
 
 // TagError ♏ |作者：吴翔宇| 🍁 |日期：2022/10/29|
@@ -211,6 +234,16 @@ func parseTag(field Field, lastPublic int) (Tag, error) {
 			if field.Type.Kind != reflect.Slice {
 				return result, TagError{Field: field.Name, Tag: t, Err: `tag "tail" is only allowed to be set on the slice type field`}
 			}
+		case "signed":
+			result.Signed = true
+			if !field.Type.isSignable() {
+				return result, TagError{Field: field.Name, Tag: t, Err: `tag "signed" is only allowed to be set on the signed integer or big.Int type field`}
+			}
+		case "alias":
+			result.Alias = true
+			if field.Type.Kind != reflect.Slice || field.Type.Elem == nil || field.Type.Elem.Kind != reflect.Uint8 {
+				return result, TagError{Field: field.Name, Tag: t, Err: `tag "alias" is only allowed to be set on the byte slice type field`}
+			}
 		default:
 			return result, TagError{Field: field.Name, Tag: t, Err: "unknown tag"}
 		}
@@ -0,0 +1,645 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/232425wxy/understanding-ethereum/common/hexutil"
+	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// JSON与RLP之间的转换
+
+// RLPToJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RLPToJSON 方法将一个rlp编码值转换成JSON：列表被转换成数组，字符串和单个字节被转换成"0x"开头的16进制字符串，例如
+// c88363617483646f67 会被转换成["0x636174", "0x646f67"]。输入必须恰好是一个规范编码的值。
+func RLPToJSON(bz []byte) ([]byte, error) {
+	raw, err := singleValue(bz)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := rlpToTree(raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(tree, "", "  ")
+}
+
+// JSONToRLP ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// JSONToRLP 方法是 RLPToJSON 的逆过程，JSON里只能出现数组和"0x"开头的16进制字符串，其他类型的值都会导致错误。
+func JSONToRLP(js []byte) ([]byte, error) {
+	v, err := parseJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := treeToRLP(v)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeToBytes(tree)
+}
+
+// RLPToTypedJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RLPToTypedJSON 方法按照类型typ的结构把rlp编码值转换成JSON，转换遵循与 Decode 完全相同的规则：
+//   - 结构体被转换成以字段名为key的对象，字段的顺序与编码顺序一致，rlp:"-"的字段不会出现，没有被编码的"optional"字段被省略，
+//     "tail"字段被转换成一个数组；
+//   - 无符号整数、有符号整数、big.Int 和 U256 被转换成十进制的JSON数字，布尔值被转换成true或false，设置了"signed"标签的
+//     字段会先经过zig-zag逆变换；
+//   - 字符串、字节切片和字节数组被转换成"0x"开头的16进制字符串；
+//   - 切片和数组被转换成数组，map被转换成由[key, value]组成的数组；
+//   - 设置了"nil"类标签的指针在编码为空值时被转换成null；
+//   - RawValue、接口以及自定义了编解码规则的类型无法从类型上得知其结构，它们会按照 RLPToJSON 的方式转换。
+//
+// 在转换之前，输入会先被解码到一个typ类型的值里，所以不符合typ的输入会直接返回 Decode 的错误。
+func RLPToTypedJSON(bz []byte, typ reflect.Type) ([]byte, error) {
+	if err := DecodeBytes(bz, reflect.New(typ).Interface()); err != nil {
+		return nil, err
+	}
+	tree, err := typedToJSON(typ, rlpstruct.Tag{}, bz)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(tree, "", "  ")
+}
+
+// TypedJSONToRLP ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// TypedJSONToRLP 方法是 RLPToTypedJSON 的逆过程。对象里不能出现类型里不存在的字段，非"optional"字段不能省略，被省略的
+// "optional"字段后面的字段也必须被省略。编码结果会被重新解码到一个typ类型的值里，以确保它满足 Decode 的所有规则。
+func TypedJSONToRLP(js []byte, typ reflect.Type) ([]byte, error) {
+	v, err := parseJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := jsonToTyped(typ, rlpstruct.Tag{}, v)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := EncodeToBytes(tree)
+	if err != nil {
+		return nil, err
+	}
+	if err = DecodeBytes(bz, reflect.New(typ).Interface()); err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 无类型的转换
+
+// singleValue 方法检查bz恰好是一个rlp编码值，并返回它。
+func singleValue(bz []byte) ([]byte, error) {
+	raw, rest, err := splitValue(bz)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ErrMoreThanOneValue
+	}
+	return raw, nil
+}
+
+// splitValue 方法将bz里的第一个编码值（包括编码前缀）和剩余的数据分开。
+func splitValue(bz []byte) (raw, rest []byte, err error) {
+	if _, _, rest, err = Split(bz); err != nil {
+		return nil, nil, err
+	}
+	return bz[:len(bz)-len(rest)], rest, nil
+}
+
+// rlpToTree 方法将一个rlp编码值转换成由 []interface{} 和16进制字符串组成的树。
+func rlpToTree(raw []byte) (interface{}, error) {
+	k, content, _, err := Split(raw)
+	if err != nil {
+		return nil, err
+	}
+	if k != List {
+		return hexutil.Encode(content), nil
+	}
+	items := []interface{}{}
+	for len(content) > 0 {
+		var elem []byte
+		if elem, content, err = splitValue(content); err != nil {
+			return nil, err
+		}
+		item, err := rlpToTree(elem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// treeToRLP 方法将JSON数组和16进制字符串转换成可以直接被 EncodeToBytes 编码的 []interface{} 和 []byte。
+func treeToRLP(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			elem, err := treeToRLP(item)
+			if err != nil {
+				return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+			}
+			items[i] = elem
+		}
+		return items, nil
+	case string:
+		return hexutil.Decode(v)
+	default:
+		return nil, fmt.Errorf("unexpected JSON value %v, want array or 0x-prefixed hex string", v)
+	}
+}
+
+// parseJSON 方法解析js，数字会被保留为 json.Number，以免大整数丢失精度。
+func parseJSON(js []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("rlp: trailing data after JSON value")
+	}
+	return v, nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 按照类型转换
+
+// jsonKind 描述一个Go类型在JSON里的表示形式。
+type jsonKind int
+
+const (
+	jsonLeaf   jsonKind = iota // 数字、布尔值或者16进制字符串
+	jsonRaw                    // 无法从类型得知结构，按照 RLPToJSON 的方式转换
+	jsonPtr                    // 指针
+	jsonStruct                 // 结构体对应的对象
+	jsonList                   // 切片和数组对应的数组
+	jsonMap                    // map对应的[key, value]数组
+)
+
+// classifyJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// classifyJSON 方法判断typ在JSON里的表示形式，判断的顺序与 makeDecoder 和 makeWriter 保持一致。
+func classifyJSON(typ reflect.Type, tag rlpstruct.Tag) (jsonKind, error) {
+	kind := typ.Kind()
+	switch {
+	case tag.Signed:
+		return jsonLeaf, nil
+	case typ == rawValueType:
+		return jsonRaw, nil
+	case typ == reflect.PtrTo(bigIntType), typ == bigIntType, typ == reflect.PtrTo(u256Type), typ == u256Type:
+		return jsonLeaf, nil
+	case kind == reflect.Pointer:
+		return jsonPtr, nil
+	case reflect.PtrTo(typ).Implements(decoderInterface), reflect.PtrTo(typ).Implements(encoderInterface):
+		return jsonRaw, nil
+	case isUint(kind), kind == reflect.Bool, kind == reflect.String:
+		return jsonLeaf, nil
+	case (kind == reflect.Slice || kind == reflect.Array) && isByte(typ.Elem()):
+		return jsonLeaf, nil
+	case kind == reflect.Interface:
+		return jsonRaw, nil
+	case kind == reflect.Struct:
+		return jsonStruct, nil
+	case kind == reflect.Slice || kind == reflect.Array:
+		return jsonList, nil
+	case kind == reflect.Map:
+		return jsonMap, nil
+	default:
+		return 0, fmt.Errorf("rlp: type %v is not RLP-serializable", typ)
+	}
+}
+
+// bigIntType = reflect.TypeOf(big.Int{})
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// typedToJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// typedToJSON 方法按照typ和tag将一个rlp编码值raw转换成JSON树，raw已经被验证过可以被解码成typ。
+func typedToJSON(typ reflect.Type, tag rlpstruct.Tag, raw []byte) (interface{}, error) {
+	kind, err := classifyJSON(typ, tag)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case jsonLeaf:
+		return leafToJSON(typ, tag, raw)
+	case jsonRaw:
+		return rlpToTree(raw)
+	case jsonPtr:
+		if tag.NilManual {
+			k, content, _, err := Split(raw)
+			if err != nil {
+				return nil, err
+			}
+			if k == typeNilKind(typ.Elem(), tag) && len(content) == 0 {
+				return nil, nil
+			}
+		}
+		return typedToJSON(typ.Elem(), rlpstruct.Tag{}, raw)
+	}
+
+	content, _, err := SplitList(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case jsonStruct:
+		return structToJSON(typ, content)
+	case jsonMap:
+		var pairs []interface{}
+		for i := 0; len(content) > 0; i++ {
+			var pair []byte
+			if pair, content, err = splitValue(content); err != nil {
+				return nil, err
+			}
+			pairContent, _, err := SplitList(pair)
+			if err != nil {
+				return nil, err
+			}
+			k, v, _ := splitValue(pairContent)
+			key, err := typedToJSON(typ.Key(), rlpstruct.Tag{}, k)
+			if err != nil {
+				return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+			}
+			val, err := typedToJSON(typ.Elem(), rlpstruct.Tag{}, v)
+			if err != nil {
+				return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+			}
+			pairs = append(pairs, []interface{}{key, val})
+		}
+		if pairs == nil {
+			pairs = []interface{}{}
+		}
+		return pairs, nil
+	default:
+		return listToJSON(typ.Elem(), content)
+	}
+}
+
+// listToJSON 方法将列表的内容content按照元素类型elemTyp逐个转换。
+func listToJSON(elemTyp reflect.Type, content []byte) ([]interface{}, error) {
+	items := []interface{}{}
+	for i := 0; len(content) > 0; i++ {
+		var (
+			elem []byte
+			err  error
+		)
+		if elem, content, err = splitValue(content); err != nil {
+			return nil, err
+		}
+		item, err := typedToJSON(elemTyp, rlpstruct.Tag{}, elem)
+		if err != nil {
+			return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// structToJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// structToJSON 方法将结构体编码的内容content转换成一个有序的对象，"tail"字段收集剩余的所有元素，内容耗尽时后面的
+// "optional"字段被省略。
+func structToJSON(typ reflect.Type, content []byte) (jsonObject, error) {
+	fields, tags, err := structFieldsAndTags(typ)
+	if err != nil {
+		return nil, err
+	}
+	obj := jsonObject{}
+	for i, f := range fields {
+		fTyp := typ.Field(f.Index).Type
+		if tags[i].Tail {
+			items, err := listToJSON(fTyp.Elem(), content)
+			if err != nil {
+				return nil, withJSONPath(err, "."+f.Name)
+			}
+			obj = append(obj, jsonMember{f.Name, items})
+			break
+		}
+		if len(content) == 0 {
+			break
+		}
+		var elem []byte
+		if elem, content, err = splitValue(content); err != nil {
+			return nil, err
+		}
+		v, err := typedToJSON(fTyp, tags[i], elem)
+		if err != nil {
+			return nil, withJSONPath(err, "."+f.Name)
+		}
+		obj = append(obj, jsonMember{f.Name, v})
+	}
+	return obj, nil
+}
+
+// leafToJSON ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// leafToJSON 方法用typ的解码器解码raw，然后把结果转换成JSON数字、布尔值或者16进制字符串。
+func leafToJSON(typ reflect.Type, tag rlpstruct.Tag, raw []byte) (interface{}, error) {
+	info := theTC.generate(typ, tag)
+	if info.decoderErr != nil {
+		return nil, info.decoderErr
+	}
+	val := reflect.New(typ).Elem()
+	s := NewStream(bytes.NewReader(raw), uint64(len(raw)))
+	if err := info.decoder(s, val); err != nil {
+		return nil, err
+	}
+	switch {
+	case typ == bigIntType:
+		return json.Number(val.Addr().Interface().(*big.Int).String()), nil
+	case typ == u256Type:
+		return json.Number(val.Addr().Interface().(*U256).String()), nil
+	}
+	switch v := val.Interface().(type) {
+	case *big.Int:
+		return json.Number(v.String()), nil
+	case *U256:
+		return json.Number(v.String()), nil
+	}
+	switch kind := typ.Kind(); {
+	case kind == reflect.Bool:
+		return val.Bool(), nil
+	case isUint(kind):
+		return json.Number(strconv.FormatUint(val.Uint(), 10)), nil
+	case isInt(kind):
+		return json.Number(strconv.FormatInt(val.Int(), 10)), nil
+	case kind == reflect.String:
+		return hexutil.Encode([]byte(val.String())), nil
+	case kind == reflect.Slice:
+		return hexutil.Encode(val.Bytes()), nil
+	default:
+		return hexutil.Encode(val.Slice(0, val.Len()).Bytes()), nil
+	}
+}
+
+// jsonToTyped ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// jsonToTyped 方法按照typ和tag把JSON树v转换成可以直接被 EncodeToBytes 编码的 []interface{} 和 RawValue。
+func jsonToTyped(typ reflect.Type, tag rlpstruct.Tag, v interface{}) (interface{}, error) {
+	kind, err := classifyJSON(typ, tag)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case jsonLeaf:
+		return jsonToLeaf(typ, tag, v)
+	case jsonRaw:
+		return treeToRLP(v)
+	case jsonPtr:
+		if v == nil {
+			if typeNilKind(typ.Elem(), tag) == String {
+				return RawValue{0x80}, nil
+			}
+			return RawValue{0xC0}, nil
+		}
+		return jsonToTyped(typ.Elem(), rlpstruct.Tag{}, v)
+	case jsonStruct:
+		return jsonToStruct(typ, v)
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected JSON value %v for %v, want array", v, typ)
+	}
+	items := make([]interface{}, len(arr))
+	for i, item := range arr {
+		if kind == jsonMap {
+			pair, ok := item.([]interface{})
+			if !ok || len(pair) != 2 {
+				return nil, withJSONPath(fmt.Errorf("map entry must be a [key, value] array"), fmt.Sprint("[", i, "]"))
+			}
+			key, err := jsonToTyped(typ.Key(), rlpstruct.Tag{}, pair[0])
+			if err != nil {
+				return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+			}
+			val, err := jsonToTyped(typ.Elem(), rlpstruct.Tag{}, pair[1])
+			if err != nil {
+				return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+			}
+			items[i] = []interface{}{key, val}
+			continue
+		}
+		if items[i], err = jsonToTyped(typ.Elem(), rlpstruct.Tag{}, item); err != nil {
+			return nil, withJSONPath(err, fmt.Sprint("[", i, "]"))
+		}
+	}
+	return items, nil
+}
+
+// jsonToStruct ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// jsonToStruct 方法按照字段的编码顺序把JSON对象转换成列表，"tail"字段的元素被直接展开到列表里。
+func jsonToStruct(typ reflect.Type, v interface{}) ([]interface{}, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected JSON value %v for %v, want object", v, typ)
+	}
+	fields, tags, err := structFieldsAndTags(typ)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = true
+	}
+	for name := range obj {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q in JSON object for %v", name, typ)
+		}
+	}
+	items := []interface{}{}
+	missing := ""
+	for i, f := range fields {
+		fv, present := obj[f.Name]
+		if !present {
+			if !tags[i].Optional && !tags[i].Tail {
+				return nil, fmt.Errorf("missing field %q in JSON object for %v", f.Name, typ)
+			}
+			if missing == "" {
+				missing = f.Name
+			}
+			continue
+		}
+		if missing != "" {
+			return nil, fmt.Errorf("field %q of %v is set but preceding optional field %q is missing", f.Name, typ, missing)
+		}
+		fTyp := typ.Field(f.Index).Type
+		if tags[i].Tail {
+			tail, err := jsonToTyped(fTyp, rlpstruct.Tag{}, fv)
+			if err != nil {
+				return nil, withJSONPath(err, "."+f.Name)
+			}
+			items = append(items, tail.([]interface{})...)
+			continue
+		}
+		item, err := jsonToTyped(fTyp, tags[i], fv)
+		if err != nil {
+			return nil, withJSONPath(err, "."+f.Name)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// jsonToLeaf ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// jsonToLeaf 方法把JSON数字、布尔值或者16进制字符串转换成typ类型的值，再用typ的编码器编码。
+func jsonToLeaf(typ reflect.Type, tag rlpstruct.Tag, v interface{}) (RawValue, error) {
+	val := reflect.New(typ).Elem()
+	kind := typ.Kind()
+	switch {
+	case typ == bigIntType, typ == reflect.PtrTo(bigIntType), typ == u256Type, typ == reflect.PtrTo(u256Type):
+		num, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON value %v for %v, want number", v, typ)
+		}
+		n, ok := new(big.Int).SetString(num.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %s for %v", num, typ)
+		}
+		switch typ {
+		case bigIntType:
+			val.Set(reflect.ValueOf(n).Elem())
+		case u256Type, reflect.PtrTo(u256Type):
+			z := new(U256)
+			if z.SetFromBig(n) {
+				return nil, fmt.Errorf("integer %s overflows %v", num, typ)
+			}
+			if typ == u256Type {
+				val.Set(reflect.ValueOf(*z))
+			} else {
+				val.Set(reflect.ValueOf(z))
+			}
+		default:
+			val.Set(reflect.ValueOf(n))
+		}
+	case kind == reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON value %v for %v, want boolean", v, typ)
+		}
+		val.SetBool(b)
+	case isUint(kind), isInt(kind):
+		num, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON value %v for %v, want number", v, typ)
+		}
+		if isUint(kind) {
+			n, err := strconv.ParseUint(num.String(), 10, typ.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %s for %v", num, typ)
+			}
+			val.SetUint(n)
+		} else {
+			n, err := strconv.ParseInt(num.String(), 10, typ.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %s for %v", num, typ)
+			}
+			val.SetInt(n)
+		}
+	default:
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+			return nil, fmt.Errorf("unexpected JSON value %v for %v, want 0x-prefixed hex string", v, typ)
+		}
+		bz, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex string %q for %v: %v", s, typ, err)
+		}
+		switch kind {
+		case reflect.String:
+			val.SetString(string(bz))
+		case reflect.Slice:
+			val.SetBytes(bz)
+		default:
+			if len(bz) != typ.Len() {
+				return nil, fmt.Errorf("hex string %q has wrong length for %v", s, typ)
+			}
+			reflect.Copy(val, reflect.ValueOf(bz))
+		}
+	}
+	info := theTC.generate(typ, tag)
+	if info.writerErr != nil {
+		return nil, info.writerErr
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err := info.writer(val, buf); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// jsonObject 是一个保持字段顺序的JSON对象，encoding/json 在编码 map 时会按照key排序，所以不能直接使用 map。
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	name  string
+	value interface{}
+}
+
+// MarshalJSON 方法按照字段的顺序输出JSON对象。
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonPathError 记录转换出错的位置，例如"Txs[1].Nonce"。
+type jsonPathError struct {
+	path string
+	err  error
+}
+
+func (e *jsonPathError) Error() string {
+	return fmt.Sprintf("rlp: JSON value at %s: %v", strings.TrimPrefix(e.path, "."), e.err)
+}
+
+func (e *jsonPathError) Unwrap() error {
+	return e.err
+}
+
+// withJSONPath 方法在err的路径前面加上elem。
+func withJSONPath(err error, elem string) error {
+	if pe, ok := err.(*jsonPathError); ok {
+		pe.path = elem + pe.path
+		return pe
+	}
+	return &jsonPathError{path: elem, err: err}
+}
@@ -0,0 +1,209 @@
+package rlp
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRLPToJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		json  string
+		err   string
+	}{
+		{input: "80", json: `"0x"`},
+		{input: "05", json: `"0x05"`},
+		{input: "c0", json: `[]`},
+		{input: "c88363617483646f67", json: `["0x636174","0x646f67"]`},
+		{input: "c7c0c1c0c3c0c1c0", json: `[[],[[]],[[],[[]]]]`},
+		{input: "c3 8100 c0", err: ErrCanonSize.Error()},
+		{input: "05 05", err: ErrMoreThanOneValue.Error()},
+	}
+	for i, test := range tests {
+		js, err := RLPToJSON(unhex(test.input))
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, "test %d", i)
+			continue
+		}
+		assert.Nil(t, err, "test %d", i)
+		assert.JSONEq(t, test.json, string(js), "test %d", i)
+
+		bz, err := JSONToRLP(js)
+		assert.Nil(t, err, "test %d", i)
+		assert.Equal(t, unhex(test.input), bz, "test %d", i)
+	}
+}
+
+func TestJSONToRLPErrors(t *testing.T) {
+	tests := []struct {
+		json string
+		err  string
+	}{
+		{json: `["0x01", 1]`, err: "rlp: JSON value at [1]: unexpected JSON value 1, want array or 0x-prefixed hex string"},
+		{json: `[[true]]`, err: "rlp: JSON value at [0][0]: unexpected JSON value true, want array or 0x-prefixed hex string"},
+		{json: `"abc"`, err: "hex string without 0x prefix"},
+		{json: `"0x1"`, err: "hex string of odd length"},
+		{json: `[] []`, err: "rlp: trailing data after JSON value"},
+		{json: `[`, err: "unexpected EOF"},
+	}
+	for i, test := range tests {
+		_, err := JSONToRLP([]byte(test.json))
+		assert.EqualError(t, err, test.err, "test %d", i)
+	}
+}
+
+type jsonInner struct {
+	Name string
+	Data [2]byte
+}
+
+type jsonOuter struct {
+	Nonce    uint64
+	Delta    int64 `rlp:"signed"`
+	Flag     bool
+	Value    *big.Int
+	Balance  U256
+	Inner    *jsonInner `rlp:"nil"`
+	Items    []jsonInner
+	Labels   map[string]uint8
+	Raw      RawValue
+	Skipped  []byte   `rlp:"-"`
+	Optional uint64   `rlp:"optional"`
+	Rest     []uint16 `rlp:"tail"`
+}
+
+func TestTypedJSON(t *testing.T) {
+	value := jsonOuter{
+		Nonce:  1024,
+		Delta:  -3,
+		Flag:   true,
+		Value:  new(big.Int).Lsh(big.NewInt(1), 70),
+		Items:  []jsonInner{{Name: "cat", Data: [2]byte{1, 2}}},
+		Labels: map[string]uint8{"b": 2, "a": 1},
+		Raw:    unhex("c20102"),
+		Rest:   []uint16{},
+	}
+	value.Balance.SetUint64(7)
+	enc, err := EncodeToBytes(&value)
+	assert.Nil(t, err)
+
+	js, err := RLPToTypedJSON(enc, reflect.TypeOf(value))
+	assert.Nil(t, err)
+	assert.Equal(t, `{
+  "Nonce": 1024,
+  "Delta": -3,
+  "Flag": true,
+  "Value": 1180591620717411303424,
+  "Balance": 7,
+  "Inner": null,
+  "Items": [
+    {
+      "Name": "0x636174",
+      "Data": "0x0102"
+    }
+  ],
+  "Labels": [
+    [
+      "0x61",
+      1
+    ],
+    [
+      "0x62",
+      2
+    ]
+  ],
+  "Raw": [
+    "0x01",
+    "0x02"
+  ],
+  "Optional": 0,
+  "Rest": []
+}`, string(js))
+
+	bz, err := TypedJSONToRLP(js, reflect.TypeOf(value))
+	assert.Nil(t, err)
+	assert.Equal(t, enc, bz)
+
+	// 设置optional字段之后，tail字段里的元素紧跟在它的后面
+	value.Optional, value.Rest = 9, []uint16{1, 2}
+	enc, err = EncodeToBytes(&value)
+	assert.Nil(t, err)
+	js, err = RLPToTypedJSON(enc, reflect.TypeOf(value))
+	assert.Nil(t, err)
+	bz, err = TypedJSONToRLP(js, reflect.TypeOf(value))
+	assert.Nil(t, err)
+	assert.Equal(t, enc, bz)
+}
+
+func TestTypedJSONErrors(t *testing.T) {
+	type optional struct {
+		A uint8
+		B uint8 `rlp:"optional"`
+		C uint8 `rlp:"optional"`
+	}
+	typ := reflect.TypeOf(optional{})
+
+	js, err := RLPToTypedJSON(unhex("c101"), typ)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"A": 1}`, string(js))
+
+	tests := []struct {
+		json string
+		err  string
+	}{
+		{json: `{"A": 1, "C": 3}`, err: `field "C" of rlp.optional is set but preceding optional field "B" is missing`},
+		{json: `{"B": 1}`, err: `missing field "A" in JSON object for rlp.optional`},
+		{json: `{"A": 1, "D": 1}`, err: `unknown field "D" in JSON object for rlp.optional`},
+		{json: `{"A": 256}`, err: `rlp: JSON value at A: invalid integer 256 for uint8`},
+		{json: `{"A": "0x01"}`, err: `rlp: JSON value at A: unexpected JSON value 0x01 for uint8, want number`},
+		{json: `[1]`, err: `unexpected JSON value [1] for rlp.optional, want object`},
+	}
+	for i, test := range tests {
+		_, err := TypedJSONToRLP([]byte(test.json), typ)
+		assert.EqualError(t, err, test.err, "test %d", i)
+	}
+
+	// 嵌套的错误会带上完整的路径
+	_, err = TypedJSONToRLP([]byte(`{"Items": [{"Name": "0x", "Data": "0x01"}]}`), reflect.TypeOf(struct{ Items []jsonInner }{}))
+	assert.EqualError(t, err, `rlp: JSON value at Items[0].Data: hex string "0x01" has wrong length for [2]uint8`)
+
+	// 不符合类型的输入直接返回解码错误
+	_, err = RLPToTypedJSON(unhex("c401020304"), typ)
+	var decErr *DecodeError
+	assert.True(t, errors.As(err, &decErr), "%v", err)
+
+	// 非规范的整数编码在转换之前就会被拒绝
+	_, err = RLPToTypedJSON(unhex("c28100"), typ)
+	assert.NotNil(t, err)
+}
+
+func TestTypedJSONNilPointers(t *testing.T) {
+	type ptrs struct {
+		Simple *uint64
+		Str    *[]byte `rlp:"nilString"`
+		List   *uint64 `rlp:"nilList"`
+	}
+	typ := reflect.TypeOf(ptrs{})
+	enc, err := EncodeToBytes(ptrs{})
+	assert.Nil(t, err)
+	assert.Equal(t, unhex("c3 80 80 c0"), enc)
+
+	// 没有设置"nil"类标签的指针被解码成指向零值的指针，所以不会得到null
+	js, err := RLPToTypedJSON(enc, typ)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"Simple": 0, "Str": null, "List": null}`, string(js))
+
+	for _, input := range []string{`{"Simple": 0, "Str": null, "List": null}`, `{"Simple": null, "Str": null, "List": null}`} {
+		bz, err := TypedJSONToRLP([]byte(input), typ)
+		assert.Nil(t, err, input)
+		assert.Equal(t, enc, bz, input)
+	}
+
+	// "nil"类标签要求空值的类型与之匹配
+	_, err = RLPToTypedJSON(unhex("c3 80 c0 c0"), typ)
+	assert.NotNil(t, err)
+}
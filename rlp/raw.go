@@ -146,6 +146,87 @@ func CountValues(bz []byte) (int, error) {
 
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
 
+// ListIterator ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// ListIterator 是 Stream.Iterate 在字节层面的对应物，它基于 Split 方法逐个遍历一个已编码列表里的元素，每个元素都是原始
+// 输入的子切片，因此遍历过程中不会分配任何内存。用法如下：
+//
+//	it, err := NewListIterator(bz)
+//	if err != nil {
+//		return err
+//	}
+//	for it.Next() {
+//		process(it.Kind(), it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type ListIterator struct {
+	data  []byte // 还没有被遍历的列表内容
+	value []byte // 当前元素完整的编码结果（编码前缀+编码内容）
+	kind  Kind
+	err   error
+}
+
+// NewListIterator ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewListIterator 方法接受一个列表的完整编码结果，返回一个用于遍历列表元素的 ListIterator，如果给定的数据不是列表，则返
+// 回 ErrExpectedList，如果列表后面还跟着别的数据，则返回 ErrMoreThanOneValue。
+func NewListIterator(list RawValue) (*ListIterator, error) {
+	content, rest, err := SplitList(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ErrMoreThanOneValue
+	}
+	return &ListIterator{data: content}, nil
+}
+
+// Next ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Next 方法将迭代器移动到下一个元素，如果列表已经被遍历完，或者遇到了不合法的编码，则返回false，后一种情况可以通过 Err
+// 方法获取具体的错误。
+func (it *ListIterator) Next() bool {
+	if len(it.data) == 0 || it.err != nil {
+		it.value = nil
+		return false
+	}
+	kind, _, rest, err := Split(it.data)
+	if err != nil {
+		it.err = err
+		it.value = nil
+		return false
+	}
+	it.kind = kind
+	it.value = it.data[:len(it.data)-len(rest)]
+	it.data = rest
+	return true
+}
+
+// Value ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Value 方法返回当前元素完整的编码结果，可以直接交给 DecodeBytes、SplitString 或者 NewListIterator 做进一步的处理。
+func (it *ListIterator) Value() []byte {
+	return it.value
+}
+
+// Kind ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Kind 方法返回当前元素的类型：Byte、String 或 List。
+func (it *ListIterator) Kind() Kind {
+	return it.kind
+}
+
+// Err ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Err 方法返回遍历过程中遇到的错误。
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
 // readKind ♏ |作者：吴翔宇| 🍁 |日期：2022/11/7|
 //
 // readKind 方法接受一个参数bz []byte，bz是一个rlp编码数据，bz的第一个字节是编码头，编码头的取值分5个段：
@@ -1,6 +1,7 @@
 package rlp
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -36,3 +37,31 @@ func TestAppendUint64(t *testing.T) {
 	res := AppendUint64(bz, i)
 	t.Log(res)
 }
+
+func TestListIterator(t *testing.T) {
+	bz := unhex("CA 01 83616263 C20203 05 80")
+	it, err := NewListIterator(bz)
+	assert.Nil(t, err)
+	var kinds []Kind
+	var values []string
+	for it.Next() {
+		kinds = append(kinds, it.Kind())
+		values = append(values, fmt.Sprintf("%X", it.Value()))
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []Kind{Byte, String, List, Byte, String}, kinds)
+	assert.Equal(t, []string{"01", "83616263", "C20203", "05", "80"}, values)
+	assert.False(t, it.Next())
+
+	// 元素的大小超出了列表剩余的数据
+	it, err = NewListIterator(unhex("C30183AA"))
+	assert.Nil(t, err)
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Equal(t, ErrValueTooLarge, it.Err())
+
+	_, err = NewListIterator(unhex("83616263"))
+	assert.Equal(t, ErrExpectedList, err)
+	_, err = NewListIterator(unhex("C0C0"))
+	assert.Equal(t, ErrMoreThanOneValue, err)
+}
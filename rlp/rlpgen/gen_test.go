@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata 里的 .out.txt 文件")
+
+// 每个 testdata/*.in.txt 文件里都定义了一个名为 Test 的结构体，为其生成的代码需要与对应的 .out.txt 文件完全一致。
+func TestOutput(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.in.txt"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, inputs)
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".in.txt")
+		t.Run(name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, input, nil, 0)
+			assert.Nil(t, err)
+			output, err := generateFromFiles(fset, "test", []*ast.File{f}, []string{"Test"}, true, true)
+			if !assert.Nil(t, err) {
+				return
+			}
+			golden := filepath.Join("testdata", name+".out.txt")
+			if *update {
+				assert.Nil(t, os.WriteFile(golden, output, 0644))
+				return
+			}
+			want, err := os.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(want), string(output))
+		})
+	}
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{
+			src: "package test\n\ntype Test struct {\n\tI interface{ M() }\n}\n",
+			err: "field I: type interface{M()} is not supported by rlpgen",
+		},
+		{
+			src: "package test\n\ntype Test struct {\n\tC chan uint\n}\n",
+			err: "field C: type chan uint is not supported by rlpgen",
+		},
+		{
+			src: "package test\n\ntype Test struct {\n\tA uint `rlp:\"optional\"`\n\tB uint\n}\n",
+			err: `rlp: invalid struct tag "" for Test.B (must be optional because preceding field "A" is optional)`,
+		},
+		{
+			src: "package test\n\ntype Test uint\n",
+			err: "type Test is not a struct",
+		},
+	}
+	for i, test := range tests {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "test.go", test.src, 0)
+		assert.Nil(t, err)
+		_, err = generateFromFiles(fset, "test", []*ast.File{f}, []string{"Test"}, true, true)
+		if assert.NotNil(t, err, "test %d", i) {
+			assert.Contains(t, err.Error(), test.err, "test %d", i)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package gentest
+
+import (
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureTypes 把 rlp 包测试用例里的结构体映射到这里生成了编解码方法的类型。rlp 包的测试用例定义在 package rlp 的测试
+// 文件里，无法被其他包引用，生成的代码又必须导入 rlp 包，所以这里直接解析 encode_test.go 和 decode_test.go，从 encTests
+// 和 decodeTests 里取出这些结构体的编码数据。intField、invalidNilTag 等结构体的定义本身就是错误的，rlpgen 在生成代码时
+// 就会拒绝它们（见 rlpgen 的 TestUnsupportedTypes），所以不在这里。
+var fixtureTypes = map[string]roundTripTest{
+	"simplestruct":         {gen: new(Simple), ref: new(refSimple)},
+	"recstruct":            {gen: new(Rec), ref: new(refRec)},
+	"ignoredFiled":         {gen: new(IgnoredMiddle), ref: new(refIgnoredMiddle)},
+	"ignoredField":         {gen: new(IgnoredMiddle), ref: new(refIgnoredMiddle)},
+	"tailStruct":           {gen: new(TailRaw), ref: new(refTailRaw)},
+	"tailRaw":              {gen: new(TailRaw), ref: new(refTailRaw)},
+	"tailUint":             {gen: new(TailUint), ref: new(refTailUint)},
+	"tailPrivateFields":    {gen: new(TailPrivate), ref: new(refTailPrivate)},
+	"optionalFields":       {gen: new(Optional), ref: new(refOptional)},
+	"optionalAndTailField": {gen: new(OptionalAndTail), ref: new(refOptionalAndTail)},
+	"optionalBigIntField":  {gen: new(OptionalBig), ref: new(refOptionalBig)},
+	"optionalPtrFiled":     {gen: new(OptionalPtr), ref: new(refOptionalPtr)},
+	"optionalPtrField":     {gen: new(OptionalPtr), ref: new(refOptionalPtr)},
+	"optionalPtrFieldNil":  {gen: new(OptionalPtrNil), ref: new(refOptionalPtrNil)},
+	"signedFields":         {gen: new(Signed), ref: new(refSigned)},
+	"u256Fields":           {gen: new(Uint256), ref: new(refUint256)},
+	"bigIntStruct":         {gen: new(BigIntString), ref: new(refBigIntString)},
+	"nilListUint":          {gen: new(NilListUint), ref: new(refNilListUint)},
+	"nilStringSlice":       {gen: new(NilStringSlice), ref: new(refNilStringSlice)},
+}
+
+// fixture 是从 rlp 包的测试用例里取出来的一条编码数据。
+type fixture struct {
+	pos   token.Position
+	typ   string // 测试用例里结构体的名字
+	input []byte
+	valid bool // encTests 里的输出一定是合法的编码，编码结果必须与之相同
+}
+
+// loadFixtures 解析 rlp 包的测试文件，找出所有元素类型为 encTest 或 decodeTest 的切片字面量，返回其中结构体类型的编码数据。
+func loadFixtures(t *testing.T) []fixture {
+	var fixtures []fixture
+	fset := token.NewFileSet()
+	for _, name := range []string{"encode_test.go", "decode_test.go"} {
+		f, err := parser.ParseFile(fset, filepath.Join("..", "..", "..", name), nil, 0)
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			arr, ok := lit.Type.(*ast.ArrayType)
+			if !ok {
+				return true
+			}
+			elemTyp, ok := arr.Elt.(*ast.Ident)
+			if !ok || (elemTyp.Name != "encTest" && elemTyp.Name != "decodeTest") {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				fields := make(map[string]ast.Expr)
+				for _, kv := range elt.(*ast.CompositeLit).Elts {
+					kv := kv.(*ast.KeyValueExpr)
+					fields[kv.Key.(*ast.Ident).Name] = kv.Value
+				}
+				fx := fixture{pos: fset.Position(elt.Pos())}
+				var data ast.Expr
+				if elemTyp.Name == "encTest" {
+					fx.typ, data, fx.valid = structName(fields["val"]), fields["output"], fields["error"] == nil
+				} else {
+					fx.typ, data = structName(fields["ptr"]), fields["input"]
+				}
+				if fx.typ == "" || data == nil {
+					continue
+				}
+				str, err := strconv.Unquote(data.(*ast.BasicLit).Value)
+				assert.Nil(t, err)
+				fx.input, err = hex.DecodeString(strings.ReplaceAll(str, " ", ""))
+				assert.Nil(t, err)
+				fixtures = append(fixtures, fx)
+			}
+			return false
+		})
+	}
+	return fixtures
+}
+
+// structName 返回形如 T{...}、&T{...} 或者 new(T) 的表达式里的类型名T，其他形式的表达式返回空字符串。
+func structName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		return structName(e.X)
+	case *ast.CompositeLit:
+		if id, ok := e.Type.(*ast.Ident); ok {
+			return id.Name
+		}
+	case *ast.CallExpr:
+		if fn, ok := e.Fun.(*ast.Ident); ok && fn.Name == "new" && len(e.Args) == 1 {
+			if id, ok := e.Args[0].(*ast.Ident); ok {
+				return id.Name
+			}
+		}
+	}
+	return ""
+}
+
+// TestFixtures 用 rlp 包的 encTests 和 decodeTests 里的编码数据比较生成的代码与反射路径的编解码结果。
+func TestFixtures(t *testing.T) {
+	fixtures := loadFixtures(t)
+	covered := make(map[string]bool)
+	for _, fx := range fixtures {
+		test, ok := fixtureTypes[fx.typ]
+		if !ok {
+			continue
+		}
+		covered[fx.typ] = true
+		enc := checkRoundTrip(t, test, fx.input)
+		if fx.valid {
+			assert.Equal(t, strings.ToUpper(hex.EncodeToString(fx.input)), strings.ToUpper(hex.EncodeToString(enc)), "%s: %s", fx.pos, fx.typ)
+		}
+	}
+	// 测试用例里的结构体被改名或者删除时，这里的映射也需要相应地更新
+	for name := range fixtureTypes {
+		assert.True(t, covered[name], "no fixtures found for rlp.%s", name)
+	}
+}
@@ -0,0 +1,1064 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+)
+
+func (obj *Simple) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	w.WriteString(obj.B)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Simple) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	_tmp1, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	obj.B = string(_tmp1)
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Rec) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.I))
+	if obj.Child == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		if err := (*obj.Child).EncodeRLP(w); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Rec) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// I:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.I = uint(_tmp0)
+	// Child:
+	if _tmp1, _tmp2, err := dec.Kind(); err != nil {
+		return err
+	} else if _tmp1 != rlp.Byte && _tmp2 == 0 {
+		if _tmp1 != rlp.List {
+			return fmt.Errorf("rlp: wrong kind of empty value (got %v, want List) for *Rec", _tmp1)
+		}
+		if _, err := dec.ListStart(); err != nil {
+			return err
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+		obj.Child = nil
+	} else {
+		var _tmp3 Rec
+		if err := _tmp3.DecodeRLP(dec); err != nil {
+			return err
+		}
+		obj.Child = &_tmp3
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Tail) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	w.WriteUint64(uint64(obj.B))
+	for _, _tmp1 := range obj.Tail {
+		w.Write(_tmp1)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Tail) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	_tmp1, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.B = uint(_tmp1)
+	// Tail:
+	_tmp2 := []rlp.RawValue{}
+	for dec.MoreDataInList() {
+		var _tmp3 rlp.RawValue
+		_tmp4, err := dec.Raw()
+		if err != nil {
+			return err
+		}
+		_tmp3 = _tmp4
+		_tmp2 = append(_tmp2, _tmp3)
+	}
+	obj.Tail = _tmp2
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Optional) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.B != 0
+	_tmp2 := obj.C != 0
+	w.WriteUint64(uint64(obj.A))
+	if _tmp1 || _tmp2 {
+		w.WriteUint64(uint64(obj.B))
+	}
+	if _tmp2 {
+		w.WriteUint64(uint64(obj.C))
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Optional) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	if dec.MoreDataInList() {
+		_tmp1, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		obj.B = uint(_tmp1)
+		// C:
+		if dec.MoreDataInList() {
+			_tmp2, err := dec.Uint64()
+			if err != nil {
+				return err
+			}
+			obj.C = uint(_tmp2)
+		} else {
+			obj.C = 0
+		}
+	} else {
+		obj.B = 0
+		obj.C = 0
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *OptionalAndTail) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.B != 0
+	_tmp2 := obj.Tail != nil
+	w.WriteUint64(uint64(obj.A))
+	if _tmp1 || _tmp2 {
+		w.WriteUint64(uint64(obj.B))
+	}
+	if _tmp2 {
+		for _, _tmp3 := range obj.Tail {
+			w.WriteUint64(uint64(_tmp3))
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *OptionalAndTail) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	if dec.MoreDataInList() {
+		_tmp1, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		obj.B = uint(_tmp1)
+		// Tail:
+		_tmp2 := []uint{}
+		for dec.MoreDataInList() {
+			var _tmp3 uint
+			_tmp4, err := dec.Uint64()
+			if err != nil {
+				return err
+			}
+			_tmp3 = uint(_tmp4)
+			_tmp2 = append(_tmp2, _tmp3)
+		}
+		obj.Tail = _tmp2
+	} else {
+		obj.B = 0
+		obj.Tail = nil
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *OptionalBig) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.B != nil
+	w.WriteUint64(uint64(obj.A))
+	if _tmp1 {
+		if obj.B == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			if obj.B.Sign() == -1 {
+				return rlp.ErrNegativeBigInt
+			}
+			w.WriteBigInt(obj.B)
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *OptionalBig) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	if dec.MoreDataInList() {
+		_tmp1, err := dec.BigInt()
+		if err != nil {
+			return err
+		}
+		obj.B = _tmp1
+	} else {
+		obj.B = nil
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *OptionalPtr) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.B != nil
+	w.WriteUint64(uint64(obj.A))
+	if _tmp1 {
+		if obj.B == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			w.WriteBytes((*obj.B)[:])
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *OptionalPtr) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	if dec.MoreDataInList() {
+		{
+			var _tmp1 [3]byte
+			if err := dec.ReadBytes(_tmp1[:]); err != nil {
+				return err
+			}
+			obj.B = &_tmp1
+		}
+	} else {
+		obj.B = nil
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Ignored) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Ignored) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Kitchen) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteBool(obj.Bool)
+	w.WriteUint64(uint64(obj.U8))
+	w.WriteUint64(uint64(obj.U16))
+	w.WriteUint64(uint64(obj.U32))
+	w.WriteString(obj.Str)
+	w.WriteBytes(obj.Bytes)
+	w.WriteBytes(obj.Array[:])
+	if obj.Big.Sign() == -1 {
+		return rlp.ErrNegativeBigInt
+	}
+	w.WriteBigInt(&obj.Big)
+	if obj.BigPtr == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.BigPtr.Sign() == -1 {
+			return rlp.ErrNegativeBigInt
+		}
+		w.WriteBigInt(obj.BigPtr)
+	}
+	if obj.NilList == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		w.WriteUint64(uint64((*obj.NilList)))
+	}
+	_tmp1 := w.ListStart()
+	for _, _tmp2 := range obj.Structs {
+		if err := _tmp2.EncodeRLP(w); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp1)
+	_tmp3 := w.ListStart()
+	for _tmp4 := range obj.Uints {
+		w.WriteUint64(uint64(obj.Uints[_tmp4]))
+	}
+	w.ListEnd(_tmp3)
+	w.Write(obj.Raw)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Kitchen) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// Bool:
+	_tmp0, err := dec.Bool()
+	if err != nil {
+		return err
+	}
+	obj.Bool = _tmp0
+	// U8:
+	_tmp1, err := dec.Uint8()
+	if err != nil {
+		return err
+	}
+	obj.U8 = _tmp1
+	// U16:
+	_tmp2, err := dec.Uint16()
+	if err != nil {
+		return err
+	}
+	obj.U16 = _tmp2
+	// U32:
+	_tmp3, err := dec.Uint32()
+	if err != nil {
+		return err
+	}
+	obj.U32 = _tmp3
+	// Str:
+	_tmp4, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	obj.Str = string(_tmp4)
+	// Bytes:
+	_tmp5, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	obj.Bytes = _tmp5
+	// Array:
+	if err := dec.ReadBytes(obj.Array[:]); err != nil {
+		return err
+	}
+	// Big:
+	_tmp6, err := dec.BigInt()
+	if err != nil {
+		return err
+	}
+	obj.Big.Set(_tmp6)
+	// BigPtr:
+	_tmp7, err := dec.BigInt()
+	if err != nil {
+		return err
+	}
+	obj.BigPtr = _tmp7
+	// NilList:
+	if _tmp8, _tmp9, err := dec.Kind(); err != nil {
+		return err
+	} else if _tmp8 != rlp.Byte && _tmp9 == 0 {
+		if _tmp8 != rlp.List {
+			return fmt.Errorf("rlp: wrong kind of empty value (got %v, want List) for *uint", _tmp8)
+		}
+		if _, err := dec.ListStart(); err != nil {
+			return err
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+		obj.NilList = nil
+	} else {
+		var _tmp10 uint
+		_tmp11, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp10 = uint(_tmp11)
+		obj.NilList = &_tmp10
+	}
+	// Structs:
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	_tmp12 := []Simple{}
+	for dec.MoreDataInList() {
+		var _tmp13 Simple
+		if err := _tmp13.DecodeRLP(dec); err != nil {
+			return err
+		}
+		_tmp12 = append(_tmp12, _tmp13)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	obj.Structs = _tmp12
+	// Uints:
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	for _tmp14 := range obj.Uints {
+		_tmp15, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		obj.Uints[_tmp14] = uint(_tmp15)
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	// Raw:
+	_tmp16, err := dec.Raw()
+	if err != nil {
+		return err
+	}
+	obj.Raw = _tmp16
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Signed) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteInt64(int64(obj.A))
+	w.WriteInt64(obj.B)
+	if obj.C == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteSignedBigInt(obj.C)
+	}
+	w.WriteSignedBigInt(&obj.D)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Signed) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Int64()
+	if err != nil {
+		return err
+	}
+	if _tmp0 < -128 || _tmp0 > 127 {
+		return fmt.Errorf("rlp: int overflow for int8")
+	}
+	obj.A = int8(_tmp0)
+	// B:
+	_tmp1, err := dec.Int64()
+	if err != nil {
+		return err
+	}
+	obj.B = _tmp1
+	// C:
+	_tmp2, err := dec.SignedBigInt()
+	if err != nil {
+		return err
+	}
+	obj.C = _tmp2
+	// D:
+	_tmp3, err := dec.SignedBigInt()
+	if err != nil {
+		return err
+	}
+	obj.D.Set(_tmp3)
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Uint256) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.C != (rlp.U256{})
+	w.WriteUint256(&obj.A)
+	if obj.B == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteUint256(obj.B)
+	}
+	if _tmp1 {
+		w.WriteUint256(&obj.C)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Uint256) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	if err := dec.ReadUint256(&obj.A); err != nil {
+		return err
+	}
+	// B:
+	obj.B = new(rlp.U256)
+	if err := dec.ReadUint256(obj.B); err != nil {
+		return err
+	}
+	// C:
+	if dec.MoreDataInList() {
+		if err := dec.ReadUint256(&obj.C); err != nil {
+			return err
+		}
+	} else {
+		obj.C = rlp.U256{}
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Alias) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteBytes(obj.A)
+	w.Write(obj.B)
+	w.WriteBytes(obj.C)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Alias) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.BytesNoCopy()
+	if err != nil {
+		return err
+	}
+	obj.A = _tmp0
+	// B:
+	_tmp1, err := dec.RawNoCopy()
+	if err != nil {
+		return err
+	}
+	obj.B = _tmp1
+	// C:
+	_tmp2, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	obj.C = _tmp2
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Reflect) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.O != nil
+	if err := rlp.Encode(w, obj.M); err != nil {
+		return err
+	}
+	if err := rlp.Encode(w, obj.I); err != nil {
+		return err
+	}
+	if obj.MP == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		if err := rlp.Encode(w, (*obj.MP)); err != nil {
+			return err
+		}
+	}
+	if _tmp1 {
+		if err := rlp.Encode(w, obj.O); err != nil {
+			return err
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Reflect) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// M:
+	if err := dec.Decode(&obj.M); err != nil {
+		return err
+	}
+	// I:
+	if err := dec.Decode(&obj.I); err != nil {
+		return err
+	}
+	// MP:
+	{
+		var _tmp0 map[uint][]byte
+		if err := dec.Decode(&_tmp0); err != nil {
+			return err
+		}
+		obj.MP = &_tmp0
+	}
+	// O:
+	if dec.MoreDataInList() {
+		if err := dec.Decode(&obj.O); err != nil {
+			return err
+		}
+	} else {
+		obj.O = nil
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *IgnoredMiddle) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	w.WriteUint64(uint64(obj.C))
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *IgnoredMiddle) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// C:
+	_tmp1, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.C = uint(_tmp1)
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *TailRaw) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	for _, _tmp1 := range obj.Tail {
+		w.Write(_tmp1)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *TailRaw) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// Tail:
+	_tmp1 := []rlp.RawValue{}
+	for dec.MoreDataInList() {
+		var _tmp2 rlp.RawValue
+		_tmp3, err := dec.Raw()
+		if err != nil {
+			return err
+		}
+		_tmp2 = _tmp3
+		_tmp1 = append(_tmp1, _tmp2)
+	}
+	obj.Tail = _tmp1
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *TailUint) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	for _, _tmp1 := range obj.Tail {
+		w.WriteUint64(uint64(_tmp1))
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *TailUint) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// Tail:
+	_tmp1 := []uint{}
+	for dec.MoreDataInList() {
+		var _tmp2 uint
+		_tmp3, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp2 = uint(_tmp3)
+		_tmp1 = append(_tmp1, _tmp2)
+	}
+	obj.Tail = _tmp1
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *TailPrivate) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	w.WriteUint64(uint64(obj.A))
+	for _, _tmp1 := range obj.Tail {
+		w.WriteUint64(uint64(_tmp1))
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *TailPrivate) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// Tail:
+	_tmp1 := []uint{}
+	for dec.MoreDataInList() {
+		var _tmp2 uint
+		_tmp3, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp2 = uint(_tmp3)
+		_tmp1 = append(_tmp1, _tmp2)
+	}
+	obj.Tail = _tmp1
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *OptionalPtrNil) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	_tmp1 := obj.B != nil
+	w.WriteUint64(uint64(obj.A))
+	if _tmp1 {
+		if obj.B == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			w.WriteBytes((*obj.B)[:])
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *OptionalPtrNil) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// A:
+	_tmp0, err := dec.Uint64()
+	if err != nil {
+		return err
+	}
+	obj.A = uint(_tmp0)
+	// B:
+	if dec.MoreDataInList() {
+		if _tmp1, _tmp2, err := dec.Kind(); err != nil {
+			return err
+		} else if _tmp1 != rlp.Byte && _tmp2 == 0 {
+			if _tmp1 != rlp.String {
+				return fmt.Errorf("rlp: wrong kind of empty value (got %v, want String) for *[3]byte", _tmp1)
+			}
+			if _, err := dec.Bytes(); err != nil {
+				return err
+			}
+			obj.B = nil
+		} else {
+			var _tmp3 [3]byte
+			if err := dec.ReadBytes(_tmp3[:]); err != nil {
+				return err
+			}
+			obj.B = &_tmp3
+		}
+	} else {
+		obj.B = nil
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *BigIntString) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	if obj.I == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.I.Sign() == -1 {
+			return rlp.ErrNegativeBigInt
+		}
+		w.WriteBigInt(obj.I)
+	}
+	w.WriteString(obj.B)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *BigIntString) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// I:
+	_tmp0, err := dec.BigInt()
+	if err != nil {
+		return err
+	}
+	obj.I = _tmp0
+	// B:
+	_tmp1, err := dec.Bytes()
+	if err != nil {
+		return err
+	}
+	obj.B = string(_tmp1)
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *NilListUint) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	if obj.X == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		w.WriteUint64(uint64((*obj.X)))
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *NilListUint) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// X:
+	if _tmp0, _tmp1, err := dec.Kind(); err != nil {
+		return err
+	} else if _tmp0 != rlp.Byte && _tmp1 == 0 {
+		if _tmp0 != rlp.List {
+			return fmt.Errorf("rlp: wrong kind of empty value (got %v, want List) for *uint", _tmp0)
+		}
+		if _, err := dec.ListStart(); err != nil {
+			return err
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+		obj.X = nil
+	} else {
+		var _tmp2 uint
+		_tmp3, err := dec.Uint64()
+		if err != nil {
+			return err
+		}
+		_tmp2 = uint(_tmp3)
+		obj.X = &_tmp2
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *NilStringSlice) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncodeBuffer(_w)
+	_tmp0 := w.ListStart()
+	if obj.X == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		_tmp1 := w.ListStart()
+		for _, _tmp2 := range *obj.X {
+			w.WriteUint64(uint64(_tmp2))
+		}
+		w.ListEnd(_tmp1)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *NilStringSlice) DecodeRLP(dec *rlp.Stream) error {
+	if _, err := dec.ListStart(); err != nil {
+		return err
+	}
+	// X:
+	if _tmp0, _tmp1, err := dec.Kind(); err != nil {
+		return err
+	} else if _tmp0 != rlp.Byte && _tmp1 == 0 {
+		if _tmp0 != rlp.String {
+			return fmt.Errorf("rlp: wrong kind of empty value (got %v, want String) for *[]uint", _tmp0)
+		}
+		if _, err := dec.Bytes(); err != nil {
+			return err
+		}
+		obj.X = nil
+	} else {
+		var _tmp2 []uint
+		if _, err := dec.ListStart(); err != nil {
+			return err
+		}
+		_tmp3 := []uint{}
+		for dec.MoreDataInList() {
+			var _tmp4 uint
+			_tmp5, err := dec.Uint64()
+			if err != nil {
+				return err
+			}
+			_tmp4 = uint(_tmp5)
+			_tmp3 = append(_tmp3, _tmp4)
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+		_tmp2 = _tmp3
+		obj.X = &_tmp2
+	}
+	if err := dec.ListEnd(); err != nil {
+		return err
+	}
+	return nil
+}
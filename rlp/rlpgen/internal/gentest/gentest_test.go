@@ -0,0 +1,147 @@
+package gentest
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+// 下面这些类型与生成了编解码方法的类型拥有完全相同的结构，但是没有 EncodeRLP 和 DecodeRLP 方法，所以 rlp 包会通过
+// 反射对它们进行编解码。
+type (
+	refSimple          Simple
+	refRec             Rec
+	refTail            Tail
+	refOptional        Optional
+	refOptionalAndTail OptionalAndTail
+	refOptionalBig     OptionalBig
+	refOptionalPtr     OptionalPtr
+	refIgnored         Ignored
+	refKitchen         Kitchen
+	refSigned          Signed
+	refUint256         Uint256
+	refAlias           Alias
+	refReflect         Reflect
+	refIgnoredMiddle   IgnoredMiddle
+	refTailRaw         TailRaw
+	refTailUint        TailUint
+	refTailPrivate     TailPrivate
+	refOptionalPtrNil  OptionalPtrNil
+	refBigIntString    BigIntString
+	refNilListUint     NilListUint
+	refNilStringSlice  NilStringSlice
+)
+
+type roundTripTest struct {
+	gen    interface{} // 生成了编解码方法的类型的指针
+	ref    interface{} // 与之对应的反射类型的指针
+	inputs []string    // 需要解码的输入，既有合法的，也有不合法的
+}
+
+var roundTripTests = []roundTripTest{
+	{
+		gen: new(Simple), ref: new(refSimple),
+		inputs: []string{"C50583343434", "C6058453505443", "C50583343434C0", "C3058333", "C105", "C50583343434FF", "C0", "05"},
+	},
+	{
+		gen: new(Rec), ref: new(refRec),
+		inputs: []string{"C20180", "C501C3C20280", "C501C3C202C0", "C20100", "C0", "C701C5C203C20480"},
+	},
+	{
+		gen: new(Tail), ref: new(refTail),
+		inputs: []string{"C3010203", "C20102", "C401020304", "C6010201C20304", "C101"},
+	},
+	{
+		gen: new(Optional), ref: new(refOptional),
+		inputs: []string{"C101", "C20102", "C3010203", "C401020304", "C20180", "C0"},
+	},
+	{
+		gen: new(OptionalAndTail), ref: new(refOptionalAndTail),
+		inputs: []string{"C101", "C20102", "C401020304", "C3018003"},
+	},
+	{
+		gen: new(OptionalBig), ref: new(refOptionalBig),
+		inputs: []string{"C101", "C20180", "C3018201", "C30182000A", "C601840102030F"},
+	},
+	{
+		gen: new(OptionalPtr), ref: new(refOptionalPtr),
+		inputs: []string{"C101", "C50183010203", "C20180", "C3018201", "C30182000A"},
+	},
+	{
+		gen: new(Ignored), ref: new(refIgnored),
+		inputs: []string{"C101", "C20102", "C0"},
+	},
+	{
+		gen: new(Kitchen), ref: new(refKitchen),
+		inputs: []string{
+			"E401820100830100008464617665C3010203840102030480831E8480C0C0C20A0BC201C0",
+			"E401820100830100008464617665C3010203840102030480831E8480C180C0C20A0BC201C0",
+			"E40281FF830100008464617665C3010203840102030480831E8480C0C0C20A0BC201C0",
+			"E501820100830100008464617665C3010203840102030480831E8480C0C5C4058234C20A0BC201C0",
+			"E601820100830100008464617665C3010203840102030480831E8480C0C5C4058234C20A0B8255C8",
+			"E601820100830100008464617665C3010203840102030480831E8480C0C5C4058234C20A0B8255C8FF",
+			"DB01820100830100008464617665C3010203840102030480808080",
+		},
+	},
+	{
+		gen: new(Signed), ref: new(refSigned),
+		inputs: []string{"C480808080", "D00181C882025789020000000000000000", "CD81FF88FFFFFFFFFFFFFFFF0280", "C5820100808080", "C5808200018080", "C480800080"},
+	},
+	{
+		gen: new(Uint256), ref: new(refUint256),
+		inputs: []string{"C3808080", "C401820100", "E501820100A08000000000000000000000000000000000000000000000000000000000000000", "E601820100A1010000000000000000000000000000000000000000000000000000000000000000", "C40182000F", "C3018105", "C201C0"},
+	},
+	{
+		gen: new(Reflect), ref: new(refReflect),
+		inputs: []string{
+			"C4C0C0C0C0", "C3C0C0C0", "C9C0C20102C3C20101C0", "D3C6C26101C26202C101C3C20304C5C482010201",
+			"D8C6C26101C26202C101C3C20304CAC482010201C482010201", "C9C6C26202C26101C0C0", "C4C0C0C080", "C5C0C0C0C0C0",
+		},
+	},
+	{
+		gen: new(Alias), ref: new(refAlias),
+		inputs: []string{"C3808080", "C9830102030505820607", "C8820102C3C20102C0", "C3810101C0", "C3C08080", "C28080"},
+	},
+}
+
+// TestRoundTrip 测试生成的 DecodeRLP 方法与反射路径对同一份输入的解码结果是否相同，以及生成的 EncodeRLP 方法与反射
+// 路径对同一个值的编码结果是否相同。
+func TestRoundTrip(t *testing.T) {
+	for _, test := range roundTripTests {
+		t.Run(reflect.TypeOf(test.gen).Elem().Name(), func(t *testing.T) {
+			for _, input := range test.inputs {
+				bz, err := hex.DecodeString(input)
+				assert.Nil(t, err)
+				checkRoundTrip(t, test, bz)
+			}
+		})
+	}
+}
+
+// checkRoundTrip 用生成的代码和反射路径分别解码input，然后再分别编码解码出来的值，比较两者的结果。两者都解码成功时返回
+// 生成的代码的编码结果，否则返回nil。
+func checkRoundTrip(t *testing.T, test roundTripTest, input []byte) []byte {
+	genTyp, refTyp := reflect.TypeOf(test.gen).Elem(), reflect.TypeOf(test.ref).Elem()
+	genVal, refVal := reflect.New(genTyp), reflect.New(refTyp)
+	genErr := rlp.DecodeBytes(input, genVal.Interface())
+	refErr := rlp.DecodeBytes(input, refVal.Interface())
+	if refErr != nil {
+		assert.NotNil(t, genErr, "input %X: reflection failed with %v, generated code succeeded", input, refErr)
+		return nil
+	}
+	if !assert.Nil(t, genErr, "input %X", input) {
+		return nil
+	}
+	assert.Equal(t, refVal.Convert(genVal.Type()).Interface(), genVal.Interface(), "input %X", input)
+
+	genEnc, genErr := rlp.EncodeToBytes(genVal.Interface())
+	refEnc, refErr := rlp.EncodeToBytes(refVal.Interface())
+	assert.Nil(t, genErr, "input %X", input)
+	assert.Nil(t, refErr, "input %X", input)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(refEnc)), strings.ToUpper(hex.EncodeToString(genEnc)), "input %X", input)
+	return genEnc
+}
@@ -0,0 +1,138 @@
+// Package gentest 里定义了一组覆盖各种结构体tag的类型，并为它们生成了编解码代码，测试用例会将生成的代码与 rlp 包基于
+// 反射的编解码结果进行比较。
+package gentest
+
+import (
+	"math/big"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+)
+
+//go:generate go run github.com/232425wxy/understanding-ethereum/rlp/rlpgen -type Simple,Rec,Tail,Optional,OptionalAndTail,OptionalBig,OptionalPtr,Ignored,Kitchen,Signed,Uint256,Alias,Reflect,IgnoredMiddle,TailRaw,TailUint,TailPrivate,OptionalPtrNil,BigIntString,NilListUint,NilStringSlice -out gen_rlp.go
+
+type Simple struct {
+	A uint
+	B string
+}
+
+type Rec struct {
+	I     uint
+	Child *Rec `rlp:"nil"`
+}
+
+type Tail struct {
+	A, B uint
+	Tail []rlp.RawValue `rlp:"tail"`
+}
+
+type Optional struct {
+	A uint
+	B uint `rlp:"optional"`
+	C uint `rlp:"optional"`
+}
+
+type OptionalAndTail struct {
+	A    uint
+	B    uint   `rlp:"optional"`
+	Tail []uint `rlp:"tail"`
+}
+
+type OptionalBig struct {
+	A uint
+	B *big.Int `rlp:"optional"`
+}
+
+type OptionalPtr struct {
+	A uint
+	B *[3]byte `rlp:"optional"`
+}
+
+type Ignored struct {
+	A uint
+	b uint
+	C uint `rlp:"-"`
+}
+
+type Kitchen struct {
+	Bool    bool
+	U8      uint8
+	U16     uint16
+	U32     uint32
+	Str     string
+	Bytes   []byte
+	Array   [4]byte
+	Big     big.Int
+	BigPtr  *big.Int
+	NilList *uint `rlp:"nilList"`
+	Structs []Simple
+	Uints   [2]uint
+	Raw     rlp.RawValue
+}
+
+type Signed struct {
+	A int8     `rlp:"signed"`
+	B int64    `rlp:"signed"`
+	C *big.Int `rlp:"signed"`
+	D big.Int  `rlp:"signed"`
+}
+
+type Uint256 struct {
+	A rlp.U256
+	B *rlp.U256
+	C rlp.U256 `rlp:"optional"`
+}
+
+type Alias struct {
+	A []byte       `rlp:"alias"`
+	B rlp.RawValue `rlp:"alias"`
+	C []byte
+}
+
+type Reflect struct {
+	M  map[string]uint
+	I  interface{}
+	MP *map[uint][]byte
+	O  map[[2]byte]bool `rlp:"optional"`
+}
+
+// 下面这些类型与 rlp 包的测试用例里用到的结构体一一对应，fixtures_test.go 会用那些测试用例的输入对它们进行测试。
+
+type IgnoredMiddle struct {
+	A uint
+	B uint `rlp:"-"`
+	C uint
+}
+
+type TailRaw struct {
+	A    uint
+	Tail []rlp.RawValue `rlp:"tail"`
+}
+
+type TailUint struct {
+	A    uint
+	Tail []uint `rlp:"tail"`
+}
+
+type TailPrivate struct {
+	A    uint
+	Tail []uint `rlp:"tail"`
+	x, y bool   //lint:ignore U1000 unused fields required for testing purposes.
+}
+
+type OptionalPtrNil struct {
+	A uint
+	B *[3]byte `rlp:"optional,nil"`
+}
+
+type BigIntString struct {
+	I *big.Int
+	B string
+}
+
+type NilListUint struct {
+	X *uint `rlp:"nilList"`
+}
+
+type NilStringSlice struct {
+	X *[]uint `rlp:"nilString"`
+}
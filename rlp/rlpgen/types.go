@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+
+	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+)
+
+// typeReflectKind ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// typeReflectKind 方法将 go/types 描述的类型映射为 reflect.Kind，rlp 包在运行时依据 reflect.Kind 选择编解码器，
+// 代码生成器在编译期依据 types.Type 做同样的选择，两者之间需要这样一座桥梁，才能复用 rlpstruct.ProcessFields 对
+// tag 的校验逻辑。
+func typeReflectKind(typ types.Type) reflect.Kind {
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Bool:
+			return reflect.Bool
+		case types.Int:
+			return reflect.Int
+		case types.Int8:
+			return reflect.Int8
+		case types.Int16:
+			return reflect.Int16
+		case types.Int32:
+			return reflect.Int32
+		case types.Int64:
+			return reflect.Int64
+		case types.Uint:
+			return reflect.Uint
+		case types.Uint8:
+			return reflect.Uint8
+		case types.Uint16:
+			return reflect.Uint16
+		case types.Uint32:
+			return reflect.Uint32
+		case types.Uint64:
+			return reflect.Uint64
+		case types.Uintptr:
+			return reflect.Uintptr
+		case types.Float32:
+			return reflect.Float32
+		case types.Float64:
+			return reflect.Float64
+		case types.Complex64:
+			return reflect.Complex64
+		case types.Complex128:
+			return reflect.Complex128
+		case types.String:
+			return reflect.String
+		case types.UnsafePointer:
+			return reflect.UnsafePointer
+		}
+	case *types.Pointer:
+		return reflect.Pointer
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Map:
+		return reflect.Map
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	}
+	return reflect.Invalid
+}
+
+// rlpStructType ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// rlpStructType 方法是 rlp 包里 reflectTypeToRLPType 方法在编译期的翻版，它把 types.Type 转换为 rlpstruct.Type，
+// 对于指针、数组和切片类型，会递归地转换它们的元素类型，rec 用来防止自引用的类型导致无限递归。
+func (ctx *genContext) rlpStructType(typ types.Type, rec map[types.Type]*rlpstruct.Type) *rlpstruct.Type {
+	if prev := rec[typ]; prev != nil {
+		return prev
+	}
+	if rec == nil {
+		rec = make(map[types.Type]*rlpstruct.Type)
+	}
+	t := &rlpstruct.Type{
+		Kind:      typeReflectKind(typ),
+		IsEncoder: ctx.isEncoder(typ),
+		IsDecoder: ctx.isDecoder(typ),
+	}
+	if named, ok := typ.(*types.Named); ok {
+		t.Name = named.Obj().Name()
+	} else if basic, ok := typ.(*types.Basic); ok {
+		t.Name = basic.Name()
+	}
+	rec[typ] = t
+	switch u := typ.Underlying().(type) {
+	case *types.Pointer:
+		t.Elem = ctx.rlpStructType(u.Elem(), rec)
+	case *types.Slice:
+		t.Elem = ctx.rlpStructType(u.Elem(), rec)
+	case *types.Array:
+		t.Elem = ctx.rlpStructType(u.Elem(), rec)
+	}
+	return t
+}
+
+// isEncoder ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isEncoder 方法判断给定类型的指针是否实现了 rlp.Encoder 接口，这与 makeWriter 里的判断条件：
+//
+//	reflect.PtrTo(typ).Implements(encoderInterface)
+//
+// 保持一致。正在被生成代码的类型也被看作是实现了 rlp.Encoder 接口，因为生成的代码会为它们加上 EncodeRLP 方法。
+func (ctx *genContext) isEncoder(typ types.Type) bool {
+	if ctx.generating(typ) {
+		return true
+	}
+	return types.Implements(types.NewPointer(typ), ctx.encoderIface)
+}
+
+// isDecoder ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isDecoder 方法判断给定类型的指针是否实现了 rlp.Decoder 接口，判断条件与 makeDecoder 保持一致。
+func (ctx *genContext) isDecoder(typ types.Type) bool {
+	if ctx.generating(typ) {
+		return true
+	}
+	return types.Implements(types.NewPointer(typ), ctx.decoderIface)
+}
+
+// generating ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// generating 方法判断给定的类型是否是本次需要生成编解码方法的类型。
+func (ctx *genContext) generating(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	for _, t := range ctx.targets {
+		if types.Identical(named, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamed ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isNamed 方法判断给定的类型是否是定义在 pkgPath 包里、名为 name 的具名类型，例如 math/big 包里的 Int。
+func isNamed(typ types.Type, pkgPath, name string) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// isBigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isBigInt 方法判断给定类型是否是 big.Int。
+func isBigInt(typ types.Type) bool {
+	return isNamed(typ, "math/big", "Int")
+}
+
+// isU256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isU256 方法判断给定类型是否是 rlp.U256。
+func isU256(typ types.Type) bool {
+	return isNamed(typ, rlpPackagePath, "U256")
+}
+
+// isByte ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isByte 方法判断给定的类型是否就是 byte 类型本身，与 rlp 包里的 isByte 不同，这里不接受 namedByteType 这种基于
+// byte 定义的具名类型，因为生成的代码无法在不借助 unsafe 的情况下把 []namedByteType 当作 []byte 使用。
+func isByte(typ types.Type) bool {
+	return types.Identical(typ, types.Typ[types.Byte])
+}
+
+// isUint ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isUint 方法判断给定类型的底层类型是否是无符号整数，如果是的话，还会返回该整数类型占用的比特数，uint 和 uintptr
+// 被看成是64位的。
+func isUint(typ types.Type) (bits int, ok bool) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return 0, false
+	}
+	switch basic.Kind() {
+	case types.Uint8:
+		return 8, true
+	case types.Uint16:
+		return 16, true
+	case types.Uint32:
+		return 32, true
+	case types.Uint, types.Uint64, types.Uintptr:
+		return 64, true
+	}
+	return 0, false
+}
+
+// isInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isInt 方法判断给定类型的底层类型是否是有符号整数，如果是的话，还会返回该整数类型占用的比特数，int 被看成是64位的。
+func isInt(typ types.Type) (bits int, ok bool) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return 0, false
+	}
+	switch basic.Kind() {
+	case types.Int8:
+		return 8, true
+	case types.Int16:
+		return 16, true
+	case types.Int32:
+		return 32, true
+	case types.Int, types.Int64:
+		return 64, true
+	}
+	return 0, false
+}
+
+// isBasic ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isBasic 方法判断给定类型的底层类型是否是某种 go/types 的基础类型。
+func isBasic(typ types.Type, kind types.BasicKind) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Kind() == kind
+}
+
+// nilKindOf ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// nilKindOf 方法是 rlp 包里 typeNilKind 方法在编译期的翻版，它计算指针类型的字段在值为 nil 时应该被编码成空字符串
+// 还是空列表，返回值就是空值的编码结果：0x80 或者 0xC0。
+func (ctx *genContext) nilKindOf(elem types.Type, tag rlpstruct.Tag) rlpstruct.NilKind {
+	if tag.NilManual {
+		return tag.NilKind
+	}
+	return ctx.rlpStructType(elem, nil).DefaultNilValue()
+}
+
+// zeroValue ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// zeroValue 方法返回给定类型零值的 Go 语言表达式，生成解码代码时，被标记为"optional"但在输入里缺失的字段会被赋予零值。
+func (ctx *genContext) zeroValue(typ types.Type) string {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return "nil"
+	}
+	return ctx.typeString(typ) + "{}"
+}
+
+// nonZeroCheck ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// nonZeroCheck 方法返回一个判断表达式v不是零值的 Go 语言表达式，它的判断结果必须与 reflect.Value.IsZero 方法一致，
+// 这样生成的代码在处理"optional"字段时，才能与 makeStructWriter 得到相同的编码结果。
+func (ctx *genContext) nonZeroCheck(v string, typ types.Type) (string, error) {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		return fmt.Sprintf("%s != %s", v, ctx.zeroValue(typ)), nil
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return v + " != nil", nil
+	case *types.Array, *types.Struct:
+		if isBigInt(typ) {
+			// 对于 big.Int 来说，只有当它内部的 abs 字段为 nil 时才是零值
+			return v + ".Bits() != nil", nil
+		}
+		if !types.Comparable(u) {
+			return "", fmt.Errorf("optional field of type %v is not comparable", typ)
+		}
+		return fmt.Sprintf("%s != (%s)", v, ctx.zeroValue(typ)), nil
+	}
+	return "", fmt.Errorf("can't check zero value of type %v", typ)
+}
@@ -0,0 +1,805 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义Schema
+
+// SchemaKind ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SchemaKind 描述一个值在rlp编码里的形式，它与Go类型无关，不同的Go类型可能对应同一种 SchemaKind，例如 string、[]byte
+// 都对应 SchemaBytes。
+type SchemaKind uint8
+
+const (
+	SchemaAny    SchemaKind = iota // 任意一个rlp编码值，对应 RawValue、接口以及自定义了编解码规则的类型
+	SchemaBool                     // 布尔值，只能是0x80或者0x01
+	SchemaUint                     // 规范编码的非负整数
+	SchemaInt                      // 经过zig-zag变换之后规范编码的有符号整数
+	SchemaBytes                    // 字符串
+	SchemaList                     // 元素类型都相同的列表
+	SchemaMap                      // 由[key, value]组成的列表，key按照编码结果升序排列
+	SchemaStruct                   // 结构体，每个元素的类型由字段决定
+)
+
+// Schema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Schema 是一个不依赖Go类型的rlp编码结构描述，可以由 SchemaOf 从Go类型生成，也可以由 ParseSchema 从文本解析得到，然后
+// 用 Validate 方法检查任意的rlp编码数据是否满足该结构。各个字段的含义如下：
+//   - Bits：SchemaUint 和 SchemaInt 的位数，取值为8、16、32、64，SchemaUint 还可以是256，0表示没有上限（big.Int）；
+//   - Fixed 和 Len：SchemaBytes 和 SchemaList 是否是定长的，以及定长时的长度，分别对应字节数组和数组；
+//   - Elem：SchemaList 的元素类型，或者 SchemaMap 的value类型；
+//   - Key：SchemaMap 的key类型；
+//   - Struct：SchemaStruct 对应的结构体定义，递归的结构体会指向同一个 StructSchema。
+type Schema struct {
+	Kind   SchemaKind
+	Bits   int
+	Fixed  bool
+	Len    int
+	Elem   *Schema
+	Key    *Schema
+	Struct *StructSchema
+}
+
+// StructSchema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// StructSchema 是一个有名字的结构体定义，字段按照编码顺序排列，rlp:"-"的字段不会出现。
+type StructSchema struct {
+	Name   string
+	Fields []FieldSchema
+}
+
+// FieldSchema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// FieldSchema 描述结构体里的一个字段，Optional、Tail 与同名的rlp标签含义相同，Nullable 表示该字段的值可以是一个类型为
+// NilKind 的空值，对应设置了"nil"、"nilString"或者"nilList"标签的指针。设置了"tail"标签的字段的 Type 是一个 SchemaList，
+// 它的元素被直接展开在结构体的列表里。
+type FieldSchema struct {
+	Name     string
+	Type     *Schema
+	Optional bool
+	Tail     bool
+	Nullable bool
+	NilKind  Kind
+}
+
+// SchemaError ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SchemaError 是 Schema.Validate 返回的错误，Path 是出错的值在整个编码里的位置，例如"Items[0].Name"，Schema 是该位置
+// 期望的类型表达式，Err 是底层的错误原因，可以通过 errors.Is 判断，例如 ErrCanonInt、ErrExpectedList。
+type SchemaError struct {
+	Path   string
+	Schema string
+	Err    error
+}
+
+func (e *SchemaError) Error() string {
+	at := ""
+	if e.Path != "" {
+		at = " at " + e.Path
+	}
+	return fmt.Sprintf("rlp: schema mismatch%s (want %s): %s", at, e.Schema, strings.TrimPrefix(e.Err.Error(), "rlp: "))
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	errTooFewElements  = errors.New("rlp: input list has too few elements")
+	errTooManyElements = errors.New("rlp: input list has too many elements")
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 从Go类型生成Schema
+
+// SchemaOf ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SchemaOf 方法返回Go类型typ的 Schema，规则与 Decode 完全一致，所以能够被解码成typ的数据一定能通过 Schema.Validate 的检
+// 查，反之亦然（自定义了编解码规则的类型对应 SchemaAny，它们内部的结构不做检查）。结构体的名字取自Go类型的名字，匿名结构
+// 体以及名字不是合法标识符的结构体叫作"Struct"，名字重复时在后面加上2、3...，由于遍历的顺序是确定的，同一个类型每次生成
+// 的 Schema 都相同。
+func SchemaOf(typ reflect.Type) (*Schema, error) {
+	info := theTC.info(typ)
+	if info.decoderErr != nil {
+		return nil, info.decoderErr
+	}
+	if info.writerErr != nil {
+		return nil, info.writerErr
+	}
+	b := &schemaBuilder{structs: make(map[reflect.Type]*StructSchema), names: make(map[string]bool)}
+	return b.schema(typ, rlpstruct.Tag{})
+}
+
+// schemaBuilder 在生成 Schema 的过程中记录已经生成的结构体，使得递归的类型能够终止，同一个类型也只有一个 StructSchema。
+type schemaBuilder struct {
+	structs map[reflect.Type]*StructSchema
+	names   map[string]bool
+}
+
+// schema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// schema 方法借助 classifyJSON 判断typ的编码形式，指针没有自己的编码形式，所以直接使用它所指向的类型的 Schema。
+func (b *schemaBuilder) schema(typ reflect.Type, tag rlpstruct.Tag) (*Schema, error) {
+	kind, err := classifyJSON(typ, tag)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case jsonLeaf:
+		return leafSchema(typ, tag), nil
+	case jsonRaw:
+		return &Schema{Kind: SchemaAny}, nil
+	case jsonPtr:
+		return b.schema(typ.Elem(), rlpstruct.Tag{})
+	case jsonStruct:
+		ss, err := b.structSchema(typ)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Kind: SchemaStruct, Struct: ss}, nil
+	case jsonMap:
+		key, err := b.schema(typ.Key(), rlpstruct.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		elem, err := b.schema(typ.Elem(), rlpstruct.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Kind: SchemaMap, Key: key, Elem: elem}, nil
+	default:
+		elem, err := b.schema(typ.Elem(), rlpstruct.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Kind: SchemaList, Elem: elem}
+		if typ.Kind() == reflect.Array {
+			s.Fixed, s.Len = true, typ.Len()
+		}
+		return s, nil
+	}
+}
+
+// structSchema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// structSchema 方法为结构体生成 StructSchema，在处理字段之前就把它登记下来，这样字段里再次出现该结构体时会直接引用它。
+func (b *schemaBuilder) structSchema(typ reflect.Type) (*StructSchema, error) {
+	if ss := b.structs[typ]; ss != nil {
+		return ss, nil
+	}
+	base := typ.Name()
+	if !isSchemaIdent(base) || isSchemaKeyword(base) {
+		// 匿名结构体，或者泛型结构体这类名字里带有方括号的类型
+		base = "Struct"
+	}
+	name := base
+	for i := 2; b.names[name] || isSchemaKeyword(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	ss := &StructSchema{Name: name}
+	b.structs[typ], b.names[name] = ss, true
+
+	fields, tags, err := structFieldsAndTags(typ)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range fields {
+		fTyp := typ.Field(f.Index).Type
+		fs := FieldSchema{Name: f.Name, Optional: tags[i].Optional}
+		if fs.Type, err = b.schema(fTyp, tags[i]); err != nil {
+			return nil, err
+		}
+		// 字节切片会被当成字符串编码，"tail"标签对它不起作用
+		fs.Tail = tags[i].Tail && fs.Type.Kind == SchemaList
+		if kind, _ := classifyJSON(fTyp, tags[i]); kind == jsonPtr && tags[i].NilManual {
+			fs.Nullable, fs.NilKind = true, typeNilKind(fTyp.Elem(), tags[i])
+		}
+		ss.Fields = append(ss.Fields, fs)
+	}
+	return ss, nil
+}
+
+// leafSchema 方法为整数、布尔值、字符串和字节数组生成 Schema。
+func leafSchema(typ reflect.Type, tag rlpstruct.Tag) *Schema {
+	kind := typ.Kind()
+	switch {
+	case tag.Signed && isInt(kind):
+		return &Schema{Kind: SchemaInt, Bits: typ.Bits()}
+	case tag.Signed:
+		return &Schema{Kind: SchemaInt}
+	case typ == bigIntType || typ == reflect.PtrTo(bigIntType):
+		return &Schema{Kind: SchemaUint}
+	case typ == u256Type || typ == reflect.PtrTo(u256Type):
+		return &Schema{Kind: SchemaUint, Bits: 256}
+	case isUint(kind):
+		return &Schema{Kind: SchemaUint, Bits: typ.Bits()}
+	case kind == reflect.Bool:
+		return &Schema{Kind: SchemaBool}
+	case kind == reflect.Array:
+		return &Schema{Kind: SchemaBytes, Fixed: true, Len: typ.Len()}
+	default:
+		return &Schema{Kind: SchemaBytes}
+	}
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// Schema的文本形式
+
+// String ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// String 方法返回 Schema 的类型表达式，语法如下，表达式里不能出现空格：
+//   - any、bool；
+//   - uint8、uint16、uint32、uint64、uint256，以及没有上限的uint；
+//   - 经过zig-zag变换的int8、int16、int32、int64，以及没有上限的int；
+//   - 变长的字符串bytes，长度为N的字符串bytesN，例如bytes32；
+//   - 变长的列表[]T，长度为N的列表[N]T；
+//   - map[K]V；
+//   - 结构体的名字，结构体的定义由 Definitions 方法给出。
+func (s *Schema) String() string {
+	switch s.Kind {
+	case SchemaBool:
+		return "bool"
+	case SchemaUint, SchemaInt:
+		name := "uint"
+		if s.Kind == SchemaInt {
+			name = "int"
+		}
+		if s.Bits != 0 {
+			name += strconv.Itoa(s.Bits)
+		}
+		return name
+	case SchemaBytes:
+		if s.Fixed {
+			return "bytes" + strconv.Itoa(s.Len)
+		}
+		return "bytes"
+	case SchemaList:
+		if s.Fixed {
+			return "[" + strconv.Itoa(s.Len) + "]" + s.Elem.String()
+		}
+		return "[]" + s.Elem.String()
+	case SchemaMap:
+		return "map[" + s.Key.String() + "]" + s.Elem.String()
+	case SchemaStruct:
+		return s.Struct.Name
+	default:
+		return "any"
+	}
+}
+
+// Definitions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Definitions 方法按照深度优先的顺序返回 Schema 里出现的所有结构体的定义，每个字段占一行，依次是字段名、类型表达式以及
+// optional、tail、nilString、nilList这些修饰词，例如：
+//
+//	struct Dog {
+//		Child Dog nilList
+//		Name bytes
+//	}
+//
+// 把结果和 String 方法的返回值一起交给 ParseSchema，可以得到与s相同的 Schema。
+func (s *Schema) Definitions() string {
+	var (
+		buf     strings.Builder
+		visited = make(map[*StructSchema]bool)
+		visit   func(s *Schema)
+	)
+	visit = func(s *Schema) {
+		if s.Key != nil {
+			visit(s.Key)
+		}
+		if s.Elem != nil {
+			visit(s.Elem)
+		}
+		if s.Kind != SchemaStruct || visited[s.Struct] {
+			return
+		}
+		visited[s.Struct] = true
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("struct " + s.Struct.Name + " {\n")
+		for _, f := range s.Struct.Fields {
+			buf.WriteString("\t" + f.Name + " " + f.Type.String())
+			switch {
+			case f.Optional:
+				buf.WriteString(" optional")
+			case f.Tail:
+				buf.WriteString(" tail")
+			}
+			switch {
+			case f.Nullable && f.NilKind == String:
+				buf.WriteString(" nilString")
+			case f.Nullable:
+				buf.WriteString(" nilList")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+		for _, f := range s.Struct.Fields {
+			visit(f.Type)
+		}
+	}
+	visit(s)
+	return buf.String()
+}
+
+// ParseSchema ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// ParseSchema 方法解析由 Definitions 方法生成（或者手写）的结构体定义defs，然后按照这些定义解析类型表达式root，得到一个
+// Schema。defs里可以用"//"写注释，结构体之间的顺序没有要求，也可以相互引用，例如：
+//
+//	schema, err := rlp.ParseSchema(defs, "[]Dog")
+//
+// 字段的修饰词必须满足与rlp标签相同的规则："tail"只能用于最后一个字段，并且类型必须是变长的列表；"optional"字段后面的字段
+// 只能是"optional"字段或者"tail"字段。
+func ParseSchema(defs string, root string) (*Schema, error) {
+	p := &schemaParser{structs: make(map[string]*StructSchema)}
+	if err := p.parseDefinitions(defs); err != nil {
+		return nil, err
+	}
+	s, err := p.parseTypeExpr(root)
+	if err != nil {
+		return nil, fmt.Errorf("rlp: invalid schema %q: %v", root, err)
+	}
+	return s, nil
+}
+
+// schemaParser 记录已经定义的结构体，类型表达式里的名字会被解析成对它们的引用。
+type schemaParser struct {
+	structs map[string]*StructSchema
+}
+
+// schemaLine 是去掉注释和首尾空白之后的一行定义。
+type schemaLine struct {
+	num    int
+	fields []string
+}
+
+// parseDefinitions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// parseDefinitions 方法分两遍解析结构体定义：第一遍登记所有结构体的名字，第二遍解析字段，这样字段可以引用后面才定义的结构体。
+func (p *schemaParser) parseDefinitions(defs string) error {
+	var lines []schemaLine
+	for i, line := range strings.Split(defs, "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, schemaLine{num: i + 1, fields: fields})
+		}
+	}
+	type body struct {
+		ss    *StructSchema
+		lines []schemaLine
+	}
+	var bodies []body
+	for i := 0; i < len(lines); i++ {
+		head := lines[i]
+		if len(head.fields) != 3 || head.fields[0] != "struct" || head.fields[2] != "{" || !isSchemaIdent(head.fields[1]) {
+			return fmt.Errorf("rlp: schema line %d: want \"struct Name {\"", head.num)
+		}
+		name := head.fields[1]
+		if p.structs[name] != nil || isSchemaKeyword(name) {
+			return fmt.Errorf("rlp: schema line %d: struct name %q is already in use", head.num, name)
+		}
+		ss := &StructSchema{Name: name}
+		p.structs[name] = ss
+		start := i + 1
+		for i++; i < len(lines) && !(len(lines[i].fields) == 1 && lines[i].fields[0] == "}"); i++ {
+		}
+		if i == len(lines) {
+			return fmt.Errorf("rlp: schema line %d: struct %s is not closed", head.num, name)
+		}
+		bodies = append(bodies, body{ss, lines[start:i]})
+	}
+	for _, b := range bodies {
+		for _, line := range b.lines {
+			f, err := p.parseField(line.fields)
+			if err != nil {
+				return fmt.Errorf("rlp: schema line %d: %v", line.num, err)
+			}
+			b.ss.Fields = append(b.ss.Fields, f)
+		}
+		if err := checkSchemaFields(b.ss); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseField 方法解析结构体里的一行字段定义：字段名、类型表达式，以及若干个修饰词。
+func (p *schemaParser) parseField(words []string) (FieldSchema, error) {
+	var f FieldSchema
+	if len(words) < 2 || !isSchemaIdent(words[0]) {
+		return f, errors.New("want \"Name type [modifiers]\"")
+	}
+	f.Name = words[0]
+	typ, err := p.parseTypeExpr(words[1])
+	if err != nil {
+		return f, fmt.Errorf("field %s: %v", f.Name, err)
+	}
+	f.Type = typ
+	for _, mod := range words[2:] {
+		switch {
+		case mod == "optional" && !f.Optional:
+			f.Optional = true
+		case mod == "tail" && !f.Tail:
+			f.Tail = true
+		case (mod == "nilString" || mod == "nilList") && !f.Nullable:
+			f.Nullable, f.NilKind = true, String
+			if mod == "nilList" {
+				f.NilKind = List
+			}
+		default:
+			return f, fmt.Errorf("field %s: unexpected modifier %q", f.Name, mod)
+		}
+	}
+	return f, nil
+}
+
+// checkSchemaFields 方法检查结构体里字段的修饰词是否满足rlp标签的规则。
+func checkSchemaFields(ss *StructSchema) error {
+	optional := ""
+	for i, f := range ss.Fields {
+		switch {
+		case f.Tail && (f.Optional || f.Nullable):
+			return fmt.Errorf("rlp: schema struct %s: field %s: \"tail\" cannot be combined with other modifiers", ss.Name, f.Name)
+		case f.Tail && i != len(ss.Fields)-1:
+			return fmt.Errorf("rlp: schema struct %s: field %s: \"tail\" must be on the last field", ss.Name, f.Name)
+		case f.Tail && (f.Type.Kind != SchemaList || f.Type.Fixed):
+			return fmt.Errorf("rlp: schema struct %s: field %s: \"tail\" field must be a variable-length list", ss.Name, f.Name)
+		case f.Optional && optional == "":
+			optional = f.Name
+		case !f.Optional && !f.Tail && optional != "":
+			return fmt.Errorf("rlp: schema struct %s: field %s must be optional because preceding field %s is optional", ss.Name, f.Name, optional)
+		}
+	}
+	return nil
+}
+
+// parseTypeExpr 方法解析一个完整的类型表达式，表达式必须被完全消耗。
+func (p *schemaParser) parseTypeExpr(expr string) (*Schema, error) {
+	s, rest, err := p.parseType(expr)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q after type %s", rest, s)
+	}
+	return s, nil
+}
+
+// parseType ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// parseType 方法从expr的开头解析出一个类型，返回该类型和剩余的部分。
+func (p *schemaParser) parseType(expr string) (*Schema, string, error) {
+	switch {
+	case strings.HasPrefix(expr, "[]"):
+		elem, rest, err := p.parseType(expr[2:])
+		if err != nil {
+			return nil, "", err
+		}
+		return &Schema{Kind: SchemaList, Elem: elem}, rest, nil
+	case strings.HasPrefix(expr, "["):
+		end := strings.IndexByte(expr, ']')
+		if end < 0 {
+			return nil, "", fmt.Errorf("missing ']' in %q", expr)
+		}
+		n, err := strconv.Atoi(expr[1:end])
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid list length %q", expr[1:end])
+		}
+		elem, rest, err := p.parseType(expr[end+1:])
+		if err != nil {
+			return nil, "", err
+		}
+		return &Schema{Kind: SchemaList, Fixed: true, Len: n, Elem: elem}, rest, nil
+	case strings.HasPrefix(expr, "map["):
+		key, rest, err := p.parseType(expr[4:])
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, "]") {
+			return nil, "", fmt.Errorf("missing ']' after map key type %s", key)
+		}
+		if key.Kind != SchemaUint && key.Kind != SchemaBytes {
+			return nil, "", fmt.Errorf("map key type %s is not orderable", key)
+		}
+		elem, rest, err := p.parseType(rest[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		return &Schema{Kind: SchemaMap, Key: key, Elem: elem}, rest, nil
+	}
+	end := 0
+	for end < len(expr) && (expr[end] == '_' || isSchemaIdentChar(expr[end])) {
+		end++
+	}
+	name, rest := expr[:end], expr[end:]
+	if s := parseSchemaKeyword(name); s != nil {
+		return s, rest, nil
+	}
+	if ss := p.structs[name]; ss != nil {
+		return &Schema{Kind: SchemaStruct, Struct: ss}, rest, nil
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("missing type in %q", expr)
+	}
+	return nil, "", fmt.Errorf("unknown type %q", name)
+}
+
+// parseSchemaKeyword 方法解析内置的类型名，例如uint64、bytes32，name不是内置类型时返回nil。
+func parseSchemaKeyword(name string) *Schema {
+	switch name {
+	case "any":
+		return &Schema{Kind: SchemaAny}
+	case "bool":
+		return &Schema{Kind: SchemaBool}
+	case "uint":
+		return &Schema{Kind: SchemaUint}
+	case "int":
+		return &Schema{Kind: SchemaInt}
+	case "bytes":
+		return &Schema{Kind: SchemaBytes}
+	case "uint8", "uint16", "uint32", "uint64", "uint256":
+		bits, _ := strconv.Atoi(name[4:])
+		return &Schema{Kind: SchemaUint, Bits: bits}
+	case "int8", "int16", "int32", "int64":
+		bits, _ := strconv.Atoi(name[3:])
+		return &Schema{Kind: SchemaInt, Bits: bits}
+	}
+	if strings.HasPrefix(name, "bytes") {
+		if n, err := strconv.Atoi(name[5:]); err == nil && n >= 0 && strconv.Itoa(n) == name[5:] {
+			return &Schema{Kind: SchemaBytes, Fixed: true, Len: n}
+		}
+	}
+	return nil
+}
+
+// isSchemaKeyword 方法判断name是否是内置的类型名，结构体不能使用这些名字。
+func isSchemaKeyword(name string) bool {
+	return parseSchemaKeyword(name) != nil || name == "map" || name == "struct"
+}
+
+// isSchemaIdent 方法判断name是否是合法的字段名或者结构体名。
+func isSchemaIdent(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] != '_' && !isSchemaIdentChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSchemaIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 按照Schema检查rlp编码数据
+
+// Validate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Validate 方法检查bz是否恰好是一个满足s的rlp编码值，检查的规则与 Decode 相同：整数必须是规范编码并且不能超出位数，布尔值
+// 只能是0x80或者0x01，定长的字符串和列表的长度必须相符，结构体的元素不能多也不能少（"optional"字段可以省略），map的key必须
+// 按照编码结果严格升序排列。不满足时返回 *SchemaError。
+func (s *Schema) Validate(bz []byte) error {
+	r := bytes.NewReader(bz)
+	stream := NewStream(r, uint64(len(bz)))
+	if err := s.validate(stream); err != nil {
+		if se, ok := err.(*SchemaError); ok {
+			se.Path = strings.TrimPrefix(se.Path, ".")
+			return se
+		}
+		return s.mismatch(err)
+	}
+	if r.Len() > 0 {
+		return s.mismatch(ErrMoreThanOneValue)
+	}
+	return nil
+}
+
+// mismatch 方法把底层的错误包装成 *SchemaError。
+func (s *Schema) mismatch(err error) error {
+	return &SchemaError{Schema: s.String(), Err: err}
+}
+
+// withSchemaPath 方法在err的路径前面加上elem，例如".Items"或者"[0]"。
+func withSchemaPath(err error, elem string) error {
+	if se, ok := err.(*SchemaError); ok {
+		se.Path = elem + se.Path
+	}
+	return err
+}
+
+// validate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// validate 方法从stream里读出一个满足s的值，与解码器一样，遇到列表末尾时原样返回 EOL，由外层的列表决定这是否是一个错误。
+func (s *Schema) validate(stream *Stream) error {
+	var err error
+	switch s.Kind {
+	case SchemaAny:
+		_, err = stream.Raw()
+	case SchemaBool:
+		_, err = stream.Bool()
+	case SchemaUint:
+		switch s.Bits {
+		case 0:
+			_, err = stream.BigInt()
+		case 256:
+			_, err = stream.Uint256()
+		default:
+			_, err = stream.uint(s.Bits)
+		}
+	case SchemaInt:
+		if s.Bits == 0 {
+			_, err = stream.SignedBigInt()
+		} else {
+			_, err = stream.int(s.Bits)
+		}
+	case SchemaBytes:
+		if s.Fixed {
+			err = stream.ReadBytes(make([]byte, s.Len))
+		} else {
+			_, err = stream.Bytes()
+		}
+	case SchemaList:
+		return s.validateList(stream)
+	case SchemaMap:
+		return s.validateMap(stream)
+	case SchemaStruct:
+		return s.validateStruct(stream)
+	}
+	if err != nil && err != EOL {
+		return s.mismatch(err)
+	}
+	return err
+}
+
+// validateList 方法检查一个列表里的所有元素，定长的列表必须恰好有 Len 个元素。
+func (s *Schema) validateList(stream *Stream) error {
+	if _, err := stream.ListStart(); err != nil {
+		return s.listError(err)
+	}
+	for i := 0; !s.Fixed || i < s.Len; i++ {
+		if err := s.Elem.validate(stream); err == EOL {
+			if s.Fixed {
+				return s.mismatch(errTooFewElements)
+			}
+			break
+		} else if err != nil {
+			return withSchemaPath(err, fmt.Sprint("[", i, "]"))
+		}
+	}
+	return s.listEnd(stream)
+}
+
+// validateMap 方法检查由[key, value]组成的列表，key必须按照编码结果严格升序排列。
+func (s *Schema) validateMap(stream *Stream) error {
+	if _, err := stream.ListStart(); err != nil {
+		return s.listError(err)
+	}
+	var prevKey []byte
+	for i := 0; ; i++ {
+		ctx := fmt.Sprint("[", i, "]")
+		if _, err := stream.ListStart(); err == EOL {
+			break
+		} else if err != nil {
+			return withSchemaPath(s.mismatch(err), ctx)
+		}
+		key, err := stream.Raw()
+		if err == EOL {
+			return withSchemaPath(s.mismatch(errTooFewElements), ctx)
+		} else if err != nil {
+			return withSchemaPath(s.mismatch(err), ctx)
+		}
+		if err = s.Key.Validate(key); err != nil {
+			return withSchemaPath(err, ctx+".key")
+		}
+		if prevKey != nil {
+			switch c := bytes.Compare(prevKey, key); {
+			case c == 0:
+				return withSchemaPath(s.mismatch(ErrMapKeyDuplicate), ctx)
+			case c > 0:
+				return withSchemaPath(s.mismatch(ErrMapKeyOrder), ctx)
+			}
+		}
+		prevKey = key
+		if err = s.Elem.validate(stream); err == EOL {
+			return withSchemaPath(s.mismatch(errTooFewElements), ctx)
+		} else if err != nil {
+			return withSchemaPath(err, ctx+".value")
+		}
+		if err = s.listEnd(stream); err != nil {
+			return withSchemaPath(err, ctx)
+		}
+	}
+	return s.listEnd(stream)
+}
+
+// validateStruct ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// validateStruct 方法按照字段的顺序检查结构体的元素：内容耗尽时，如果下一个字段是"optional"字段则检查结束，否则说明元素
+// 太少；"tail"字段收集剩余的所有元素；设置了"nil"类标签的字段还可以是类型为 NilKind 的空值。
+func (s *Schema) validateStruct(stream *Stream) error {
+	if _, err := stream.ListStart(); err != nil {
+		return s.listError(err)
+	}
+	for _, f := range s.Struct.Fields {
+		if f.Tail {
+			for i := 0; ; i++ {
+				if err := f.Type.Elem.validate(stream); err == EOL {
+					break
+				} else if err != nil {
+					return withSchemaPath(err, fmt.Sprint(".", f.Name, "[", i, "]"))
+				}
+			}
+			break
+		}
+		if err := f.validate(stream); err == EOL {
+			if f.Optional {
+				break
+			}
+			return s.mismatch(errTooFewElements)
+		} else if err != nil {
+			return withSchemaPath(err, "."+f.Name)
+		}
+	}
+	return s.listEnd(stream)
+}
+
+// validate 方法检查结构体里的一个字段，可以为空的字段遇到空值时还要检查空值的类型是否正确。
+func (f FieldSchema) validate(stream *Stream) error {
+	if f.Nullable {
+		kind, size, err := stream.Kind()
+		if err != nil {
+			if err == EOL {
+				return err
+			}
+			return f.Type.mismatch(err)
+		}
+		if kind != Byte && size == 0 {
+			if kind != f.NilKind {
+				return f.Type.mismatch(fmt.Errorf("wrong kind of empty value (got %v, want %v)", kind, f.NilKind))
+			}
+			stream.kind = -1
+			return nil
+		}
+	}
+	return f.Type.validate(stream)
+}
+
+// listError 方法包装 ListStart 返回的错误，EOL 需要原样返回给外层的列表。
+func (s *Schema) listError(err error) error {
+	if err == EOL {
+		return err
+	}
+	return s.mismatch(err)
+}
+
+// listEnd 方法结束当前的列表，如果列表里还有没被读取的元素，则返回元素太多的错误。
+func (s *Schema) listEnd(stream *Stream) error {
+	if err := stream.ListEnd(); err == errNotAtEOL {
+		return s.mismatch(errTooManyElements)
+	} else if err != nil {
+		return s.mismatch(err)
+	}
+	return nil
+}
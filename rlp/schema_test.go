@@ -0,0 +1,186 @@
+package rlp
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaOf(t *testing.T) {
+	schema, err := SchemaOf(reflect.TypeOf([]jsonOuter{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "[]jsonOuter", schema.String())
+	assert.Equal(t, `struct jsonOuter {
+	Nonce uint64
+	Delta int64
+	Flag bool
+	Value uint
+	Balance uint256
+	Inner jsonInner nilList
+	Items []jsonInner
+	Labels map[bytes]uint8
+	Raw any
+	Optional uint64 optional
+	Rest []uint16 tail
+}
+
+struct jsonInner {
+	Name bytes
+	Data bytes2
+}
+`, schema.Definitions())
+
+	// 递归的结构体引用同一个定义
+	schema, err = SchemaOf(reflect.TypeOf(Dog{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "struct Dog {\n\tName bytes\n\tChild Dog optional\n}\n", schema.Definitions())
+	assert.True(t, schema.Struct.Fields[1].Type.Struct == schema.Struct)
+
+	// 匿名结构体以及重名的结构体
+	type anon struct {
+		A struct{ X [3]uint16 }
+		B struct {
+			Y int8 `rlp:"signed"`
+		}
+		C *[]byte `rlp:"nilString"`
+	}
+	schema, err = SchemaOf(reflect.TypeOf(anon{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "struct anon {\n\tA Struct\n\tB Struct2\n\tC bytes nilString\n}\n\nstruct Struct {\n\tX [3]uint16\n}\n\nstruct Struct2 {\n\tY int8\n}\n", schema.Definitions())
+
+	_, err = SchemaOf(reflect.TypeOf(map[[2]uint]bool{}))
+	assert.EqualError(t, err, "rlp: map key type [2]uint is not orderable")
+}
+
+func TestParseSchema(t *testing.T) {
+	for _, typ := range []reflect.Type{reflect.TypeOf(jsonOuter{}), reflect.TypeOf([]Dog{}), reflect.TypeOf(map[uint64][4][]*big.Int{})} {
+		want, err := SchemaOf(typ)
+		assert.Nil(t, err)
+		got, err := ParseSchema(want.Definitions(), want.String())
+		assert.Nil(t, err, "%v", typ)
+		assert.Equal(t, want.String(), got.String())
+		assert.Equal(t, want.Definitions(), got.Definitions())
+	}
+
+	// 注释、空行以及前向引用
+	schema, err := ParseSchema(`
+// 一个交易列表
+struct Block {
+	Txs []Tx   // 引用后面定义的结构体
+	Extra bytes32 optional
+}
+
+struct Tx {
+	Nonce uint64
+	To bytes20 nilString
+	Data bytes
+}`, "Block")
+	assert.Nil(t, err)
+	assert.Equal(t, "struct Block {\n\tTxs []Tx\n\tExtra bytes32 optional\n}\n\nstruct Tx {\n\tNonce uint64\n\tTo bytes20 nilString\n\tData bytes\n}\n", schema.Definitions())
+
+	tests := []struct {
+		defs, root string
+		err        string
+	}{
+		{root: "uint7", err: `rlp: invalid schema "uint7": unknown type "uint7"`},
+		{root: "[]", err: `rlp: invalid schema "[]": missing type in ""`},
+		{root: "[x]bool", err: `rlp: invalid schema "[x]bool": invalid list length "x"`},
+		{root: "map[bool]bool", err: `rlp: invalid schema "map[bool]bool": map key type bool is not orderable`},
+		{root: "bytes[]", err: `rlp: invalid schema "bytes[]": unexpected "[]" after type bytes`},
+		{defs: "struct A {\n}\nstruct A {\n}", root: "A", err: `rlp: schema line 3: struct name "A" is already in use`},
+		{defs: "struct bytes {\n}", root: "bool", err: `rlp: schema line 1: struct name "bytes" is already in use`},
+		{defs: "struct A {\n\tX B\n}", root: "A", err: `rlp: schema line 2: field X: unknown type "B"`},
+		{defs: "struct A {\n\tX bool", root: "A", err: `rlp: schema line 1: struct A is not closed`},
+		{defs: "type A {\n}", root: "A", err: `rlp: schema line 1: want "struct Name {"`},
+		{defs: "struct A {\n\tX bool signed\n}", root: "A", err: `rlp: schema line 2: field X: unexpected modifier "signed"`},
+		{defs: "struct A {\n\tX []bool tail\n\tY bool\n}", root: "A", err: `rlp: schema struct A: field X: "tail" must be on the last field`},
+		{defs: "struct A {\n\tX [2]bool tail\n}", root: "A", err: `rlp: schema struct A: field X: "tail" field must be a variable-length list`},
+		{defs: "struct A {\n\tX bool optional\n\tY bool\n}", root: "A", err: `rlp: schema struct A: field Y must be optional because preceding field X is optional`},
+	}
+	for i, test := range tests {
+		_, err := ParseSchema(test.defs, test.root)
+		assert.EqualError(t, err, test.err, "test %d", i)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		typ   reflect.Type
+		input string
+		err   string
+	}{
+		{typ: reflect.TypeOf(uint8(0)), input: "80"},
+		{typ: reflect.TypeOf(uint8(0)), input: "8201 00", err: "rlp: schema mismatch (want uint8): uint overflow"},
+		{typ: reflect.TypeOf(uint16(0)), input: "820001", err: "rlp: schema mismatch (want uint16): non-canonical integer format"},
+		{typ: reflect.TypeOf(uint16(0)), input: "8105", err: "rlp: schema mismatch (want uint16): non-canonical size information"},
+		{typ: reflect.TypeOf(true), input: "02", err: "rlp: schema mismatch (want bool): invalid boolean value: 2"},
+		{typ: reflect.TypeOf([2]byte{}), input: "820102"},
+		{typ: reflect.TypeOf([2]byte{}), input: "83010203", err: "rlp: schema mismatch (want bytes2): input value has wrong size 3, want 2"},
+		{typ: reflect.TypeOf([2]uint{}), input: "c101", err: "rlp: schema mismatch (want [2]uint64): input list has too few elements"},
+		{typ: reflect.TypeOf([2]uint{}), input: "c3010203", err: "rlp: schema mismatch (want [2]uint64): input list has too many elements"},
+		{typ: reflect.TypeOf([]string{}), input: "c3 c0 8101", err: "rlp: schema mismatch at [0] (want bytes): expected String or Byte"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c6 c20101 c20202"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c6 c20202 c20101", err: "rlp: schema mismatch at [1] (want map[bytes]uint64): map keys not in canonical order"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c6 c20101 c20101", err: "rlp: schema mismatch at [1] (want map[bytes]uint64): duplicate map key"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c4 c20101 c0", err: "rlp: schema mismatch at [1] (want map[bytes]uint64): input list has too few elements"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c4 c3c00101", err: "rlp: schema mismatch at [0].key (want bytes): expected String or Byte"},
+		{typ: reflect.TypeOf(map[string]uint{}), input: "c4 c3010101", err: "rlp: schema mismatch at [0] (want map[bytes]uint64): input list has too many elements"},
+		{typ: reflect.TypeOf(Dog{}), input: "c5 826161 c180"},
+		{typ: reflect.TypeOf(Dog{}), input: "c4 826161 c0", err: "rlp: schema mismatch at Child (want Dog): input list has too few elements"},
+		{typ: reflect.TypeOf(Dog{}), input: "c7 826161 c3 80 c1c0", err: "rlp: schema mismatch at Child.Child.Name (want bytes): expected String or Byte"},
+		{typ: reflect.TypeOf(jsonInner{}), input: "c0", err: "rlp: schema mismatch (want jsonInner): input list has too few elements"},
+		{typ: reflect.TypeOf([]jsonInner{}), input: "c6 c5 80 820102 80", err: "rlp: schema mismatch at [0] (want jsonInner): input list has too many elements"},
+		{typ: reflect.TypeOf(jsonOuter{}), input: "c0", err: "rlp: schema mismatch (want jsonOuter): input list has too few elements"},
+		{typ: reflect.TypeOf(struct {
+			X *uint `rlp:"nilString"`
+		}{}), input: "c1c0", err: "rlp: schema mismatch at X (want uint64): wrong kind of empty value (got List, want String)"},
+		{typ: reflect.TypeOf(struct {
+			X    uint
+			Tail []uint16 `rlp:"tail"`
+		}{}), input: "c6 01 02 83010000", err: "rlp: schema mismatch at Tail[1] (want uint16): uint overflow"},
+		{typ: reflect.TypeOf(struct {
+			X    uint
+			Tail []byte `rlp:"tail"`
+		}{}), input: "c4 01 820102"},
+		{typ: reflect.TypeOf(uint(0)), input: "01 02", err: "rlp: schema mismatch (want uint64): input contains more than one value"},
+		{typ: reflect.TypeOf(RawValue{}), input: "", err: "rlp: schema mismatch (want any): EOF"},
+	}
+	for i, test := range tests {
+		schema, err := SchemaOf(test.typ)
+		assert.Nil(t, err, "test %d", i)
+		input := unhex(test.input)
+		err = schema.Validate(input)
+		if test.err == "" {
+			assert.Nil(t, err, "test %d", i)
+		} else {
+			assert.EqualError(t, err, test.err, "test %d", i)
+		}
+		// Validate 与 DecodeBytes 的结论必须一致
+		decErr := DecodeBytes(input, reflect.New(test.typ).Interface())
+		assert.Equal(t, err == nil, decErr == nil, "test %d: validate error %v, decode error %v", i, err, decErr)
+	}
+
+	// 编码结果一定能通过检查
+	value := jsonOuter{Nonce: 1, Delta: -300, Items: []jsonInner{{Name: "x"}}, Labels: map[string]uint8{"a": 1}, Raw: unhex("c0"), Optional: 2, Rest: []uint16{1, 2}}
+	enc, err := EncodeToBytes(&value)
+	assert.Nil(t, err)
+	schema, err := SchemaOf(reflect.TypeOf(value))
+	assert.Nil(t, err)
+	assert.Nil(t, schema.Validate(enc))
+
+	// 错误可以通过 errors.As 和 errors.Is 判断
+	err = schema.Validate(unhex("c3 820001"))
+	var schemaErr *SchemaError
+	assert.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, "Nonce", schemaErr.Path)
+	assert.Equal(t, "uint64", schemaErr.Schema)
+	assert.True(t, errors.Is(err, ErrCanonInt))
+
+	// 多余的数据同样返回 *SchemaError
+	err = schema.Validate(append(enc, 0x01))
+	assert.True(t, errors.As(err, &schemaErr))
+	assert.True(t, errors.Is(err, ErrMoreThanOneValue))
+}
@@ -0,0 +1,391 @@
+package rlp
+
+import (
+	"fmt"
+	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+	"math/big"
+	"reflect"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 全局API
+
+// EncodedSize ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// EncodedSize 方法计算x的编码结果有多少个字节，返回值与 len(EncodeToBytes(x)) 完全相同，但是除了实现了 Encoder 接口却
+// 没有实现 Sizer 接口的类型以外，整个计算过程不会真正地编码，也不会分配存储编码结果的内存。与编码器一样，针对每种类型的计算规
+// 则只会生成一次，然后被缓存在 typeInfo 里。如果x无法被编码，则返回与 EncodeToBytes 相同的错误。
+func EncodedSize(x interface{}) (int, error) {
+	rVal := reflect.ValueOf(x)
+	s, err := cachedSizer(rVal.Type())
+	if err != nil {
+		return 0, err
+	}
+	return s(rVal)
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义 Sizer 接口
+
+// Sizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// 实现了 Encoder 接口的类型可以同时实现 Sizer 接口，EncodedSizeRLP 方法返回的值必须等于 EncodeRLP 方法写入的字节数。没有
+// 实现 Sizer 接口的 Encoder，EncodedSize 只能先调用 EncodeRLP 方法把它编码到一个临时的缓冲区里，再计算缓冲区的长度。对于没
+// 有实现 Encoder 接口的类型，Sizer 接口不会生效，因为它们的编码结果完全由类型本身决定。
+type Sizer interface {
+	EncodedSizeRLP() (int, error)
+}
+
+var sizerInterface = reflect.TypeOf(new(Sizer)).Elem()
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 计算编码结果的长度
+
+// makeSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSizer 方法为typ生成一个计算编码结果长度的函数，case之间的顺序与 makeWriter 完全一致，这样同一种类型一定按照相同的
+// 规则计算长度和编码。
+func makeSizer(typ reflect.Type, tag rlpstruct.Tag) (sizer, error) {
+	kind := typ.Kind()
+	switch {
+	case tag.Signed:
+		return makeSignedSizer(typ)
+	case typ == rawValueType:
+		return sizeRawValue, nil
+	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
+		return sizeBigIntPtr, nil
+	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
+		return sizeBigIntNoPtr, nil
+	case typ == reflect.PtrTo(u256Type):
+		return sizeU256Ptr, nil
+	case typ == u256Type:
+		return sizeU256NoPtr, nil
+	case kind == reflect.Pointer:
+		return makePtrSizer(typ, tag)
+	case reflect.PtrTo(typ).Implements(encoderInterface):
+		return makeEncodeSizer(typ)
+	case isUint(kind):
+		return sizeUint, nil
+	case kind == reflect.Bool:
+		return sizeBool, nil
+	case kind == reflect.String:
+		return sizeString, nil
+	case kind == reflect.Slice && isByte(typ.Elem()):
+		return sizeBytes, nil
+	case kind == reflect.Array && isByte(typ.Elem()):
+		return sizeByteArray, nil
+	case kind == reflect.Slice || kind == reflect.Array:
+		return makeSliceSizer(typ, tag)
+	case kind == reflect.Struct:
+		return makeStructSizer(typ)
+	case kind == reflect.Map:
+		return makeMapSizer(typ)
+	case kind == reflect.Interface:
+		return sizeInterface, nil
+	default:
+		return nil, fmt.Errorf("rlp: type %v is not RLP-serializable", typ)
+	}
+}
+
+// makeSignedSizer 方法与 makeSignedWriter 对应，整数先经过zig-zag变换再计算长度。
+func makeSignedSizer(typ reflect.Type) (sizer, error) {
+	switch {
+	case typ.AssignableTo(reflect.PtrTo(reflect.TypeOf(big.Int{}))):
+		return func(val reflect.Value) (int, error) {
+			ptr := val.Interface().(*big.Int)
+			if ptr == nil {
+				return 1, nil
+			}
+			return bigIntEncSize(zigzagBig(ptr)), nil
+		}, nil
+	case typ.AssignableTo(reflect.TypeOf(big.Int{})):
+		return func(val reflect.Value) (int, error) {
+			i := val.Interface().(big.Int)
+			return bigIntEncSize(zigzagBig(&i)), nil
+		}, nil
+	case isInt(typ.Kind()):
+		return func(val reflect.Value) (int, error) {
+			return uintEncSize(zigzag(val.Int())), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("rlp: type %v does not support the \"signed\" tag", typ)
+	}
+}
+
+// sizeRawValue 方法返回 RawValue 自身的长度。
+func sizeRawValue(val reflect.Value) (int, error) {
+	return val.Len(), nil
+}
+
+// sizeBigIntPtr 方法与 writeBigIntPtr 对应，nil指针被编码为0x80。
+func sizeBigIntPtr(val reflect.Value) (int, error) {
+	ptr := val.Interface().(*big.Int)
+	if ptr == nil {
+		return 1, nil
+	}
+	if ptr.Sign() == -1 {
+		return 0, ErrNegativeBigInt
+	}
+	return bigIntEncSize(ptr), nil
+}
+
+// sizeBigIntNoPtr 方法与 writeBigIntNoPtr 对应。
+func sizeBigIntNoPtr(val reflect.Value) (int, error) {
+	i := val.Interface().(big.Int)
+	if i.Sign() == -1 {
+		return 0, ErrNegativeBigInt
+	}
+	return bigIntEncSize(&i), nil
+}
+
+// sizeU256Ptr 方法与 writeU256Ptr 对应，nil指针被编码为0x80。
+func sizeU256Ptr(val reflect.Value) (int, error) {
+	ptr := val.Interface().(*U256)
+	if ptr == nil {
+		return 1, nil
+	}
+	return u256EncSize(ptr), nil
+}
+
+// sizeU256NoPtr 方法与 writeU256NoPtr 对应，同样直接从 reflect.Value 里读出4个64位无符号整数，避免分配内存。
+func sizeU256NoPtr(val reflect.Value) (int, error) {
+	var z U256
+	for i := range z {
+		z[i] = val.Index(i).Uint()
+	}
+	return u256EncSize(&z), nil
+}
+
+// makePtrSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makePtrSizer 方法与 makePtrWriter 对应，非空指针的长度就是它所指向的值的长度，空指针被编码为0x80或者0xC0，长度都是1。
+func makePtrSizer(typ reflect.Type, tag rlpstruct.Tag) (sizer, error) {
+	info := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tag{})
+	if info.sizerErr != nil {
+		return nil, info.sizerErr
+	}
+	var s sizer = func(value reflect.Value) (int, error) {
+		if ev := value.Elem(); ev.IsValid() {
+			return info.sizer(ev)
+		}
+		return 1, nil
+	}
+	return s, nil
+}
+
+// makeEncodeSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeEncodeSizer 方法为实现了 Encoder 接口的类型生成计算长度的函数。如果该类型（或者它的指针）实现了 Sizer 接口，则直接
+// 调用 EncodedSizeRLP 方法，否则只能调用 EncodeRLP 方法把值编码到一个临时的 *encBuffer 里，然后返回编码结果的长度。
+func makeEncodeSizer(typ reflect.Type) (sizer, error) {
+	switch {
+	case typ.Implements(sizerInterface):
+		return func(value reflect.Value) (int, error) {
+			return value.Interface().(Sizer).EncodedSizeRLP()
+		}, nil
+	case reflect.PtrTo(typ).Implements(sizerInterface) && reflect.PtrTo(typ).Implements(encoderInterface) && !typ.Implements(encoderInterface):
+		return func(value reflect.Value) (int, error) {
+			if !value.CanAddr() {
+				return 0, fmt.Errorf("rlp: unadressable value of type %v, EncodeRLP is pointer method", value.Type())
+			}
+			return value.Addr().Interface().(Sizer).EncodedSizeRLP()
+		}, nil
+	}
+	w, err := makeEncodeWriter(typ)
+	if err != nil {
+		return nil, err
+	}
+	var s sizer = func(value reflect.Value) (int, error) {
+		buf := getEncBuffer()
+		defer encBufferPool.Put(buf)
+		if err := w(value, buf); err != nil {
+			return 0, err
+		}
+		return buf.size(), nil
+	}
+	return s, nil
+}
+
+// sizeUint 方法与 writeUint 对应。
+func sizeUint(val reflect.Value) (int, error) {
+	return uintEncSize(val.Uint()), nil
+}
+
+// sizeBool 方法与 writeBool 对应，true被编码为0x01，false被编码为0x80。
+func sizeBool(val reflect.Value) (int, error) {
+	return 1, nil
+}
+
+// sizeString 方法与 writeString 对应。
+func sizeString(val reflect.Value) (int, error) {
+	s := val.String()
+	if len(s) == 1 && s[0] < 0x80 {
+		return 1, nil
+	}
+	return headSize(uint64(len(s))) + len(s), nil
+}
+
+// sizeBytes 方法与 writeBytes 对应。
+func sizeBytes(val reflect.Value) (int, error) {
+	return bytesEncSize(val.Bytes()), nil
+}
+
+// sizeByteArray 方法与 makeByteArrayWriter 生成的编码器对应，长度为1的数组需要看里面的字节是否小于0x80。
+func sizeByteArray(val reflect.Value) (int, error) {
+	n := val.Len()
+	if n == 1 && val.Index(0).Uint() < 0x80 {
+		return 1, nil
+	}
+	return headSize(uint64(n)) + n, nil
+}
+
+// sizeInterface 方法与 writeInterface 对应，nil接口被编码为空列表0xC0。
+func sizeInterface(val reflect.Value) (int, error) {
+	if val.IsNil() {
+		return 1, nil
+	}
+	eval := val.Elem()
+	s, err := cachedSizer(eval.Type())
+	if err != nil {
+		return 0, err
+	}
+	return s(eval)
+}
+
+// makeSliceSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSliceSizer 方法与 makeSliceWriter 对应，先把所有元素的长度加起来得到列表内容的长度，再加上列表头的长度，设置了"tail"
+// 标签的切片没有列表头。
+func makeSliceSizer(typ reflect.Type, tag rlpstruct.Tag) (sizer, error) {
+	info := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tag{})
+	if info.sizerErr != nil {
+		return nil, info.sizerErr
+	}
+	var s sizer = func(value reflect.Value) (int, error) {
+		total := 0
+		for i := 0; i < value.Len(); i++ {
+			size, err := info.sizer(value.Index(i))
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		if tag.Tail {
+			return total, nil
+		}
+		return headSize(uint64(total)) + total, nil
+	}
+	return s, nil
+}
+
+// makeMapSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeMapSizer 方法与 makeMapWriter 对应。key的编码结果很短，并且需要靠它们来检查是否有两个key的编码结果相同，所以这里仍
+// 然调用 sortMapEntries 方法对key进行编码，value则只计算长度。
+func makeMapSizer(typ reflect.Type) (sizer, error) {
+	if !isOrderableMapKey(typ.Key()) {
+		return nil, fmt.Errorf("rlp: map key type %v is not orderable", typ.Key())
+	}
+	keyInfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tag{})
+	if keyInfo.writerErr != nil {
+		return nil, keyInfo.writerErr
+	}
+	elemInfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tag{})
+	if elemInfo.sizerErr != nil {
+		return nil, elemInfo.sizerErr
+	}
+	var s sizer = func(value reflect.Value) (int, error) {
+		if value.Len() == 0 {
+			return 1, nil
+		}
+		entries, err := sortMapEntries(value, keyInfo.writer)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		for _, entry := range entries {
+			size, err := elemInfo.sizer(entry.value)
+			if err != nil {
+				return 0, err
+			}
+			pair := len(entry.key) + size
+			total += headSize(uint64(pair)) + pair
+		}
+		return headSize(uint64(total)) + total, nil
+	}
+	return s, nil
+}
+
+// makeStructSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeStructSizer 方法与 makeStructWriter 对应，末尾值为空的"optional"字段不参与编码，也就不计算它们的长度。
+func makeStructSizer(typ reflect.Type) (sizer, error) {
+	fields, err := processStructFields(typ)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.info.sizerErr != nil {
+			return nil, structFieldError{typ, f.index, f.info.sizerErr}
+		}
+	}
+	firstOptional := firstOptionalField(fields)
+	var s sizer = func(value reflect.Value) (int, error) {
+		last := len(fields) - 1
+		for ; last >= firstOptional; last-- {
+			if !value.Field(fields[last].index).IsZero() {
+				break
+			}
+		}
+		total := 0
+		for i := 0; i <= last; i++ {
+			size, err := fields[i].info.sizer(value.Field(fields[i].index))
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		return headSize(uint64(total)) + total, nil
+	}
+	return s, nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 基本类型的编码长度
+
+// uintEncSize 方法返回 encBuffer.writeUint64 写入的字节数。
+func uintEncSize(i uint64) int {
+	if i < 0x80 {
+		return 1
+	}
+	return 1 + intSize(i)
+}
+
+// bytesEncSize 方法返回 encBuffer.writeBytes 写入的字节数。
+func bytesEncSize(bz []byte) int {
+	if len(bz) == 1 && bz[0] < 0x80 {
+		return 1
+	}
+	return headSize(uint64(len(bz))) + len(bz)
+}
+
+// bigIntEncSize 方法返回 encBuffer.writeBigInt 写入的字节数。
+func bigIntEncSize(i *big.Int) int {
+	if i.BitLen() <= 64 {
+		return uintEncSize(i.Uint64())
+	}
+	n := (i.BitLen() + 7) / 8
+	return headSize(uint64(n)) + n
+}
+
+// u256EncSize 方法返回 encBuffer.writeUint256 写入的字节数。
+func u256EncSize(z *U256) int {
+	if z.IsUint64() {
+		return uintEncSize(z.Uint64())
+	}
+	return 1 + z.ByteLen()
+}
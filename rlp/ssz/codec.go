@@ -0,0 +1,552 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 基本类型
+
+// offsetSize 可变长度的值在容器和列表里占用的偏移量的字节数。
+const offsetSize = 4
+
+// makeBasic ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeBasic 方法为基本类型生成编解码器：布尔值被编码成0x00或0x01，无符号整数以小端序被编码成size个字节，哈希树根就是编码
+// 结果右侧补零到32个字节。
+func (info *typeInfo) makeBasic(typ reflect.Type, tag sszTag, size uint64) error {
+	if err := noTag(typ, tag); err != nil {
+		return err
+	}
+	info.size, info.basic = size, true
+	info.marshal = func(buf []byte, val reflect.Value) ([]byte, error) {
+		return appendBasic(buf, val), nil
+	}
+	info.unmarshal = func(bz []byte, val reflect.Value) error {
+		if uint64(len(bz)) != size {
+			return wrapError(ErrSize, typ)
+		}
+		return readBasic(bz, val)
+	}
+	info.hash = func(val reflect.Value) ([32]byte, error) {
+		var chunk [32]byte
+		appendBasic(chunk[:0], val)
+		return chunk, nil
+	}
+	return nil
+}
+
+// appendBasic 方法将基本类型的值val以小端序追加到buf后面。
+func appendBasic(buf []byte, val reflect.Value) []byte {
+	switch val.Kind() {
+	case reflect.Bool:
+		if val.Bool() {
+			return append(buf, 0x01)
+		}
+		return append(buf, 0x00)
+	case reflect.Uint8:
+		return append(buf, byte(val.Uint()))
+	case reflect.Uint16:
+		return binary.LittleEndian.AppendUint16(buf, uint16(val.Uint()))
+	case reflect.Uint32:
+		return binary.LittleEndian.AppendUint32(buf, uint32(val.Uint()))
+	case reflect.Uint64:
+		return binary.LittleEndian.AppendUint64(buf, val.Uint())
+	default:
+		// rlp.U256，U256[0]是最低的64位，正好是小端序
+		for i := 0; i < 4; i++ {
+			buf = binary.LittleEndian.AppendUint64(buf, val.Index(i).Uint())
+		}
+		return buf
+	}
+}
+
+// readBasic 方法从bz里读取基本类型的值到val里，bz的长度已经被检查过了。
+func readBasic(bz []byte, val reflect.Value) error {
+	switch val.Kind() {
+	case reflect.Bool:
+		if bz[0] > 1 {
+			return wrapError(ErrBool, val.Type())
+		}
+		val.SetBool(bz[0] == 1)
+	case reflect.Uint8:
+		val.SetUint(uint64(bz[0]))
+	case reflect.Uint16:
+		val.SetUint(uint64(binary.LittleEndian.Uint16(bz)))
+	case reflect.Uint32:
+		val.SetUint(uint64(binary.LittleEndian.Uint32(bz)))
+	case reflect.Uint64:
+		val.SetUint(binary.LittleEndian.Uint64(bz))
+	default:
+		for i := 0; i < 4; i++ {
+			val.Index(i).SetUint(binary.LittleEndian.Uint64(bz[i*8:]))
+		}
+	}
+	return nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 向量和列表
+
+// makeArray ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeArray 方法为数组生成编解码器，[N]T总是长度为N的向量，ssz-size标签如果设置了，必须等于N。
+func (info *typeInfo) makeArray(typ reflect.Type, tag sszTag) error {
+	size, max, err := tag.head()
+	if err != nil {
+		return err
+	}
+	n := uint64(typ.Len())
+	if max > 0 || (size > 0 && size != n) {
+		return fmt.Errorf("ssz: tag of array type %v must not set ssz-max or a different ssz-size", typ)
+	}
+	if n == 0 {
+		return fmt.Errorf("ssz: zero-length vector type %v", typ)
+	}
+	return info.makeSequence(typ, tag.elem(), n, false)
+}
+
+// makeSlice ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSlice 方法为切片生成编解码器，设置了ssz-size标签的切片是向量，设置了ssz-max标签的切片是列表，二者必居其一。
+func (info *typeInfo) makeSlice(typ reflect.Type, tag sszTag) error {
+	size, max, err := tag.head()
+	if err != nil {
+		return err
+	}
+	switch {
+	case size > 0:
+		return info.makeSequence(typ, tag.elem(), size, false)
+	case max > 0:
+		return info.makeSequence(typ, tag.elem(), max, true)
+	default:
+		return fmt.Errorf("ssz: slice type %v needs an ssz-size or ssz-max tag", typ)
+	}
+}
+
+// makeSequence ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeSequence 方法为向量（isList为false，长度必须等于n）和列表（isList为true，长度不能超过n）生成编解码器。固定长度元素
+// 的编码结果被直接拼接在一起；可变长度元素的前面先放n个4字节的偏移量，偏移量从序列编码的起始位置开始计算。元素的
+// typeInfo 在生成时可能还没有生成完毕（例如结构体里包含一个由它自己组成的列表），所以元素的布局信息要在运行时读取。
+func (info *typeInfo) makeSequence(typ reflect.Type, elemTag sszTag, n uint64, isList bool) error {
+	elem := theTC.infoWhileGenerating(typ.Elem(), elemTag)
+	if elem.err != nil {
+		return elem.err
+	}
+	isBytes := typ.Elem().Kind() == reflect.Uint8
+	if !isList {
+		if elem.marshal == nil {
+			return fmt.Errorf("ssz: recursive type %v has infinite size", typ.Elem())
+		}
+		if elem.fixed() {
+			info.size = n * elem.size
+		}
+	}
+	checkLen := func(l int) error {
+		if isList && uint64(l) > n {
+			return wrapError(ErrListTooLong, typ)
+		}
+		if !isList && uint64(l) != n {
+			return wrapError(ErrVectorLength, typ)
+		}
+		return nil
+	}
+
+	info.marshal = func(buf []byte, val reflect.Value) ([]byte, error) {
+		if err := checkLen(val.Len()); err != nil {
+			return nil, err
+		}
+		if isBytes {
+			return append(buf, byteSlice(val)...), nil
+		}
+		return marshalElems(buf, val, elem)
+	}
+	info.unmarshal = func(bz []byte, val reflect.Value) error {
+		if info.fixed() && uint64(len(bz)) != info.size {
+			return wrapError(ErrSize, typ)
+		}
+		if isBytes {
+			if err := checkLen(len(bz)); err != nil {
+				return err
+			}
+			if typ.Kind() == reflect.Array {
+				reflect.Copy(val, reflect.ValueOf(bz))
+			} else {
+				val.SetBytes(append([]byte{}, bz...))
+			}
+			return nil
+		}
+		parts, err := splitElems(bz, elem, n, typ)
+		if err != nil {
+			return err
+		}
+		if err = checkLen(len(parts)); err != nil {
+			return err
+		}
+		if typ.Kind() == reflect.Slice {
+			val.Set(reflect.MakeSlice(typ, len(parts), len(parts)))
+		}
+		for i, part := range parts {
+			if err = elem.unmarshal(part, val.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	info.hash = func(val reflect.Value) ([32]byte, error) {
+		if err := checkLen(val.Len()); err != nil {
+			return [32]byte{}, err
+		}
+		var (
+			chunks [][32]byte
+			limit  uint64
+		)
+		switch {
+		case isBytes:
+			chunks, limit = pack(byteSlice(val)), (n+31)/32
+		case elem.basic:
+			bz, err := marshalElems(nil, val, elem)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			chunks, limit = pack(bz), (n*elem.size+31)/32
+		default:
+			chunks, limit = make([][32]byte, val.Len()), n
+			for i := range chunks {
+				root, err := elem.hash(val.Index(i))
+				if err != nil {
+					return [32]byte{}, err
+				}
+				chunks[i] = root
+			}
+		}
+		root := merkleize(chunks, limit)
+		if isList {
+			root = mixInLength(root, uint64(val.Len()))
+		}
+		return root, nil
+	}
+	return nil
+}
+
+// byteSlice 方法返回字节切片或者字节数组val的内容，不可寻址的数组会被复制一份。
+func byteSlice(val reflect.Value) []byte {
+	if val.Kind() == reflect.Slice {
+		return val.Bytes()
+	}
+	if val.CanAddr() {
+		return val.Slice(0, val.Len()).Bytes()
+	}
+	bz := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(bz), val)
+	return bz
+}
+
+// marshalElems ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// marshalElems 方法依次编码序列val里的元素，可变长度的元素前面会先写入它们的偏移量。
+func marshalElems(buf []byte, val reflect.Value, elem *typeInfo) ([]byte, error) {
+	var err error
+	if elem.fixed() {
+		for i := 0; i < val.Len(); i++ {
+			if buf, err = elem.marshal(buf, val.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	start := len(buf)
+	buf = append(buf, make([]byte, offsetSize*val.Len())...)
+	for i := 0; i < val.Len(); i++ {
+		if buf, err = putOffset(buf, start, start+i*offsetSize); err != nil {
+			return nil, err
+		}
+		if buf, err = elem.marshal(buf, val.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// putOffset 方法把当前的编码长度（相对于start）作为偏移量写到buf[pos:]处。
+func putOffset(buf []byte, start, pos int) ([]byte, error) {
+	offset := len(buf) - start
+	if offset > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: offset %d overflows uint32", ErrOffset, offset)
+	}
+	binary.LittleEndian.PutUint32(buf[pos:], uint32(offset))
+	return buf, nil
+}
+
+// splitElems ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// splitElems 方法将序列的编码数据bz切分成一个个元素的编码数据。对于可变长度的元素，第一个偏移量同时给出了偏移量区域的长度，
+// 也就是元素的个数，所有的偏移量必须单调不减，并且不能超出bz的范围。元素个数超过max时直接返回错误，避免为恶意的输入分配
+// 大量的内存。
+func splitElems(bz []byte, elem *typeInfo, max uint64, typ reflect.Type) ([][]byte, error) {
+	if elem.fixed() {
+		if uint64(len(bz))%elem.size != 0 {
+			return nil, wrapError(ErrSize, typ)
+		}
+		count := uint64(len(bz)) / elem.size
+		if count > max {
+			return nil, wrapError(ErrListTooLong, typ)
+		}
+		parts := make([][]byte, count)
+		for i := range parts {
+			parts[i] = bz[uint64(i)*elem.size : uint64(i+1)*elem.size]
+		}
+		return parts, nil
+	}
+	if len(bz) == 0 {
+		return nil, nil
+	}
+	if len(bz) < offsetSize {
+		return nil, wrapError(ErrOffset, typ)
+	}
+	first := uint64(binary.LittleEndian.Uint32(bz))
+	if first == 0 || first%offsetSize != 0 || first > uint64(len(bz)) {
+		return nil, wrapError(ErrOffset, typ)
+	}
+	count := first / offsetSize
+	if count > max {
+		return nil, wrapError(ErrListTooLong, typ)
+	}
+	parts := make([][]byte, count)
+	for i := uint64(0); i < count; i++ {
+		start := uint64(binary.LittleEndian.Uint32(bz[i*offsetSize:]))
+		end := uint64(len(bz))
+		if i+1 < count {
+			end = uint64(binary.LittleEndian.Uint32(bz[(i+1)*offsetSize:]))
+		}
+		if end < start || end > uint64(len(bz)) {
+			return nil, wrapError(ErrOffset, typ)
+		}
+		parts[i] = bz[start:end]
+	}
+	return parts, nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 比特列表
+
+// makeBitlist ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeBitlist 方法为 Bitlist 生成编解码器，nil或者空的 Bitlist 被当作长度为0的比特列表，编码成[0x01]。计算哈希树根时
+// 分隔位会被去掉，剩下的比特位按照(N+255)/256个块的上限进行默克尔化，再混入比特位的数量。
+func (info *typeInfo) makeBitlist(typ reflect.Type, tag sszTag) error {
+	size, max, err := tag.head()
+	if err != nil {
+		return err
+	}
+	if size > 0 || max == 0 || tag.elem() != (sszTag{}) {
+		return fmt.Errorf("ssz: Bitlist needs exactly one ssz-max dimension")
+	}
+	bitlist := func(val reflect.Value) (Bitlist, error) {
+		b := val.Interface().(Bitlist)
+		if len(b) == 0 {
+			return Bitlist{0x01}, nil
+		}
+		if b[len(b)-1] == 0 {
+			return nil, ErrBitlist
+		}
+		if b.Len() > max {
+			return nil, wrapError(ErrListTooLong, typ)
+		}
+		return b, nil
+	}
+	info.marshal = func(buf []byte, val reflect.Value) ([]byte, error) {
+		b, err := bitlist(val)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, b...), nil
+	}
+	info.unmarshal = func(bz []byte, val reflect.Value) error {
+		if len(bz) == 0 {
+			return wrapError(ErrBitlist, typ)
+		}
+		b, err := bitlist(reflect.ValueOf(Bitlist(bz)))
+		if err != nil {
+			return err
+		}
+		val.SetBytes(append([]byte{}, b...))
+		return nil
+	}
+	info.hash = func(val reflect.Value) ([32]byte, error) {
+		b, err := bitlist(val)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		n := b.Len()
+		bits := append([]byte{}, b...)
+		bits[len(bits)-1] &^= 1 << (n % 8)
+		if n%8 == 0 {
+			bits = bits[:len(bits)-1]
+		}
+		return mixInLength(merkleize(pack(bits), (max+255)/256), n), nil
+	}
+	return nil
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 容器
+
+// makeContainer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makeContainer 方法为结构体生成编解码器。容器的编码分为两部分：固定部分依次存放固定长度字段的编码结果和可变长度字段的
+// 偏移量，可变部分依次存放可变长度字段的编码结果，偏移量从容器编码的起始位置开始计算。所有字段都是固定长度时，容器也是
+// 固定长度的。容器的哈希树根是各个字段的哈希树根默克尔化的结果。
+func (info *typeInfo) makeContainer(typ reflect.Type, tag sszTag) error {
+	if err := noTag(typ, tag); err != nil {
+		return err
+	}
+	fields, err := processContainerFields(typ)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("ssz: empty container type %v", typ)
+	}
+	var fixedLen uint64 // 固定部分的长度
+	allFixed := true
+	for _, f := range fields {
+		if f.info.err != nil {
+			return fmt.Errorf("%w (struct field %v.%s)", f.info.err, typ, f.name)
+		}
+		if f.info.marshal == nil {
+			return fmt.Errorf("ssz: recursive type %v has infinite size (struct field %v.%s)", typ.Field(f.index).Type, typ, f.name)
+		}
+		if f.info.fixed() {
+			fixedLen += f.info.size
+		} else {
+			fixedLen += offsetSize
+			allFixed = false
+		}
+	}
+	if allFixed {
+		info.size = fixedLen
+	}
+
+	info.marshal = func(buf []byte, val reflect.Value) ([]byte, error) {
+		var err error
+		start := len(buf)
+		var offsetPos []int
+		for _, f := range fields {
+			if f.info.fixed() {
+				if buf, err = f.info.marshal(buf, val.Field(f.index)); err != nil {
+					return nil, err
+				}
+			} else {
+				offsetPos = append(offsetPos, len(buf))
+				buf = append(buf, 0, 0, 0, 0)
+			}
+		}
+		i := 0
+		for _, f := range fields {
+			if f.info.fixed() {
+				continue
+			}
+			if buf, err = putOffset(buf, start, offsetPos[i]); err != nil {
+				return nil, err
+			}
+			if buf, err = f.info.marshal(buf, val.Field(f.index)); err != nil {
+				return nil, err
+			}
+			i++
+		}
+		return buf, nil
+	}
+	info.unmarshal = func(bz []byte, val reflect.Value) error {
+		if uint64(len(bz)) < fixedLen || (allFixed && uint64(len(bz)) != fixedLen) {
+			return wrapError(ErrSize, typ)
+		}
+		var (
+			pos       uint64
+			offsets   []uint64
+			varFields []containerField
+		)
+		for _, f := range fields {
+			if f.info.fixed() {
+				if err := f.info.unmarshal(bz[pos:pos+f.info.size], val.Field(f.index)); err != nil {
+					return err
+				}
+				pos += f.info.size
+			} else {
+				offsets = append(offsets, uint64(binary.LittleEndian.Uint32(bz[pos:])))
+				varFields = append(varFields, f)
+				pos += offsetSize
+			}
+		}
+		if len(offsets) > 0 && offsets[0] != fixedLen {
+			return wrapError(ErrOffset, typ)
+		}
+		for i, f := range varFields {
+			end := uint64(len(bz))
+			if i+1 < len(offsets) {
+				end = offsets[i+1]
+			}
+			if end < offsets[i] || end > uint64(len(bz)) {
+				return wrapError(ErrOffset, typ)
+			}
+			if err := f.info.unmarshal(bz[offsets[i]:end], val.Field(f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	info.hash = func(val reflect.Value) ([32]byte, error) {
+		roots := make([][32]byte, len(fields))
+		for i, f := range fields {
+			root, err := f.info.hash(val.Field(f.index))
+			if err != nil {
+				return [32]byte{}, err
+			}
+			roots[i] = root
+		}
+		return merkleize(roots, uint64(len(roots))), nil
+	}
+	return nil
+}
+
+// makePtr ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// makePtr 方法为指向结构体的指针生成编解码器，它的布局与结构体完全相同，编码和计算哈希树根时nil指针被当作零值，解码时会
+// 为nil指针分配一个新的结构体。
+func (info *typeInfo) makePtr(typ reflect.Type, tag sszTag) error {
+	elem := theTC.infoWhileGenerating(typ.Elem(), tag)
+	if elem.err != nil {
+		return elem.err
+	}
+	if elem.marshal == nil {
+		return fmt.Errorf("ssz: recursive type %v has infinite size", typ.Elem())
+	}
+	info.size = elem.size
+	deref := func(val reflect.Value) reflect.Value {
+		if val.IsNil() {
+			return reflect.New(typ.Elem()).Elem()
+		}
+		return val.Elem()
+	}
+	info.marshal = func(buf []byte, val reflect.Value) ([]byte, error) {
+		return elem.marshal(buf, deref(val))
+	}
+	info.unmarshal = func(bz []byte, val reflect.Value) error {
+		if val.IsNil() {
+			val.Set(reflect.New(typ.Elem()))
+		}
+		return elem.unmarshal(bz, val.Elem())
+	}
+	info.hash = func(val reflect.Value) ([32]byte, error) {
+		return elem.hash(deref(val))
+	}
+	return nil
+}
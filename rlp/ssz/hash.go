@@ -0,0 +1,72 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 默克尔化
+
+// zeroHashes ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// zeroHashes[i] 是一棵深度为i、所有叶子都是32字节零值的默克尔树的根，zeroHashes[0]就是零值块本身。列表的容量往往远大于
+// 实际的元素个数，默克尔化时右侧空缺的子树直接使用这些预先计算好的哈希值，而不必真的构建出来。
+var zeroHashes [65][32]byte
+
+func init() {
+	for i := 1; i < len(zeroHashes); i++ {
+		zeroHashes[i] = hashPair(zeroHashes[i-1], zeroHashes[i-1])
+	}
+}
+
+// hashPair 方法返回 sha256(a || b)。
+func hashPair(a, b [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], a[:])
+	copy(buf[32:], b[:])
+	return sha256.Sum256(buf[:])
+}
+
+// pack 方法将bz切分成32字节的块，最后一个块右侧补零，空的输入返回nil。
+func pack(bz []byte) [][32]byte {
+	chunks := make([][32]byte, (len(bz)+31)/32)
+	for i := range chunks {
+		copy(chunks[i][:], bz[i*32:])
+	}
+	return chunks
+}
+
+// merkleize ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// merkleize 方法把chunks补齐到不小于limit的最小的2的幂个块，然后两两哈希直到只剩一个根。调用者需要保证 len(chunks) 不超过
+// limit，limit为0时按1处理。
+func merkleize(chunks [][32]byte, limit uint64) [32]byte {
+	depth := 0
+	for uint64(1)<<depth < limit {
+		depth++
+	}
+	if len(chunks) == 0 {
+		return zeroHashes[depth]
+	}
+	layer := append([][32]byte{}, chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := layer[:len(layer)/2]
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// mixInLength 方法返回 sha256(root || uint256(length))，列表的哈希树根需要混入它的长度。
+func mixInLength(root [32]byte, length uint64) [32]byte {
+	var buf [32]byte
+	binary.LittleEndian.PutUint64(buf[:], length)
+	return hashPair(root, buf)
+}
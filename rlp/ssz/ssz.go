@@ -0,0 +1,148 @@
+/*
+ssz 包实现了以太坊共识层使用的SimpleSerialize（SSZ）编码，它与rlp包是兄弟关系：结构体字段的筛选沿用了
+rlpstruct.ProcessFields，类型信息的缓存沿用了rlp包里 typeCache 的写法。SSZ的编码规则在README里有详细介绍。
+*/
+
+package ssz
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// API
+
+// Marshal ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Marshal 方法返回val的SSZ编码结果，val可以是结构体、指向结构体的指针，或者任何 ssz 包支持的类型。
+func Marshal(val interface{}) ([]byte, error) {
+	rVal := reflect.ValueOf(val)
+	if !rVal.IsValid() {
+		return nil, errNilValue
+	}
+	info := cachedTypeInfo(rVal.Type())
+	if info.err != nil {
+		return nil, info.err
+	}
+	return info.marshal(nil, rVal)
+}
+
+// Unmarshal ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Unmarshal 方法将SSZ编码数据bz解码到val里，val必须是一个非nil的指针。SSZ编码不是自描述的，bz必须恰好是一个完整的值，
+// 多余或者缺少的字节都会导致解码失败。
+func Unmarshal(bz []byte, val interface{}) error {
+	rVal := reflect.ValueOf(val)
+	if rVal.Kind() != reflect.Pointer {
+		return errNoPointer
+	}
+	if rVal.IsNil() {
+		return errNilValue
+	}
+	info := cachedTypeInfo(rVal.Type().Elem())
+	if info.err != nil {
+		return info.err
+	}
+	return info.unmarshal(bz, rVal.Elem())
+}
+
+// HashTreeRoot ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// HashTreeRoot 方法计算val的哈希树根（hash_tree_root），共识层里区块、状态等数据的“哈希”指的都是它。
+func HashTreeRoot(val interface{}) ([32]byte, error) {
+	rVal := reflect.ValueOf(val)
+	if !rVal.IsValid() {
+		return [32]byte{}, errNilValue
+	}
+	info := cachedTypeInfo(rVal.Type())
+	if info.err != nil {
+		return [32]byte{}, info.err
+	}
+	return info.hash(rVal)
+}
+
+// Size ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Size 方法返回类型typ的固定编码长度，如果typ是可变长度的类型，则返回0。
+func Size(typ reflect.Type) (uint64, error) {
+	info := cachedTypeInfo(typ)
+	return info.size, info.err
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义全局错误
+
+var (
+	ErrSize         = errors.New("ssz: input size does not match type")
+	ErrOffset       = errors.New("ssz: invalid offset")
+	ErrListTooLong  = errors.New("ssz: list exceeds its maximum length")
+	ErrVectorLength = errors.New("ssz: vector length mismatch")
+	ErrBool         = errors.New("ssz: invalid boolean")
+	ErrBitlist      = errors.New("ssz: invalid bitlist")
+)
+
+// 定义内部错误
+
+var (
+	errNilValue  = errors.New("ssz: nil value")
+	errNoPointer = errors.New("ssz: interface given to Unmarshal must be a pointer")
+)
+
+// wrapError 方法给err附加类型信息，方便定位是哪个类型的数据出了问题。
+func wrapError(err error, typ reflect.Type) error {
+	return fmt.Errorf("%w (decoding into %v)", err, typ)
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// Bitlist ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Bitlist 对应SSZ里的Bitlist[N]类型，必须通过`ssz-max:"N"`标签给出最大长度。它的内容就是编码结果：比特位按照从低到高
+// 的顺序存放，最后一个有效比特位的后面紧跟着一个值为1的分隔位，因此一个合法的 Bitlist 至少有一个字节，并且最后一个字节
+// 不为0。例如长度为3、内容为[1, 0, 1]的 Bitlist 是[0x0D]。
+type Bitlist []byte
+
+// bitlistType = reflect.TypeOf(Bitlist{})
+var bitlistType = reflect.TypeOf(Bitlist{})
+
+// NewBitlist 方法返回一个长度为n、所有比特位都为0的 Bitlist。
+func NewBitlist(n uint64) Bitlist {
+	b := make(Bitlist, n/8+1)
+	b[n/8] = 1 << (n % 8)
+	return b
+}
+
+// Len ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Len 方法返回 Bitlist 里有效比特位的数量（不包括分隔位），如果 Bitlist 不合法，则返回0。
+func (b Bitlist) Len() uint64 {
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return 0
+	}
+	return uint64(len(b)-1)*8 + uint64(bits.Len8(b[len(b)-1])) - 1
+}
+
+// BitAt 方法返回第i个比特位的值，i超出范围时返回false。
+func (b Bitlist) BitAt(i uint64) bool {
+	if i >= b.Len() {
+		return false
+	}
+	return b[i/8]&(1<<(i%8)) != 0
+}
+
+// SetBitAt 方法设置第i个比特位的值，i超出范围时什么也不做。
+func (b Bitlist) SetBitAt(i uint64, v bool) {
+	if i >= b.Len() {
+		return
+	}
+	if v {
+		b[i/8] |= 1 << (i % 8)
+	} else {
+		b[i/8] &^= 1 << (i % 8)
+	}
+}
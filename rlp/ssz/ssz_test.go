@@ -0,0 +1,312 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+// 下面的类型与共识层规范测试（consensus-spec-tests）里ssz_generic测试用例使用的类型一致。
+
+type SingleFieldTestStruct struct {
+	A byte
+}
+
+type SmallTestStruct struct {
+	A uint16
+	B uint16
+}
+
+type FixedTestStruct struct {
+	A uint8
+	B uint64
+	C uint32
+}
+
+type VarTestStruct struct {
+	A uint16
+	B []uint16 `ssz-max:"1024"`
+	C uint8
+}
+
+type ComplexTestStruct struct {
+	A uint16
+	B []uint16 `ssz-max:"128"`
+	C uint8
+	D []byte `ssz-max:"256"`
+	E VarTestStruct
+	F [4]FixedTestStruct
+	G [2]VarTestStruct
+}
+
+type BitlistStruct struct {
+	A Bitlist `ssz-max:"5"`
+	B uint8
+	C Bitlist `ssz-max:"2048"`
+}
+
+type Uint256Struct struct {
+	Balance rlp.U256
+	Roots   [][]byte `ssz-size:"?,32" ssz-max:"16"`
+	Flag    bool
+	Cache   []byte `rlp:"-"`
+	hidden  uint64
+}
+
+type listHolder struct {
+	Items []VarTestStruct `ssz-max:"8"`
+}
+
+type vectorHolder struct {
+	Vec_uint16_3  [3]uint16
+	Vec_bool_5    [5]bool
+	Vec_uint64_9  []uint64 `ssz-size:"9"`
+	ByteVector32  [32]byte
+	ByteList256   []byte   `ssz-max:"256"`
+	ListUint32100 []uint32 `ssz-max:"100"`
+	Bitlist8      Bitlist  `ssz-max:"8"`
+	Bitlist512    Bitlist  `ssz-max:"512"`
+}
+
+// fixtureTypes 将测试用例里的类型名映射为对应的Go类型，没有标签就无法表达的类型借助 vectorHolder 和 listHolder 的字段来获取。
+var fixtureTypes = map[string]reflect.Type{
+	"bool":                  reflect.TypeOf(false),
+	"uint8":                 reflect.TypeOf(uint8(0)),
+	"uint16":                reflect.TypeOf(uint16(0)),
+	"uint32":                reflect.TypeOf(uint32(0)),
+	"uint64":                reflect.TypeOf(uint64(0)),
+	"uint256":               reflect.TypeOf(rlp.U256{}),
+	"SingleFieldTestStruct": reflect.TypeOf(SingleFieldTestStruct{}),
+	"SmallTestStruct":       reflect.TypeOf(SmallTestStruct{}),
+	"FixedTestStruct":       reflect.TypeOf(FixedTestStruct{}),
+	"VarTestStruct":         reflect.TypeOf(VarTestStruct{}),
+	"ComplexTestStruct":     reflect.TypeOf(ComplexTestStruct{}),
+	"BitlistStruct":         reflect.TypeOf(BitlistStruct{}),
+	"Uint256Struct":         reflect.TypeOf(Uint256Struct{}),
+}
+
+// fieldFixture 用于测试 vectorHolder 和 listHolder 里的字段，它们的标签只能通过结构体字段给出。
+var fieldFixture = map[string]string{
+	"Vec_uint16_3":         "Vec_uint16_3",
+	"Vec_bool_5":           "Vec_bool_5",
+	"Vec_uint64_9":         "Vec_uint64_9",
+	"ByteVector32":         "ByteVector32",
+	"ByteList256":          "ByteList256",
+	"List_uint32_100":      "ListUint32100",
+	"Bitlist8":             "Bitlist8",
+	"Bitlist512":           "Bitlist512",
+	"List_VarTestStruct_8": "Items",
+}
+
+type sszFixture struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Valid      bool   `json:"valid"`
+	Serialized string `json:"serialized"`
+	Root       string `json:"root"`
+}
+
+// fixtureCodec 返回测试用例所描述的类型的 typeInfo，以及一个用来存放解码结果的值。
+func fixtureCodec(t *testing.T, name string) (*typeInfo, reflect.Value) {
+	if typ, ok := fixtureTypes[name]; ok {
+		return cachedTypeInfo(typ), reflect.New(typ).Elem()
+	}
+	fieldName, ok := fieldFixture[name]
+	if !ok {
+		t.Fatalf("unknown fixture type %s", name)
+	}
+	holder := reflect.TypeOf(vectorHolder{})
+	if name == "List_VarTestStruct_8" {
+		holder = reflect.TypeOf(listHolder{})
+	}
+	sf, _ := holder.FieldByName(fieldName)
+	info := theTC.info(sf.Type, sszTag{size: sf.Tag.Get("ssz-size"), max: sf.Tag.Get("ssz-max")})
+	assert.Nil(t, info.err)
+	return info, reflect.New(sf.Type).Elem()
+}
+
+func TestGenericFixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/ssz_generic.json")
+	assert.Nil(t, err)
+	var fixtures []sszFixture
+	assert.Nil(t, json.Unmarshal(data, &fixtures))
+	assert.NotEmpty(t, fixtures)
+
+	for _, f := range fixtures {
+		name := f.Type + "/" + f.Name
+		info, val := fixtureCodec(t, f.Type)
+		serialized, _ := hex.DecodeString(f.Serialized)
+		err := info.unmarshal(serialized, val)
+		if !f.Valid {
+			assert.NotNil(t, err, name)
+			continue
+		}
+		assert.Nil(t, err, name)
+		enc, err := info.marshal(nil, val)
+		assert.Nil(t, err, name)
+		assert.Equal(t, f.Serialized, hex.EncodeToString(enc), name)
+		root, err := info.hash(val)
+		assert.Nil(t, err, name)
+		assert.Equal(t, f.Root, hex.EncodeToString(root[:]), name)
+		if info.fixed() {
+			assert.Equal(t, int(info.size), len(serialized), name)
+		}
+	}
+}
+
+func TestMarshalValues(t *testing.T) {
+	// 手工计算的哈希树根：两个字段各占一个块，根就是这两个块的哈希
+	small := SmallTestStruct{A: 0x4567, B: 0x0123}
+	enc, err := Marshal(small)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x67, 0x45, 0x23, 0x01}, enc)
+	var chunks [64]byte
+	chunks[0], chunks[1], chunks[32], chunks[33] = 0x67, 0x45, 0x23, 0x01
+	root, err := HashTreeRoot(&small)
+	assert.Nil(t, err)
+	assert.Equal(t, sha256.Sum256(chunks[:]), root)
+
+	// 可变长度字段的偏移量等于固定部分的长度7
+	v := VarTestStruct{A: 0xabcd, B: []uint16{1, 2, 3}, C: 0xff}
+	enc, err = Marshal(&v)
+	assert.Nil(t, err)
+	assert.Equal(t, "cdab07000000ff010002000300", hex.EncodeToString(enc))
+	var dec VarTestStruct
+	assert.Nil(t, Unmarshal(enc, &dec))
+	assert.Equal(t, v, dec)
+
+	// rlp标签为"-"的字段和未导出的字段不参与编码
+	u := Uint256Struct{Roots: [][]byte{make([]byte, 32)}, Flag: true, Cache: []byte{1}, hidden: 7}
+	u.Balance.SetUint64(1)
+	enc, err = Marshal(u)
+	assert.Nil(t, err)
+	var decU Uint256Struct
+	assert.Nil(t, Unmarshal(enc, &decU))
+	u.Cache, u.hidden = nil, 0
+	assert.Equal(t, u, decU)
+
+	// nil指针被当作零值
+	type ptrHolder struct {
+		P *FixedTestStruct
+		Q uint8
+	}
+	enc1, err := Marshal(ptrHolder{Q: 1})
+	assert.Nil(t, err)
+	enc2, err := Marshal(ptrHolder{P: &FixedTestStruct{}, Q: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, enc1, enc2)
+	var decP ptrHolder
+	assert.Nil(t, Unmarshal(enc1, &decP))
+	assert.Equal(t, &FixedTestStruct{}, decP.P)
+
+	size, err := Size(reflect.TypeOf(FixedTestStruct{}))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(13), size)
+	size, err = Size(reflect.TypeOf(ComplexTestStruct{}))
+	assert.Nil(t, err)
+	assert.Zero(t, size)
+}
+
+func TestBitlist(t *testing.T) {
+	b := NewBitlist(10)
+	assert.Equal(t, uint64(10), b.Len())
+	b.SetBitAt(0, true)
+	b.SetBitAt(9, true)
+	b.SetBitAt(10, true) // 超出范围，什么也不做
+	assert.Equal(t, Bitlist{0x01, 0x06}, b)
+	assert.True(t, b.BitAt(9))
+	assert.False(t, b.BitAt(1))
+	b.SetBitAt(9, false)
+	assert.Equal(t, Bitlist{0x01, 0x04}, b)
+	assert.Equal(t, uint64(0), Bitlist{}.Len())
+	assert.Equal(t, uint64(0), Bitlist{0x00}.Len())
+
+	// 超出最大长度的比特列表无法被编码
+	_, err := Marshal(BitlistStruct{A: NewBitlist(6)})
+	assert.True(t, errors.Is(err, ErrListTooLong))
+	_, err = Marshal(BitlistStruct{A: Bitlist{0x01, 0x00}})
+	assert.True(t, errors.Is(err, ErrBitlist))
+}
+
+func TestEncodeErrors(t *testing.T) {
+	_, err := Marshal(VarTestStruct{B: make([]uint16, 1025)})
+	assert.True(t, errors.Is(err, ErrListTooLong))
+	_, err = Marshal(Uint256Struct{Roots: [][]byte{{1, 2, 3}}})
+	assert.True(t, errors.Is(err, ErrVectorLength))
+	_, err = HashTreeRoot(VarTestStruct{B: make([]uint16, 1025)})
+	assert.True(t, errors.Is(err, ErrListTooLong))
+
+	var v VarTestStruct
+	assert.NotNil(t, Unmarshal([]byte{0x00}, v))
+	assert.NotNil(t, Unmarshal([]byte{0x00}, (*VarTestStruct)(nil)))
+	_, err = Marshal(nil)
+	assert.NotNil(t, err)
+}
+
+func TestInvalidTypes(t *testing.T) {
+	tests := []interface{}{
+		struct{ A []uint16 }{}, // 切片必须设置ssz-size或ssz-max
+		struct{}{},             // 空容器
+		struct{ A [0]uint8 }{}, // 长度为0的向量
+		struct{ A int }{},      // 有符号整数
+		struct{ A string }{},   // 字符串
+		struct{ A uint }{},     // 与平台相关的整数
+		struct{ A *uint64 }{},  // 只支持指向结构体的指针
+		struct{ A Bitlist }{},  // Bitlist 必须设置ssz-max
+		struct {
+			A uint64 `ssz-max:"4"`
+		}{}, // 基本类型不能设置标签
+		struct {
+			A []byte `ssz-max:"4" ssz-size:"4"`
+		}{}, // 同一个维度不能同时设置两个标签
+		struct {
+			A [4]byte `ssz-size:"5"`
+		}{}, // 与数组长度不一致
+		struct {
+			A []byte `ssz-max:"x"`
+		}{}, // 无法解析的标签
+		struct {
+			A uint64 `rlp:"optional"`
+		}{}, // rlp专有的标签
+	}
+	for _, test := range tests {
+		_, err := Marshal(test)
+		assert.NotNil(t, err, "%T", test)
+		_, err = Size(reflect.TypeOf(test))
+		assert.NotNil(t, err, "%T", test)
+	}
+}
+
+type recursiveList struct {
+	Value uint64
+	Kids  []recursiveList `ssz-max:"4"`
+}
+
+type recursivePtr struct {
+	Value uint64
+	Next  *recursivePtr
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	// 与rlp包一样，空列表被解码成长度为0的非nil切片
+	leaf := []recursiveList{}
+	tree := recursiveList{Value: 1, Kids: []recursiveList{{Value: 2, Kids: leaf}, {Value: 3, Kids: []recursiveList{{Value: 4, Kids: leaf}}}}}
+	enc, err := Marshal(tree)
+	assert.Nil(t, err)
+	var dec recursiveList
+	assert.Nil(t, Unmarshal(enc, &dec))
+	assert.Equal(t, tree, dec)
+	_, err = HashTreeRoot(tree)
+	assert.Nil(t, err)
+
+	// 通过指针引用自己的类型具有无穷大的编码长度
+	_, err = Marshal(recursivePtr{})
+	assert.NotNil(t, err)
+}
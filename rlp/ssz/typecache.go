@@ -0,0 +1,280 @@
+package ssz
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 定义编码器、解码器和哈希器
+
+// marshaler 将val的编码结果追加到buf后面，并返回追加后的buf。
+type marshaler func(buf []byte, val reflect.Value) ([]byte, error)
+
+// unmarshaler 将bz解码到val里，bz恰好是一个完整的值的编码。
+type unmarshaler func(bz []byte, val reflect.Value) error
+
+// hasher 计算val的哈希树根。
+type hasher func(val reflect.Value) ([32]byte, error)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// typeInfo ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// typeInfo 维护了针对某个特定类型的布局信息和编码器、解码器、哈希器。SSZ里的类型分为固定长度和可变长度两种，固定长度类型
+// 的编码长度记录在 size 字段里；可变长度类型的 size 为0，它们在容器和列表里以4字节的偏移量占位，真正的内容放在所有固定
+// 部分的后面。SSZ不存在编码长度为0的固定长度类型（空容器和长度为0的向量都是非法的），所以可以用0来表示可变长度。
+type typeInfo struct {
+	size      uint64
+	basic     bool // 布尔值和无符号整数是基本类型，计算哈希树根时它们会被紧凑地打包进32字节的块里
+	marshal   marshaler
+	unmarshal unmarshaler
+	hash      hasher
+	err       error
+}
+
+// fixed 方法判断该类型是否是固定长度类型。
+func (info *typeInfo) fixed() bool {
+	return info.size > 0
+}
+
+// typeKey ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// typeKey 与rlp包里的同名结构体一样，由类型和标签组成：同一个Go类型在不同的标签下可能是不同的SSZ类型，例如[]byte既可以
+// 是`ssz-max:"32"`的ByteList，也可以是`ssz-size:"32"`的ByteVector。
+type typeKey struct {
+	reflect.Type
+	sszTag
+}
+
+// typeCache ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// typeCache 的写法与rlp包里的 typeCache 完全一样：读取时无锁地访问 cur，生成新的 typeInfo 时加锁，把 cur 复制到 next
+// 里，生成结束之后再用 next 替换 cur。生成过程中 typeInfo 会先被放进 next，因此自引用的类型也能正确地生成。
+type typeCache struct {
+	cur  atomic.Value
+	mu   sync.Mutex
+	next map[typeKey]*typeInfo
+}
+
+// theTC 是包级别的类型缓存。
+var theTC = newTypeCache()
+
+// newTypeCache 方法返回一个空的类型缓存。
+func newTypeCache() *typeCache {
+	c := new(typeCache)
+	c.cur.Store(make(map[typeKey]*typeInfo))
+	return c
+}
+
+// cachedTypeInfo 方法返回不带标签的类型typ的 typeInfo。
+func cachedTypeInfo(typ reflect.Type) *typeInfo {
+	return theTC.info(typ, sszTag{})
+}
+
+// info 方法先从 cur 里寻找 typeInfo，找不到的话就现场生成。
+func (tc *typeCache) info(typ reflect.Type, tag sszTag) *typeInfo {
+	cur := tc.cur.Load().(map[typeKey]*typeInfo)
+	if info := cur[typeKey{typ, tag}]; info != nil {
+		return info
+	}
+	return tc.generate(typ, tag)
+}
+
+// generate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// generate 方法在锁的保护下生成typ的 typeInfo，生成过程中产生的所有 typeInfo 都会先被存放在 next 里，最后一次性地替换 cur。
+func (tc *typeCache) generate(typ reflect.Type, tag sszTag) *typeInfo {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	cur := tc.cur.Load().(map[typeKey]*typeInfo)
+	if info := cur[typeKey{typ, tag}]; info != nil {
+		return info
+	}
+	tc.next = make(map[typeKey]*typeInfo, len(cur)+1)
+	for k, v := range cur {
+		tc.next[k] = v
+	}
+	info := tc.infoWhileGenerating(typ, tag)
+	tc.cur.Store(tc.next)
+	tc.next = nil
+	return info
+}
+
+// infoWhileGenerating 方法在生成过程中获取typ的 typeInfo，先到 next 里找，找不到就创建一个新的并立即放进 next 里。
+func (tc *typeCache) infoWhileGenerating(typ reflect.Type, tag sszTag) *typeInfo {
+	key := typeKey{typ, tag}
+	if info := tc.next[key]; info != nil {
+		return info
+	}
+	info := new(typeInfo)
+	tc.next[key] = info
+	info.err = info.generate(typ, tag)
+	return info
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 标签
+
+// sszTag ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// sszTag 记录了结构体字段上的`ssz-size`和`ssz-max`标签，两个标签的值都可以由逗号分隔成多个维度，第一个维度描述字段本身，
+// 第二个维度描述字段的元素，以此类推，"?"或者空表示该维度没有设置，例如[][]byte类型的字段可以这样设置标签：
+//
+//	Roots [][]byte `ssz-size:"?,32" ssz-max:"16"`
+//
+// 表示 Roots 是一个最多包含16个元素的列表，每个元素是长度为32的字节向量。
+type sszTag struct {
+	size string
+	max  string
+}
+
+// head 方法解析第一个维度的size和max，没有设置的维度返回0。
+func (t sszTag) head() (size, max uint64, err error) {
+	if size, err = parseDim(t.size); err != nil {
+		return 0, 0, err
+	}
+	if max, err = parseDim(t.max); err != nil {
+		return 0, 0, err
+	}
+	if size > 0 && max > 0 {
+		return 0, 0, fmt.Errorf("ssz: both ssz-size and ssz-max are set on the same dimension")
+	}
+	return size, max, nil
+}
+
+// elem 方法去掉第一个维度，返回描述元素的标签。
+func (t sszTag) elem() sszTag {
+	return sszTag{size: restDims(t.size), max: restDims(t.max)}
+}
+
+// parseDim 方法解析第一个维度的数值。
+func parseDim(s string) (uint64, error) {
+	s, _, _ = strings.Cut(s, ",")
+	if s = strings.TrimSpace(s); s == "" || s == "?" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("ssz: invalid dimension %q in tag", s)
+	}
+	return n, nil
+}
+
+// restDims 方法返回去掉第一个维度之后的标签值。
+func restDims(s string) string {
+	_, rest, _ := strings.Cut(s, ",")
+	return rest
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 生成 typeInfo
+
+// u256Type = reflect.TypeOf(rlp.U256{})
+var u256Type = reflect.TypeOf(rlp.U256{})
+
+// generate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// generate 方法根据typ和tag确定SSZ类型并生成对应的编码器、解码器和哈希器：
+//   - bool、uint8、uint16、uint32、uint64和 rlp.U256 是基本类型，rlp.U256 对应uint256；
+//   - [N]T是长度为N的向量，[]T设置了ssz-size标签时也是向量，设置了ssz-max标签时是列表，元素为byte时分别是ByteVector和ByteList；
+//   - Bitlist 是比特列表，必须设置ssz-max标签；
+//   - 结构体和指向结构体的指针是容器，nil指针被当作零值处理。
+func (info *typeInfo) generate(typ reflect.Type, tag sszTag) error {
+	kind := typ.Kind()
+	switch {
+	case typ == u256Type:
+		return info.makeBasic(typ, tag, 32)
+	case typ == bitlistType:
+		return info.makeBitlist(typ, tag)
+	case kind == reflect.Bool || kind == reflect.Uint8:
+		return info.makeBasic(typ, tag, 1)
+	case kind == reflect.Uint16:
+		return info.makeBasic(typ, tag, 2)
+	case kind == reflect.Uint32:
+		return info.makeBasic(typ, tag, 4)
+	case kind == reflect.Uint64:
+		return info.makeBasic(typ, tag, 8)
+	case kind == reflect.Array:
+		return info.makeArray(typ, tag)
+	case kind == reflect.Slice:
+		return info.makeSlice(typ, tag)
+	case kind == reflect.Struct:
+		return info.makeContainer(typ, tag)
+	case kind == reflect.Pointer && typ.Elem().Kind() == reflect.Struct:
+		return info.makePtr(typ, tag)
+	default:
+		return fmt.Errorf("ssz: type %v is not SSZ-serializable", typ)
+	}
+}
+
+// noTag 方法检查不需要标签的类型上没有多余的标签。
+func noTag(typ reflect.Type, tag sszTag) error {
+	if tag != (sszTag{}) {
+		return fmt.Errorf("ssz: ssz-size/ssz-max tag is not applicable to type %v", typ)
+	}
+	return nil
+}
+
+// containerField 描述容器里参与编码的一个字段。
+type containerField struct {
+	index int
+	name  string
+	info  *typeInfo
+}
+
+// processContainerFields ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// processContainerFields 方法借助 rlpstruct.ProcessFields 筛选出结构体里参与编码的字段：未导出的字段和设置了`rlp:"-"`
+// 的字段会被忽略。"optional"、"tail"、"nil"和"signed"这些rlp专有的标签在SSZ里没有对应的含义，设置了它们的字段会导致错误。
+func processContainerFields(typ reflect.Type) ([]containerField, error) {
+	var allFields []rlpstruct.Field
+	for i := 0; i < typ.NumField(); i++ {
+		rf := typ.Field(i)
+		allFields = append(allFields, rlpstruct.Field{
+			Name:     rf.Name,
+			Index:    i,
+			Exported: rf.IsExported(),
+			Type:     *reflectTypeToStructType(rf.Type),
+			Tag:      string(rf.Tag),
+		})
+	}
+	fields, tags, err := rlpstruct.ProcessFields(allFields)
+	if err != nil {
+		if tagErr, ok := err.(rlpstruct.TagError); ok {
+			tagErr.StructType = typ.String()
+			return nil, tagErr
+		}
+		return nil, err
+	}
+	var result []containerField
+	for i, f := range fields {
+		if tags[i] != (rlpstruct.Tag{}) {
+			return nil, fmt.Errorf("ssz: rlp tag %q of %v.%s has no SSZ equivalent", reflect.StructTag(f.Tag).Get("rlp"), typ, f.Name)
+		}
+		sf := typ.Field(f.Index)
+		tag := sszTag{size: sf.Tag.Get("ssz-size"), max: sf.Tag.Get("ssz-max")}
+		info := theTC.infoWhileGenerating(sf.Type, tag)
+		result = append(result, containerField{index: f.Index, name: f.Name, info: info})
+	}
+	return result, nil
+}
+
+// reflectTypeToStructType 方法将 reflect.Type 转换为 rlpstruct.Type，结构体不会被继续展开，所以不会无限递归。
+func reflectTypeToStructType(typ reflect.Type) *rlpstruct.Type {
+	t := &rlpstruct.Type{Name: typ.Name(), Kind: typ.Kind()}
+	if typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Ptr {
+		t.Elem = reflectTypeToStructType(typ.Elem())
+	}
+	return t
+}
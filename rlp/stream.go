@@ -56,6 +56,30 @@ type Stream struct {
 	kind         Kind
 	byteVal      byte // 类型标签中的值，例如0xC0或者0x87等等
 	limited      bool
+	pos          uint64        // 已经从底层输入读取的字节数，即下一个字节在输入数据里的绝对偏移量
+	valuePos     uint64        // 最近一次被 Kind 方法解析的编码前缀在输入数据里的偏移量，用于给 DecodeError 定位
+	valueKind    Kind          // 最近一次被 Kind 方法解析出的编码类型
+	opts         DecodeOptions // 解码时的资源限制
+	elems        []int         // 与 stack 一一对应，记录每一层列表里已经读到的元素个数
+	allocated    uint64        // 解码过程中已经分配的字节数
+	input        []byte        // DecodeBytes 系列函数给出的完整输入，零拷贝解码时字节切片直接引用它
+	noCopy       bool          // 为true时 Bytes 和 Raw 方法返回的字节切片都直接引用 input
+}
+
+// DecodeOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DecodeOptions 定义了解码时的资源限制。inputLimit 只能限制输入数据的总长度，但是一段恶意构造的输入依然可以用很少的字节
+// 构造出很深的嵌套列表（导致很深的递归调用），或者用很多个空值让 decodeSliceElems 为一个元素很大的切片反复扩容，又或者在
+// 输入长度不受限制的时候声明一个长度接近 2^64 的字符串。各字段取值为0表示不做限制，超出限制时返回对应的错误：
+//   - MaxDepth：列表最多可以嵌套多少层，超出时返回 ErrDepthLimit；
+//   - MaxListLength：单个列表里最多可以有多少个元素，超出时返回 ErrListLengthLimit；
+//   - MaxStringSize：单个字符串的最大长度，在读取字符串的编码前缀时就会检查，超出时返回 ErrStringSizeLimit；
+//   - MaxAlloc：解码过程中为字符串、RawValue、大整数、切片、指针和map分配的内存总量上限，超出时返回 ErrAllocLimit。
+type DecodeOptions struct {
+	MaxDepth      int
+	MaxListLength int
+	MaxStringSize uint64
+	MaxAlloc      uint64
 }
 
 var streamPool = sync.Pool{New: func() interface{} { return new(Stream) }}
@@ -72,6 +96,16 @@ func NewStream(r io.Reader, inputLimit uint64) *Stream {
 	return s
 }
 
+// NewStreamWithOptions ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewStreamWithOptions 方法与 NewStream 方法一样创建一个 *Stream，不同的是解码时会受到opts给出的资源限制。调用
+// Reset 方法会连同这些限制一起清除，这样从 streamPool 里取出的 Stream 不会沿用上一次使用时的限制。
+func NewStreamWithOptions(r io.Reader, inputLimit uint64, opts DecodeOptions) *Stream {
+	s := NewStream(r, inputLimit)
+	s.opts = opts
+	return s
+}
+
 // NewListStream ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
 //
 // NewListStream 与 NewStream 方法相比，该方法有两处不同，一是 *Stream.kind 被设置为 List，二是 *Stream.size
@@ -107,8 +141,14 @@ func (s *Stream) Decode(val interface{}) error {
 		return err
 	}
 	err = d(s, rVal.Elem())
-	if decErr, ok := err.(*decodeError); ok && len(decErr.ctx) > 0 {
-		decErr.ctx = append(decErr.ctx, fmt.Sprintf("(%v)", rTyp.Elem()))
+	if decErr, ok := err.(*DecodeError); ok {
+		decErr.locate(s)
+		if len(decErr.ctx) > 0 {
+			if decErr.root != nil {
+				decErr.ctx = append(decErr.ctx, fmt.Sprintf("(%v)", decErr.root))
+			}
+			decErr.root = rTyp.Elem()
+		}
 	}
 	return err
 }
@@ -118,7 +158,7 @@ func (s *Stream) Decode(val interface{}) error {
 // Reset 方法接受两个入参：io.Reader 和一个64位无符号整数 inputLimit，这两个参数用来重置 *Stream，
 // *Stream 的读取源 *Stream.r 会被 io.Reader 替换，然后如果 inputLimit 大于0，则 *Stream.limited
 // 会被置为 true，而 *Stream.remaining 会被置为 inputLimit，否则 *Stream.remaining 会被设置为 io.Reader
-// 的长度
+// 的长度。NewStreamWithOptions 设置的资源限制也会被清除，需要限制时由调用者在 Reset 之后重新设置。
 func (s *Stream) Reset(r io.Reader, inputLimit uint64) {
 	if inputLimit > 0 {
 		s.remaining = inputLimit
@@ -146,11 +186,27 @@ func (s *Stream) Reset(r io.Reader, inputLimit uint64) {
 	}
 	s.r = byteReader
 	s.stack = s.stack[:0]
+	s.elems = s.elems[:0]
+	s.allocated = 0
+	s.opts = DecodeOptions{}
+	s.input = nil
+	s.noCopy = false
 	s.size = 0
 	s.kind = -1
 	s.kindErr = nil
 	s.byteVal = 0
 	s.auxiliaryBuf = [32]byte{}
+	s.pos = 0
+	s.valuePos = 0
+	s.valueKind = 0
+}
+
+// InputOffset ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// InputOffset 方法返回 Stream 已经从底层输入读取的字节数，也就是下一个将被读取的字节在输入数据里的绝对偏移量，对于没有被
+// 包装成 DecodeError 的错误（例如直接调用 Stream 的 Uint64、Bytes 等方法时返回的错误），可以借助该方法确定出错的大致位置。
+func (s *Stream) InputOffset() uint64 {
+	return s.pos
 }
 
 // ListStart ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
@@ -167,10 +223,14 @@ func (s *Stream) ListStart() (size uint64, err error) {
 	if kind != List {
 		return 0, ErrExpectedList
 	}
+	if s.opts.MaxDepth > 0 && len(s.stack) >= s.opts.MaxDepth {
+		return 0, ErrDepthLimit
+	}
 	if inList, listLimit := s.listLimit(); inList {
 		s.stack[len(s.stack)-1] = listLimit - size
 	}
 	s.stack = append(s.stack, size)
+	s.elems = append(s.elems, 0)
 	s.kind = -1
 	s.size = 0
 	return size, nil
@@ -186,11 +246,90 @@ func (s *Stream) ListEnd() error {
 		return errNotAtEOL
 	}
 	s.stack = s.stack[:len(s.stack)-1]
+	s.elems = s.elems[:len(s.elems)-1]
 	s.kind = -1
 	s.size = 0
 	return nil
 }
 
+// MoreDataInList ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MoreDataInList 方法判断当前所在的列表里是否还有没被读取的数据，如果当前不在任何列表里，则返回false。手写 DecodeRLP
+// 方法时，可以借助该方法处理长度可变的列表，例如：
+//
+//	for s.MoreDataInList() {
+//		...
+//	}
+func (s *Stream) MoreDataInList() bool {
+	_, listLimit := s.listLimit()
+	return listLimit > 0
+}
+
+// Iterate ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Iterate 方法用来逐个处理一个列表里的元素，而不需要像 Decode 那样把整个列表一次性解码到内存里。该方法先调用 ListStart
+// 进入列表，然后对列表里的每个元素调用一次fn，i是元素在列表里的索引值，elem就是 Stream 自己，fn可以调用 elem 上的任意方
+// 法（例如 Decode、Bytes、Raw，甚至是嵌套的 Iterate）来消费这个元素，所有元素都被处理完之后，再调用 ListEnd 退出列表。
+//
+// 如果fn没有消费当前元素，该元素会被自动跳过；fn返回的错误会终止迭代并原样返回。fn不能改变列表的嵌套深度，即在fn里调用
+// ListStart 之后必须调用与之对应的 ListEnd。迭代过程中依然受到 NewStream 设置的 inputLimit 和列表大小的限制。例如：
+//
+//	err := s.Iterate(func(i int, elem *Stream) error {
+//		var tx Transaction
+//		if err := elem.Decode(&tx); err != nil {
+//			return err
+//		}
+//		return process(i, &tx)
+//	})
+func (s *Stream) Iterate(fn func(i int, elem *Stream) error) error {
+	if _, err := s.ListStart(); err != nil {
+		return err
+	}
+	depth := len(s.stack)
+	for i := 0; ; i++ {
+		_, size, err := s.Kind()
+		if err == EOL {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// 此时当前元素的编码头已经被读取了，如果fn消费了该元素（或者进一步读取了后面的数据），列表剩余的大小一定会发生变化，
+		// 否则就说明fn没有碰这个元素，Byte 和空值这种大小为0的元素则通过 s.kind 是否被重置来判断
+		before := s.stack[depth-1]
+		if err = fn(i, s); err != nil {
+			return err
+		}
+		if len(s.stack) != depth {
+			return errIterateDepth
+		}
+		if s.kind >= 0 && s.stack[depth-1] == before {
+			if err = s.skip(size); err != nil {
+				return err
+			}
+		}
+	}
+	return s.ListEnd()
+}
+
+// skip ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// skip 方法在 Kind 方法被调用之后，跳过当前元素size个字节的内容，读取的数据会被丢弃，不会为此分配额外的内存空间。
+func (s *Stream) skip(size uint64) error {
+	s.kind = -1
+	for size > 0 {
+		n := uint64(len(s.auxiliaryBuf))
+		if size < n {
+			n = size
+		}
+		if err := s.readFull(s.auxiliaryBuf[:n]); err != nil {
+			return err
+		}
+		size -= n
+	}
+	return nil
+}
+
 // Kind ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
 //
 // Kind 方法返回下一个编码数据的类型和其EC部分的大小，类型就三类：Byte、String、List。
@@ -207,12 +346,23 @@ func (s *Stream) Kind() (kind Kind, size uint64, err error) {
 		return 0, 0, EOL
 	}
 	// 在这里会从"c80102030405060708"中读取一个字节的内容
+	s.valuePos = s.pos
 	s.kind, s.size, s.kindErr = s.readKind()
+	s.valueKind = s.kind
 	if s.kindErr == nil {
 		if inList && s.size > listLimit {
 			s.kindErr = ErrElemTooLarge
 		} else if s.limited && s.size > s.remaining {
 			s.kindErr = ErrValueTooLarge
+		} else if s.kind == String && s.opts.MaxStringSize > 0 && s.size > s.opts.MaxStringSize {
+			s.kindErr = ErrStringSizeLimit
+		}
+	}
+	// 每个元素的编码前缀只会被读取一次，所以在这里统计列表里的元素个数
+	if inList && s.kindErr == nil {
+		s.elems[len(s.elems)-1]++
+		if s.opts.MaxListLength > 0 && s.elems[len(s.elems)-1] > s.opts.MaxListLength {
+			s.kindErr = ErrListLengthLimit
 		}
 	}
 	return s.kind, s.size, s.kindErr
@@ -362,6 +512,7 @@ func (s *Stream) willRead(n uint64) error {
 		}
 		s.remaining -= n
 	}
+	s.pos += n
 	return nil
 }
 
@@ -376,6 +527,47 @@ func (s *Stream) listLimit() (inList bool, limit uint64) {
 	return true, s.stack[len(s.stack)-1]
 }
 
+// readAlias ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// readAlias 方法与 readFull 方法一样读取接下来的n个字节，不同的是它不复制数据，而是直接返回 input 里对应的部分，调用者需要
+// 保证 input 不为nil。返回的切片的容量被限制为n，这样对它调用append不会覆盖输入里后面的数据。
+func (s *Stream) readAlias(n uint64) ([]byte, error) {
+	start := s.pos
+	if err := s.willRead(n); err != nil {
+		return nil, err
+	}
+	if _, err := s.r.(io.Seeker).Seek(int64(n), io.SeekCurrent); err != nil {
+		return nil, err
+	}
+	return s.input[start : start+n : start+n], nil
+}
+
+// alloc ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// alloc 方法在解码过程中分配n个字节的内存之前被调用，如果分配之后的总量会超过 DecodeOptions.MaxAlloc，则返回
+// ErrAllocLimit，调用者不应再进行分配。
+func (s *Stream) alloc(n uint64) error {
+	if s.opts.MaxAlloc == 0 {
+		return nil
+	}
+	if n > s.opts.MaxAlloc-s.allocated {
+		return ErrAllocLimit
+	}
+	s.allocated += n
+	return nil
+}
+
+// BigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// BigInt 方法从底层stream解码出一个非负的大整数，输入必须是规范的编码，不能带有前导零。
+func (s *Stream) BigInt() (*big.Int, error) {
+	i := new(big.Int)
+	if err := s.decodeBigInt(i); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
 // decodeBigInt ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
 //
 // decodeBigInt 方法接受一个大整数的指针 *big.Int，底层stream接下来存储的数据是某个大整数rlp编码的内容，
@@ -405,6 +597,9 @@ func (s *Stream) decodeBigInt(x *big.Int) error {
 			return ErrCanonSize
 		}
 	default:
+		if err = s.alloc(size); err != nil {
+			return err
+		}
 		buffer = make([]byte, size)
 		if err = s.readFull(buffer); err != nil {
 			return err
@@ -417,10 +612,70 @@ func (s *Stream) decodeBigInt(x *big.Int) error {
 	return nil
 }
 
+// Uint256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Uint256 方法读取接下来的一个256位无符号整数，输入必须是不超过32个字节的字符串，并且是规范的编码，返回值是一个数组，
+// 整个过程不会分配内存。
+func (s *Stream) Uint256() (U256, error) {
+	var z U256
+	err := s.ReadUint256(&z)
+	return z, err
+}
+
+// ReadUint256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// ReadUint256 方法将接下来的一个256位无符号整数解码到z里，它的逻辑与 decodeBigInt 类似，区别是输入超过32个字节时会返回
+// errUintOverflow，并且借助 auxiliaryBuf 完成读取，不需要分配内存。
+func (s *Stream) ReadUint256(z *U256) error {
+	var buffer []byte
+	kind, size, err := s.Kind()
+	switch {
+	case err != nil:
+		return err
+	case kind == List:
+		return ErrExpectedString
+	case kind == Byte:
+		buffer = s.auxiliaryBuf[:1]
+		buffer[0] = s.byteVal
+		s.kind = -1
+	case size == 0:
+		s.kind = -1
+	case size > uint64(len(s.auxiliaryBuf)):
+		return errUintOverflow
+	default:
+		buffer = s.auxiliaryBuf[:size]
+		if err = s.readFull(buffer); err != nil {
+			return err
+		}
+		if size == 1 && buffer[0] < 0x80 {
+			return ErrCanonSize
+		}
+	}
+	if len(buffer) > 0 && buffer[0] == 0 {
+		return ErrCanonInt
+	}
+	z.SetBytes(buffer)
+	return nil
+}
+
 // Bytes ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
-// Bytes 方法返回底层stream中存储的接下来的字符串解码结果，不能是列表数据。
+// Bytes 方法返回底层stream中存储的接下来的字符串解码结果，不能是列表数据。在 DecodeBytesNoCopy 里调用时，返回的切片直接
+// 引用输入数据。
 func (s *Stream) Bytes() ([]byte, error) {
+	return s.bytes(s.noCopy)
+}
+
+// BytesNoCopy ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// BytesNoCopy 方法与 Bytes 方法一样返回接下来的字符串，如果 Stream 是由 DecodeBytes 系列函数创建的，返回的切片直接引用输入
+// 数据而不会复制，否则退化成 Bytes 方法。调用者不能修改返回的切片，否则输入数据也会被修改。
+func (s *Stream) BytesNoCopy() ([]byte, error) {
+	return s.bytes(s.input != nil)
+}
+
+// bytes 方法是 Bytes 和 BytesNoCopy 的实现，alias为true时返回的切片直接引用 input。
+func (s *Stream) bytes(alias bool) ([]byte, error) {
 	kind, size, err := s.Kind()
 	if err != nil {
 		return nil, err
@@ -428,11 +683,24 @@ func (s *Stream) Bytes() ([]byte, error) {
 	switch kind {
 	case Byte:
 		s.kind = -1
+		if alias {
+			return s.input[s.valuePos : s.valuePos+1 : s.valuePos+1], nil
+		}
 		return []byte{s.byteVal}, nil
 	case String:
-		bz := make([]byte, size)
-		if err = s.readFull(bz); err != nil {
-			return nil, err
+		var bz []byte
+		if alias {
+			if bz, err = s.readAlias(size); err != nil {
+				return nil, err
+			}
+		} else {
+			if err = s.alloc(size); err != nil {
+				return nil, err
+			}
+			bz = make([]byte, size)
+			if err = s.readFull(bz); err != nil {
+				return nil, err
+			}
 		}
 		if size == 1 && bz[0] < 0x80 {
 			return nil, ErrCanonSize
@@ -477,20 +745,46 @@ func (s *Stream) ReadBytes(bz []byte) error {
 
 // Raw ♏ |作者：吴翔宇| 🍁 |日期：2022/11/11|
 //
-// Raw 方法返回stream里存储的 RawValue 数据。
+// Raw 方法返回stream里存储的 RawValue 数据。在 DecodeBytesNoCopy 里调用时，返回的切片直接引用输入数据。
 func (s *Stream) Raw() ([]byte, error) {
+	return s.raw(s.noCopy)
+}
+
+// RawNoCopy ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// RawNoCopy 方法之于 Raw 方法，就像 BytesNoCopy 方法之于 Bytes 方法。
+func (s *Stream) RawNoCopy() ([]byte, error) {
+	return s.raw(s.input != nil)
+}
+
+// raw 方法是 Raw 和 RawNoCopy 的实现，alias为true时返回的切片直接引用 input。
+func (s *Stream) raw(alias bool) ([]byte, error) {
 	// 获取下一段数据的类型，size反映出stream里接下来存储的RawValue的大小
 	kind, size, err := s.Kind()
 	if err != nil {
 		return nil, err
 	}
+	start := s.valuePos
 	if kind == Byte {
 		// 将kind设置为-1的目的是为了避免将来调用Kind()方法返回的kind还是之前编码数据片段的kind
 		s.kind = -1
+		if alias {
+			return s.input[start : start+1 : start+1], nil
+		}
 		return []byte{s.byteVal}, nil
 	}
+	if alias {
+		// 编码前缀已经被读取过了，它就在 valuePos 和 pos 之间
+		if _, err = s.readAlias(size); err != nil {
+			return nil, err
+		}
+		return s.input[start:s.pos:s.pos], nil
+	}
 	// 计算编码前缀的的大小
 	prefixSize := headSize(size)
+	if err = s.alloc(uint64(prefixSize) + size); err != nil {
+		return nil, err
+	}
 	buf := make([]byte, uint64(prefixSize)+size)
 	if err = s.readFull(buf[prefixSize:]); err != nil {
 		return nil, err
@@ -510,10 +804,82 @@ func (s *Stream) Uint64() (uint64, error) {
 	return s.uint(64)
 }
 
-// bool ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
+// Uint32 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Uint32 方法从底层stream解码出一个32位无符号整数，输入超过4个字节时会返回错误。
+func (s *Stream) Uint32() (uint32, error) {
+	i, err := s.uint(32)
+	return uint32(i), err
+}
+
+// Uint16 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Uint16 方法从底层stream解码出一个16位无符号整数，输入超过2个字节时会返回错误。
+func (s *Stream) Uint16() (uint16, error) {
+	i, err := s.uint(16)
+	return uint16(i), err
+}
+
+// Uint8 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Uint8 方法从底层stream解码出一个8位无符号整数，输入超过1个字节时会返回错误。
+func (s *Stream) Uint8() (uint8, error) {
+	i, err := s.uint(8)
+	return uint8(i), err
+}
+
+// Int64 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Int64 方法从底层stream解码出一个64位有符号整数，输入必须是经过zig-zag变换之后的规范编码，带有前导零的输入会返回
+// ErrCanonInt 错误。
+func (s *Stream) Int64() (int64, error) {
+	return s.int(64)
+}
+
+// int ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// int 方法接受一个整数maxBits，从底层stream里读取一个经过zig-zag变换的整数，然后对其做逆变换。对于maxBits位的有符号
+// 整数来说，zig-zag变换后的结果恰好占用maxBits个比特，所以可以直接复用 uint 方法对长度和规范性做检查。
+func (s *Stream) int(maxBits int) (int64, error) {
+	num, err := s.uint(maxBits)
+	if err != nil {
+		return 0, err
+	}
+	return unzigzag(num), nil
+}
+
+// SignedBigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SignedBigInt 方法从底层stream解码出一个可以是负数的大整数，输入是经过zig-zag变换之后的编码结果。
+func (s *Stream) SignedBigInt() (*big.Int, error) {
+	x := new(big.Int)
+	if err := s.decodeSignedBigInt(x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// decodeSignedBigInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeSignedBigInt 方法先调用 decodeBigInt 方法解码出zig-zag变换后的无符号大整数u，然后做逆变换：u是偶数的话，原值
+// 为u/2，u是奇数的话，原值为-(u+1)/2。
+func (s *Stream) decodeSignedBigInt(x *big.Int) error {
+	if err := s.decodeBigInt(x); err != nil {
+		return err
+	}
+	odd := x.Bit(0) == 1
+	x.Rsh(x, 1)
+	if odd {
+		x.Add(x, big.NewInt(1))
+		x.Neg(x)
+	}
+	return nil
+}
+
+// Bool ♏ |作者：吴翔宇| 🍁 |日期：2022/11/10|
 //
-// bool 方法解码底层stream接下来的数据成bool类型。
-func (s *Stream) bool() (bool, error) {
+// Bool 方法解码底层stream接下来的数据成bool类型，只有0x80和0x01是合法的输入，分别对应false和true。
+func (s *Stream) Bool() (bool, error) {
 	num, err := s.uint(8)
 	if err != nil {
 		return false, err
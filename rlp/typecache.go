@@ -3,6 +3,7 @@ package rlp
 import (
 	"fmt"
 	"github.com/232425wxy/understanding-ethereum/rlp/internal/rlpstruct"
+	"math/big"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -22,6 +23,11 @@ type writer func(reflect.Value, *encBuffer) error
 // decoder 是一个函数类型，解码时会遇到各种各样的数据类型，为此需要针对不同的数据类型设计不同的解码规则。
 type decoder func(*Stream, reflect.Value) error
 
+// sizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// sizer 是一个函数类型，它计算某个值的编码结果有多少个字节，但是并不真正地进行编码，详见 EncodedSize。
+type sizer func(reflect.Value) (int, error)
+
 /*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
 
 // typeInfo ♏ |作者：吴翔宇| 🍁 |日期：2022/10/30|
@@ -33,6 +39,8 @@ type typeInfo struct {
 	decoderErr error // 在为某个特定的数据类型生成解码器时遇到的错误
 	writer     writer
 	writerErr  error // 在为某个特定的数据类型生成编码器时遇到的错误
+	sizer      sizer
+	sizerErr   error // 在为某个特定的数据类型生成计算编码长度的函数时遇到的错误
 }
 
 // makeDecoderAndWriter ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
@@ -43,6 +51,7 @@ type typeInfo struct {
 func (ti *typeInfo) makeDecoderAndWriter(typ reflect.Type, tag rlpstruct.Tag) {
 	ti.decoder, ti.decoderErr = makeDecoder(typ, tag)
 	ti.writer, ti.writerErr = makeWriter(typ, tag)
+	ti.sizer, ti.sizerErr = makeSizer(typ, tag)
 }
 
 // typeKey ♏ |作者：吴翔宇| 🍁 |日期：2022/10/30|
@@ -78,6 +87,14 @@ func cachedWriter(typ reflect.Type) (writer, error) {
 	return info.writer, info.writerErr
 }
 
+// cachedSizer ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// cachedSizer 方法与 cachedWriter 方法类似，返回针对typ的计算编码长度的函数。
+func cachedSizer(typ reflect.Type) (sizer, error) {
+	info := theTC.info(typ)
+	return info.sizer, info.sizerErr
+}
+
 // cachedDecoder ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
 //
 // cachedDecoder 方法接受一个参数，那就是 reflect.Type 类型的typ，然后该方法从缓冲区获取针对该typ的 typeInfo 实
@@ -188,7 +205,25 @@ type field struct {
 // processStructFields 方法接受某个结构体的 reflect.Type，然后基于此来处理给定的结构体里所有可导出字段，包括每个字段
 // 的tag，最终目的是为了获取所有参与编码的结构体字段信息，在这个过程中，顺便还会为每个字段生成编解码器，官方源码写法是："structFields"。
 func processStructFields(typ reflect.Type) (fields []field, err error) {
-	// 将结构体的字段转换为 rlpstruct.Field
+	structFields, structTags, err := structFieldsAndTags(typ)
+	if err != nil {
+		return nil, err
+	}
+	// 为结构体里每个字段生成对应的编解码器
+	for i, sf := range structFields {
+		t := typ.Field(sf.Index).Type
+		tag := structTags[i]
+		info := theTC.infoWhileGenerating(t, tag)
+		fields = append(fields, field{sf.Index, info, tag.Optional})
+	}
+	return fields, nil
+}
+
+// structFieldsAndTags ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// structFieldsAndTags 方法将结构体的字段转换为 rlpstruct.Field，然后调用 rlpstruct.ProcessFields 过滤和验证这些字段，
+// 返回所有参与编码的字段和它们的tag。
+func structFieldsAndTags(typ reflect.Type) ([]rlpstruct.Field, []rlpstruct.Tag, error) {
 	var allStructFields []rlpstruct.Field
 	for i := 0; i < typ.NumField(); i++ {
 		rf := typ.Field(i)
@@ -200,23 +235,15 @@ func processStructFields(typ reflect.Type) (fields []field, err error) {
 			Tag:      string(rf.Tag),
 		})
 	}
-	// 过滤和验证结构体的所有字段
 	structFields, structTags, err := rlpstruct.ProcessFields(allStructFields)
 	if err != nil {
 		if tagErr, ok := err.(rlpstruct.TagError); ok {
 			tagErr.StructType = typ.String()
-			return nil, tagErr
+			return nil, nil, tagErr
 		}
-		return nil, err
-	}
-	// 为结构体里每个字段生成对应的编解码器
-	for i, sf := range structFields {
-		t := typ.Field(sf.Index).Type
-		tag := structTags[i]
-		info := theTC.infoWhileGenerating(t, tag)
-		fields = append(fields, field{sf.Index, info, tag.Optional})
+		return nil, nil, err
 	}
-	return fields, nil
+	return structFields, structTags, nil
 }
 
 // reflectTypeToRLPType ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
@@ -289,6 +316,33 @@ func isUint(k reflect.Kind) bool {
 	return k >= reflect.Uint && k <= reflect.Uintptr
 }
 
+// isInt ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isInt 方法判断给定的 reflect.Kind 是否是有符号整数类型。
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+// isOrderableMapKey ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// isOrderableMapKey 方法判断给定的类型能否作为被编码的map的key：无符号整数、字符串、字节数组和 *big.Int 的编码结果都是
+// 字符串，可以按照字节序进行排序。实现了 Encoder 或 Decoder 接口的类型会被排除在外，因为我们无法保证它们的编码结果不是
+// 列表。
+func isOrderableMapKey(typ reflect.Type) bool {
+	if reflect.PtrTo(typ).Implements(encoderInterface) || reflect.PtrTo(typ).Implements(decoderInterface) {
+		return false
+	}
+	kind := typ.Kind()
+	switch {
+	case isUint(kind), kind == reflect.String:
+		return true
+	case kind == reflect.Array:
+		return isByte(typ.Elem())
+	default:
+		return typ == reflect.PtrTo(reflect.TypeOf(big.Int{}))
+	}
+}
+
 // isByte ♏ |作者：吴翔宇| 🍁 |日期：2022/10/31|
 //
 // 该方法接受一个参数：typ reflect.Type，该方法判断给定的typ是否是 reflect.Uint8 类型，且必须没有实现 Encoder 接口，
@@ -0,0 +1,133 @@
+package rlp
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+	"reflect"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// U256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// U256 是一个定长的256位无符号整数，它由4个64位的无符号整数组成，U256[0]是最低的64位，U256[3]是最高的64位。以太坊里
+// 的余额、gas价格等数值都不会超过256位，用 U256 代替 *big.Int 可以在编解码时避免分配内存：makeWriter 和 makeDecoder
+// 能够直接识别 U256 和 *U256 类型，编码规则与非负的 big.Int 完全一样，例如 NewU256(1024) 的编码结果是[0x82 0x04 0x00]；
+// 解码时输入的字符串不能超过32个字节，并且必须是规范的编码（不能带有前导零）。
+type U256 [4]uint64
+
+// u256Type = reflect.TypeOf(U256{})
+var u256Type = reflect.TypeOf(U256{})
+
+// NewU256 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewU256 方法返回一个值为x的 *U256。
+func NewU256(x uint64) *U256 {
+	return new(U256).SetUint64(x)
+}
+
+// SetUint64 方法将z设置为x，并返回z。
+func (z *U256) SetUint64(x uint64) *U256 {
+	*z = U256{x}
+	return z
+}
+
+// SetBytes ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SetBytes 方法将大端序的字节切片bz解释为一个无符号整数并赋值给z，如果bz超过32个字节，则只保留最低的32个字节。
+func (z *U256) SetBytes(bz []byte) *U256 {
+	if len(bz) > 32 {
+		bz = bz[len(bz)-32:]
+	}
+	var buf [32]byte
+	copy(buf[32-len(bz):], bz)
+	z[3] = binary.BigEndian.Uint64(buf[0:8])
+	z[2] = binary.BigEndian.Uint64(buf[8:16])
+	z[1] = binary.BigEndian.Uint64(buf[16:24])
+	z[0] = binary.BigEndian.Uint64(buf[24:32])
+	return z
+}
+
+// SetFromBig ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// SetFromBig 方法将z设置为b，如果b是负数或者超过了256位，则返回true，此时z的值是b的绝对值的最低256位。
+func (z *U256) SetFromBig(b *big.Int) (overflow bool) {
+	z.SetBytes(b.Bytes())
+	return b.Sign() < 0 || b.BitLen() > 256
+}
+
+// IsZero 方法判断x是否等于0。
+func (x *U256) IsZero() bool {
+	return x[0]|x[1]|x[2]|x[3] == 0
+}
+
+// IsUint64 方法判断x能否用一个64位无符号整数表示。
+func (x *U256) IsUint64() bool {
+	return x[1]|x[2]|x[3] == 0
+}
+
+// Uint64 方法返回x的最低64位。
+func (x *U256) Uint64() uint64 {
+	return x[0]
+}
+
+// BitLen ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// BitLen 方法返回表示x所需的最少比特位数，0的比特位数为0。
+func (x *U256) BitLen() int {
+	for i := 3; i > 0; i-- {
+		if x[i] != 0 {
+			return i*64 + bits.Len64(x[i])
+		}
+	}
+	return bits.Len64(x[0])
+}
+
+// ByteLen 方法返回表示x所需的最少字节数，0的字节数为0。
+func (x *U256) ByteLen() int {
+	return (x.BitLen() + 7) / 8
+}
+
+// Bytes32 ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Bytes32 方法以32字节大端序的形式返回x，返回值是一个数组，因此不会分配内存。
+func (x *U256) Bytes32() [32]byte {
+	var buf [32]byte
+	binary.BigEndian.PutUint64(buf[0:8], x[3])
+	binary.BigEndian.PutUint64(buf[8:16], x[2])
+	binary.BigEndian.PutUint64(buf[16:24], x[1])
+	binary.BigEndian.PutUint64(buf[24:32], x[0])
+	return buf
+}
+
+// Bytes 方法以大端序的形式返回x，结果不带有前导零，0会被表示为空切片。
+func (x *U256) Bytes() []byte {
+	buf := x.Bytes32()
+	return append([]byte{}, buf[32-x.ByteLen():]...)
+}
+
+// ToBig 方法将x转换为 *big.Int。
+func (x *U256) ToBig() *big.Int {
+	return new(big.Int).SetBytes(x.Bytes())
+}
+
+// Cmp ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Cmp 方法比较x和y的大小：x<y时返回-1，x==y时返回0，x>y时返回1。
+func (x *U256) Cmp(y *U256) int {
+	for i := 3; i >= 0; i-- {
+		switch {
+		case x[i] < y[i]:
+			return -1
+		case x[i] > y[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// String 方法返回x的十进制表示。
+func (x *U256) String() string {
+	return x.ToBig().String()
+}
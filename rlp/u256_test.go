@@ -0,0 +1,167 @@
+package rlp
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var u256TestValues = []string{
+	"0",
+	"1",
+	"127",
+	"128",
+	"18446744073709551615",
+	"18446744073709551616",
+	"340282366920938463463374607431768211456",
+	"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+}
+
+func TestU256(t *testing.T) {
+	for _, s := range u256TestValues {
+		b, _ := new(big.Int).SetString(s, 10)
+		var z U256
+		assert.False(t, z.SetFromBig(b), s)
+		assert.Equal(t, s, z.String())
+		assert.Equal(t, b.BitLen(), z.BitLen(), s)
+		assert.Equal(t, b.Bytes(), z.Bytes(), s)
+		assert.Equal(t, b.IsUint64(), z.IsUint64(), s)
+		assert.Equal(t, 0, z.Cmp(new(U256).SetBytes(b.Bytes())), s)
+
+		// 编码结果必须与值相同的 big.Int 完全一样
+		want, err := EncodeToBytes(b)
+		assert.Nil(t, err)
+		got, err := EncodeToBytes(&z)
+		assert.Nil(t, err)
+		assert.Equal(t, want, got, s)
+	}
+
+	var z U256
+	assert.True(t, z.SetFromBig(big.NewInt(-1)))
+	assert.True(t, z.SetFromBig(new(big.Int).Lsh(big.NewInt(1), 256)))
+	assert.True(t, z.IsZero())
+	assert.Equal(t, -1, NewU256(1).Cmp(&U256{0, 1}))
+	assert.Equal(t, 1, (&U256{0, 0, 0, 1}).Cmp(&U256{^uint64(0), ^uint64(0), ^uint64(0)}))
+}
+
+func TestEncodeBufferUint256(t *testing.T) {
+	for _, s := range u256TestValues {
+		b, _ := new(big.Int).SetString(s, 10)
+		var z U256
+		z.SetFromBig(b)
+		buf := new(bytes.Buffer)
+		w := NewEncodeBuffer(buf)
+		w.WriteUint256(&z)
+		w.WriteBigInt(b)
+		assert.Nil(t, w.Flush())
+		half := buf.Len() / 2
+		assert.Equal(t, buf.Bytes()[:half], buf.Bytes()[half:], s)
+
+		stream := NewStream(bytes.NewReader(buf.Bytes()), 0)
+		got, err := stream.Uint256()
+		assert.Nil(t, err)
+		assert.Equal(t, z, got, s)
+	}
+}
+
+func TestStreamUint256(t *testing.T) {
+	// 列表里的元素超出列表的大小时，同样会触发 ErrElemTooLarge
+	s := NewStream(bytes.NewReader(unhex("C3 820400 83010000")), 0)
+	_, err := s.ListStart()
+	assert.Nil(t, err)
+	z, err := s.Uint256()
+	assert.Nil(t, err)
+	assert.Equal(t, U256{1024}, z)
+	_, err = s.Uint256()
+	assert.Equal(t, EOL, err)
+	assert.Nil(t, s.ListEnd())
+
+	s = NewStream(bytes.NewReader(unhex("C2 83010000")), 0)
+	_, err = s.ListStart()
+	assert.Nil(t, err)
+	_, err = s.Uint256()
+	assert.Equal(t, ErrElemTooLarge, err)
+
+	s = NewStream(bytes.NewReader(unhex("A1 01"+string(bytes.Repeat([]byte("00"), 32)))), 0)
+	_, err = s.Uint256()
+	assert.Equal(t, errUintOverflow, err)
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 下面的基准测试对比 U256 与 *big.Int 在编解码同样的数值时的内存分配情况。
+
+type benchU256 struct {
+	Nonce, Price, Gas, Value U256
+}
+
+type benchBigInt struct {
+	Nonce, Price, Gas, Value *big.Int
+}
+
+func makeBenchValues() (benchU256, benchBigInt) {
+	var u benchU256
+	bi := benchBigInt{
+		Nonce: big.NewInt(42),
+		Price: new(big.Int).Lsh(big.NewInt(3), 70),
+		Gas:   big.NewInt(21000),
+		Value: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+	}
+	u.Nonce.SetFromBig(bi.Nonce)
+	u.Price.SetFromBig(bi.Price)
+	u.Gas.SetFromBig(bi.Gas)
+	u.Value.SetFromBig(bi.Value)
+	return u, bi
+}
+
+func BenchmarkEncodeU256(b *testing.B) {
+	u, _ := makeBenchValues()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Encode(io.Discard, &u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeBigInt(b *testing.B) {
+	_, bi := makeBenchValues()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Encode(io.Discard, &bi); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeU256(b *testing.B) {
+	u, _ := makeBenchValues()
+	enc, _ := EncodeToBytes(&u)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst benchU256
+		if err := DecodeBytes(enc, &dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeBigInt(b *testing.B) {
+	_, bi := makeBenchValues()
+	enc, _ := EncodeToBytes(&bi)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 每次都解码到新的结构体里，模拟解码一条新消息的场景
+		var dst benchBigInt
+		if err := DecodeBytes(enc, &dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package trie
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+)
+
+// ErrNotFound 表示键值存储里不存在给定的键。
+var ErrNotFound = errors.New("trie: key not found")
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// KeyValueReader ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// KeyValueReader 定义了从键值存储里读取数据的方法，前缀树通过它按照哈希值加载节点。
+type KeyValueReader interface {
+	// Has 判断键值存储里是否存在给定的键。
+	Has(key []byte) (bool, error)
+	// Get 返回给定的键对应的值，不存在时返回 ErrNotFound。
+	Get(key []byte) ([]byte, error)
+}
+
+// KeyValueWriter ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// KeyValueWriter 定义了向键值存储里写入数据的方法，Commit 通过它把节点以"哈希值=>rlp编码"的形式保存起来。
+type KeyValueWriter interface {
+	// Put 将给定的键值对写入键值存储。
+	Put(key []byte, value []byte) error
+	// Delete 从键值存储里删除给定的键。
+	Delete(key []byte) error
+}
+
+// KeyValueStore ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// KeyValueStore 是前缀树依赖的键值存储接口，任何实现了它的数据库（例如基于磁盘的数据库）都可以被用来存储前缀树的节点。
+type KeyValueStore interface {
+	KeyValueReader
+	KeyValueWriter
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// MemoryDB ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MemoryDB 是一个基于map实现的内存键值存储，它实现了 KeyValueStore 接口，并且可以被多个协程并发使用。写入和读取时
+// 都会拷贝数据，调用者可以放心地修改传入或者得到的字节切片。
+type MemoryDB struct {
+	mu sync.RWMutex
+	db map[string][]byte
+}
+
+// NewMemoryDB ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewMemoryDB 方法返回一个空的 MemoryDB。
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{db: make(map[string][]byte)}
+}
+
+// Has 方法判断给定的键是否存在。
+func (m *MemoryDB) Has(key []byte) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.db[string(key)]
+	return ok, nil
+}
+
+// Get 方法返回给定的键对应的值的拷贝，键不存在时返回 ErrNotFound。
+func (m *MemoryDB) Get(key []byte) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if val, ok := m.db[string(key)]; ok {
+		return common.CopyBytes(val), nil
+	}
+	return nil, ErrNotFound
+}
+
+// Put 方法写入一个键值对。
+func (m *MemoryDB) Put(key []byte, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.db[string(key)] = common.CopyBytes(value)
+	return nil
+}
+
+// Delete 方法删除给定的键，键不存在时什么也不做。
+func (m *MemoryDB) Delete(key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.db, string(key))
+	return nil
+}
+
+// Len 方法返回 MemoryDB 里存储的键值对的数量。
+func (m *MemoryDB) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.db)
+}
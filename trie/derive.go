@@ -0,0 +1,63 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+	"github.com/232425wxy/understanding-ethereum/rlp"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// DerivableList ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DerivableList 是可以用 DeriveSha 计算根哈希的列表，例如区块里的交易列表和收据列表。EncodeIndex 把第i个元素的编码
+// 写入w，对于普通的交易来说就是它的rlp编码。
+type DerivableList interface {
+	Len() int
+	EncodeIndex(i int, w *bytes.Buffer)
+}
+
+// DeriveSha ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DeriveSha 方法计算区块头里的交易根和收据根：它以每个元素的索引值的rlp编码为键，以元素的编码为值，把所有元素插入一棵
+// 空的前缀树，然后返回这棵树的根哈希。空列表的根哈希是 EmptyRoot。
+func DeriveSha(list DerivableList) []byte {
+	t := NewEmpty(NewMemoryDB())
+	var buf bytes.Buffer
+	for i := 0; i < list.Len(); i++ {
+		buf.Reset()
+		list.EncodeIndex(i, &buf)
+		// 前缀树是全新的并且只存在于内存里，不会遇到找不到节点的错误
+		t.Update(rlp.AppendUint64(nil, uint64(i)), common.CopyBytes(buf.Bytes()))
+	}
+	return t.Hash()
+}
+
+// DeriveShaOf ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// DeriveShaOf 方法接受一个切片或者数组items，它的每个元素都必须能被 rlp.EncodeToBytes 编码，该方法先逐个编码这些元素，
+// 然后按照 DeriveSha 的规则计算根哈希，如果某个元素无法被编码，则返回错误。
+func DeriveShaOf(items interface{}) ([]byte, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("trie: DeriveShaOf needs a slice or array, got %T", items)
+	}
+	list := make(encodedList, v.Len())
+	for i := range list {
+		enc, err := rlp.EncodeToBytes(v.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("trie: can't encode item %d: %w", i, err)
+		}
+		list[i] = enc
+	}
+	return DeriveSha(list), nil
+}
+
+// encodedList 是由已经编码好的元素组成的 DerivableList。
+type encodedList [][]byte
+
+func (l encodedList) Len() int                           { return len(l) }
+func (l encodedList) EncodeIndex(i int, w *bytes.Buffer) { w.Write(l[i]) }
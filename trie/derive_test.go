@@ -0,0 +1,57 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+	"github.com/232425wxy/understanding-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+type deriveItem struct {
+	Nonce uint64
+	To    []byte
+	Data  string
+}
+
+func TestDeriveSha(t *testing.T) {
+	root, err := DeriveShaOf([]deriveItem{})
+	assert.Nil(t, err)
+	assert.Equal(t, "56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421", common.Bytes2Hex(root))
+
+	// 超过128个元素时，索引值的rlp编码会从单个字节变成两个字节，键的长度也随之改变
+	items := make([]deriveItem, 300)
+	for i := range items {
+		items[i] = deriveItem{Nonce: uint64(i), To: []byte{byte(i), 0xAA}, Data: "transfer"}
+	}
+	root, err = DeriveShaOf(items)
+	assert.Nil(t, err)
+
+	// 与手动构建的前缀树比较
+	tr := NewEmpty(NewMemoryDB())
+	for i, item := range items {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		val, _ := rlp.EncodeToBytes(item)
+		assert.Nil(t, tr.Update(key, val))
+	}
+	assert.Equal(t, tr.Hash(), root)
+
+	// DerivableList 与 DeriveShaOf 的结果一致
+	list := make(encodedList, len(items))
+	for i, item := range items {
+		list[i], _ = rlp.EncodeToBytes(item)
+	}
+	assert.Equal(t, root, DeriveSha(list))
+
+	_, err = DeriveShaOf(42)
+	assert.NotNil(t, err)
+	_, err = DeriveShaOf([]interface{}{int8(-1)})
+	assert.NotNil(t, err)
+}
+
+// TestDeriveShaVector 用以太坊客户端的区块编码测试里的数据验证 DeriveSha：区块只包含一笔交易，区块头里记录的交易根是
+// 由另一套实现计算出来的，不依赖这里的前缀树和编码代码。
+func TestDeriveShaVector(t *testing.T) {
+	tx := common.Hex2Bytes("f85f800a82c35094095e7baea6a6c7c4c2dfeb977efac326af552d870a801ba09bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094fa08a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b1")
+	assert.Equal(t, "5fe50b260da6308036625b850b5d6ced6d0a9f814c0688bc91ffb7b7a3a54b67", common.Bytes2Hex(DeriveSha(encodedList{tx})))
+}
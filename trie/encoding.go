@@ -0,0 +1,105 @@
+package trie
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 前缀树里的键有三种表现形式：
+//   - KEYBYTES：原始的字节形式，也就是调用者传进来的键；
+//   - HEX：每个字节被拆成两个半字节（nibble），如果键指向的是一个值，末尾还会追加一个值为16的终止符，前缀树在内存
+//     里就是用这种形式组织节点的；
+//   - COMPACT：即以太坊黄皮书里的 hex-prefix 编码，它把HEX形式重新压缩回字节，并用第一个字节的高4位记录半字节的奇偶
+//     性以及是否带有终止符，节点在被编码成rlp数据时使用这种形式。
+//
+// 例如键 0x1234 的HEX形式是[1 2 3 4 16]，COMPACT形式是[0x20 0x12 0x34]。
+
+// hexToCompact ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// hexToCompact 方法将HEX形式的键转换为COMPACT形式。COMPACT形式第一个字节的高4位里，第1位（0x20）表示键是否带有
+// 终止符，第0位（0x10）表示半字节的个数是否为奇数，如果是奇数，第一个半字节就被放在第一个字节的低4位里。
+func hexToCompact(hex []byte) []byte {
+	terminator := byte(0)
+	if hasTerm(hex) {
+		terminator = 1
+		hex = hex[:len(hex)-1]
+	}
+	buf := make([]byte, len(hex)/2+1)
+	buf[0] = terminator << 5
+	if len(hex)&1 == 1 {
+		buf[0] |= 1 << 4
+		buf[0] |= hex[0]
+		hex = hex[1:]
+	}
+	decodeNibbles(hex, buf[1:])
+	return buf
+}
+
+// compactToHex ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// compactToHex 方法是 hexToCompact 的逆过程。
+func compactToHex(compact []byte) []byte {
+	if len(compact) == 0 {
+		return compact
+	}
+	base := keybytesToHex(compact)
+	// 没有终止符的话，去掉 keybytesToHex 追加的终止符
+	if base[0] < 2 {
+		base = base[:len(base)-1]
+	}
+	// 偶数个半字节时需要去掉标志位和填充的0，奇数个时只需要去掉标志位
+	chop := 2 - base[0]&1
+	return base[chop:]
+}
+
+// keybytesToHex ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// keybytesToHex 方法将原始的键转换为HEX形式，结果的末尾总是带有终止符。
+func keybytesToHex(str []byte) []byte {
+	l := len(str)*2 + 1
+	nibbles := make([]byte, l)
+	for i, b := range str {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	nibbles[l-1] = 16
+	return nibbles
+}
+
+// hexToKeybytes ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// hexToKeybytes 方法将HEX形式的键转换回原始的字节形式，去掉终止符之后，半字节的个数必须是偶数，否则会panic。
+func hexToKeybytes(hex []byte) []byte {
+	if hasTerm(hex) {
+		hex = hex[:len(hex)-1]
+	}
+	if len(hex)&1 != 0 {
+		panic("can't convert hex key of odd length")
+	}
+	key := make([]byte, len(hex)/2)
+	decodeNibbles(hex, key)
+	return key
+}
+
+// decodeNibbles 方法把每两个半字节合并成一个字节写入bytes里。
+func decodeNibbles(nibbles []byte, bytes []byte) {
+	for bi, ni := 0, 0; ni < len(nibbles); bi, ni = bi+1, ni+2 {
+		bytes[bi] = nibbles[ni]<<4 | nibbles[ni+1]
+	}
+}
+
+// prefixLen 方法返回a和b的公共前缀的长度。
+func prefixLen(a, b []byte) int {
+	var i, length = 0, len(a)
+	if len(b) < length {
+		length = len(b)
+	}
+	for ; i < length; i++ {
+		if a[i] != b[i] {
+			break
+		}
+	}
+	return i
+}
+
+// hasTerm 方法判断HEX形式的键是否带有终止符。
+func hasTerm(s []byte) bool {
+	return len(s) > 0 && s[len(s)-1] == 16
+}
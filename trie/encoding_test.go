@@ -0,0 +1,44 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexCompact(t *testing.T) {
+	tests := []struct{ hex, compact []byte }{
+		// 空的键，带有或者不带有终止符
+		{hex: []byte{}, compact: []byte{0x00}},
+		{hex: []byte{16}, compact: []byte{0x20}},
+		// 奇数个半字节，不带终止符
+		{hex: []byte{1, 2, 3, 4, 5}, compact: []byte{0x11, 0x23, 0x45}},
+		// 偶数个半字节，不带终止符
+		{hex: []byte{0, 1, 2, 3, 4, 5}, compact: []byte{0x00, 0x01, 0x23, 0x45}},
+		// 奇数个半字节，带终止符
+		{hex: []byte{15, 1, 12, 11, 8, 16}, compact: []byte{0x3f, 0x1c, 0xb8}},
+		// 偶数个半字节，带终止符
+		{hex: []byte{0, 15, 1, 12, 11, 8, 16}, compact: []byte{0x20, 0x0f, 0x1c, 0xb8}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.compact, hexToCompact(test.hex), "hexToCompact(%x)", test.hex)
+		assert.Equal(t, test.hex, compactToHex(test.compact), "compactToHex(%x)", test.compact)
+	}
+}
+
+func TestHexKeybytes(t *testing.T) {
+	tests := []struct{ key, hexIn, hexOut []byte }{
+		{key: []byte{}, hexIn: []byte{16}, hexOut: []byte{16}},
+		{key: []byte{}, hexIn: []byte{}, hexOut: []byte{16}},
+		{key: []byte{0x12, 0x34, 0x56}, hexIn: []byte{1, 2, 3, 4, 5, 6, 16}, hexOut: []byte{1, 2, 3, 4, 5, 6, 16}},
+		{key: []byte{0x12, 0x34, 0x5}, hexIn: []byte{1, 2, 3, 4, 0, 5, 16}, hexOut: []byte{1, 2, 3, 4, 0, 5, 16}},
+		{key: []byte{0x12, 0x34, 0x56}, hexIn: []byte{1, 2, 3, 4, 5, 6}, hexOut: []byte{1, 2, 3, 4, 5, 6, 16}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.hexOut, keybytesToHex(test.key), "keybytesToHex(%x)", test.key)
+		assert.Equal(t, test.key, hexToKeybytes(test.hexIn), "hexToKeybytes(%x)", test.hexIn)
+	}
+	assert.Panics(t, func() { hexToKeybytes([]byte{1, 2, 3}) })
+	assert.Equal(t, 3, prefixLen([]byte{1, 2, 3, 4}, []byte{1, 2, 3}))
+	assert.Equal(t, 0, prefixLen([]byte{1}, []byte{2}))
+}
@@ -0,0 +1,111 @@
+package trie
+
+import (
+	"github.com/232425wxy/understanding-ethereum/crypto/sha3"
+)
+
+// hashLen 节点哈希值的长度
+const hashLen = 32
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// hashNodeTree ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// hashNodeTree 方法计算以n为根的子树的哈希值，它返回两个节点：
+//   - hashed：n被父节点引用时的形式，如果n的编码不小于32字节（或者force为true），它就是n的哈希值 hashNode，否则是
+//     n折叠之后的形式，会被直接内嵌在父节点的编码里；
+//   - cached：n的一个副本，它和它的所有子节点都缓存了各自的哈希值，前缀树用它替换原来的节点，下次计算哈希时就不需要
+//     重新计算没有被修改过的子树了。
+//
+// 根节点总是以 force=true 调用，因为即使根节点的编码很短，我们也需要它的哈希值作为整棵树的根哈希。
+func hashNodeTree(n node, force bool) (hashed node, cached node) {
+	if hash, _ := n.cache(); hash != nil {
+		return hash, n
+	}
+	switch n := n.(type) {
+	case *shortNode:
+		collapsed, cached := n.copy(), n.copy()
+		collapsed.Key = hexToCompact(n.Key)
+		if _, ok := n.Val.(valueNode); !ok {
+			collapsed.Val, cached.Val = hashNodeTree(n.Val, false)
+		}
+		hashed := hashCollapsed(collapsed, force)
+		if hn, ok := hashed.(hashNode); ok {
+			cached.flags.hash = hn
+		}
+		return hashed, cached
+	case *fullNode:
+		collapsed, cached := n.copy(), n.copy()
+		for i := 0; i < 16; i++ {
+			if n.Children[i] != nil {
+				collapsed.Children[i], cached.Children[i] = hashNodeTree(n.Children[i], false)
+			}
+		}
+		hashed := hashCollapsed(collapsed, force)
+		if hn, ok := hashed.(hashNode); ok {
+			cached.flags.hash = hn
+		}
+		return hashed, cached
+	default:
+		// hashNode 和 valueNode 不需要再计算哈希
+		return n, n
+	}
+}
+
+// hashCollapsed 方法对折叠之后的节点进行编码，编码长度小于32字节并且force为false时返回节点本身，否则返回编码的哈希值。
+func hashCollapsed(collapsed node, force bool) node {
+	enc := encodeNode(collapsed)
+	if len(enc) < hashLen && !force {
+		return collapsed
+	}
+	return hashNode(sha3.Keccak256(enc))
+}
+
+// commitNodeTree ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// commitNodeTree 方法把以n为根的子树里所有被修改过的节点写入db，调用它之前必须先用 hashNodeTree 计算过哈希值。
+// 拥有哈希值的节点以"哈希值=>rlp编码"的形式被写入，内嵌的节点则随着父节点的编码一起保存。返回值是n被父节点引用时
+// 的折叠形式，与 hashNodeTree 返回的 hashed 相同。
+func commitNodeTree(n node, db KeyValueWriter) (node, error) {
+	hash, dirty := n.cache()
+	if hash != nil && !dirty {
+		return hash, nil
+	}
+	var collapsed node
+	switch n := n.(type) {
+	case *shortNode:
+		c := n.copy()
+		c.Key = hexToCompact(n.Key)
+		if _, ok := n.Val.(valueNode); !ok {
+			child, err := commitNodeTree(n.Val, db)
+			if err != nil {
+				return nil, err
+			}
+			c.Val = child
+		}
+		n.flags.dirty = false
+		collapsed = c
+	case *fullNode:
+		c := n.copy()
+		for i := 0; i < 16; i++ {
+			if n.Children[i] != nil {
+				child, err := commitNodeTree(n.Children[i], db)
+				if err != nil {
+					return nil, err
+				}
+				c.Children[i] = child
+			}
+		}
+		n.flags.dirty = false
+		collapsed = c
+	default:
+		return n, nil
+	}
+	if hash == nil {
+		return collapsed, nil
+	}
+	if err := db.Put(hash, encodeNode(collapsed)); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
@@ -0,0 +1,214 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/232425wxy/understanding-ethereum/rlp"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// node ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// node 是前缀树里所有节点类型的公共接口，前缀树由以下四种节点组成：
+//   - fullNode：分支节点，有16个子节点，分别对应下一个半字节的16种取值，第17个位置存放恰好在此处结束的键对应的值；
+//   - shortNode：扩展节点或叶子节点，Key 是一段公共的路径，如果 Key 带有终止符，那么它就是叶子节点，Val 是一个
+//     valueNode，否则它是扩展节点，Val 指向下一个节点；
+//   - hashNode：一个尚未从数据库里加载的节点，它的内容就是该节点的哈希值；
+//   - valueNode：存储在前缀树里的值。
+type node interface {
+	cache() (hashNode, bool)
+}
+
+type (
+	fullNode struct {
+		Children [17]node
+		flags    nodeFlag
+	}
+	shortNode struct {
+		Key   []byte
+		Val   node
+		flags nodeFlag
+	}
+	hashNode  []byte
+	valueNode []byte
+)
+
+// nodeFlag ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// nodeFlag 记录了节点的缓存信息：hash 是节点的哈希值（如果已经计算过的话），dirty 表示该节点自上次提交以来被修改过，
+// 还没有被写入数据库。
+type nodeFlag struct {
+	hash  hashNode
+	dirty bool
+}
+
+func (n *fullNode) cache() (hashNode, bool)  { return n.flags.hash, n.flags.dirty }
+func (n *shortNode) cache() (hashNode, bool) { return n.flags.hash, n.flags.dirty }
+func (n hashNode) cache() (hashNode, bool)   { return nil, true }
+func (n valueNode) cache() (hashNode, bool)  { return nil, true }
+
+func (n *fullNode) copy() *fullNode   { cpy := *n; return &cpy }
+func (n *shortNode) copy() *shortNode { cpy := *n; return &cpy }
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 🌹节点的编码🌹
+
+// encodeNode ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// encodeNode 方法计算一个"折叠"之后的节点的rlp编码，所谓折叠，是指 shortNode 的 Key 已经被转换成了COMPACT形式，
+// 并且所有子节点都已经被替换成了它们的哈希值（hashNode）或者内嵌的折叠节点（编码长度不足32字节的子节点）。编码规则如下：
+//   - shortNode 被编码成包含两个元素的列表：[COMPACT形式的Key, 子节点的引用或者值]；
+//   - fullNode 被编码成包含17个元素的列表：前16个元素是子节点的引用，空的子节点被编码成空字符串，最后一个元素是值。
+//
+// 每个元素都先被编码成 rlp.RawValue，然后再作为列表整体交给 rlp.EncodeToBytes 编码。
+func encodeNode(n node) []byte {
+	var elems []rlp.RawValue
+	switch n := n.(type) {
+	case *shortNode:
+		elems = []rlp.RawValue{encodeBytes(n.Key), encodeRef(n.Val)}
+	case *fullNode:
+		elems = make([]rlp.RawValue, 17)
+		for i, child := range &n.Children {
+			elems[i] = encodeRef(child)
+		}
+	case hashNode, valueNode:
+		return encodeRef(n)
+	default:
+		panic(fmt.Sprintf("trie: can't encode node of type %T", n))
+	}
+	enc, err := rlp.EncodeToBytes(elems)
+	if err != nil {
+		// 编码 []rlp.RawValue 不会出错
+		panic(err)
+	}
+	return enc
+}
+
+// encodeRef ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// encodeRef 方法计算节点作为父节点的一个元素时的编码：哈希值和值被编码成字符串，内嵌的节点直接使用它自身的编码，
+// 空节点被编码成空字符串。
+func encodeRef(n node) rlp.RawValue {
+	switch n := n.(type) {
+	case nil:
+		return rlp.EmptyString
+	case hashNode:
+		return encodeBytes(n)
+	case valueNode:
+		return encodeBytes(n)
+	default:
+		return encodeNode(n)
+	}
+}
+
+// encodeBytes 方法返回字节切片的rlp编码。
+func encodeBytes(bz []byte) rlp.RawValue {
+	enc, err := rlp.EncodeToBytes(bz)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 🌹节点的解码🌹
+
+// decodeNode ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeNode 方法借助 rlp.SplitList、rlp.CountValues 等方法解析节点的rlp编码，hash 是该节点的哈希值，它会被缓存
+// 在解码得到的节点里，对于内嵌的节点，hash 为nil。解码得到的节点直接引用buf里的数据，调用者不能再修改buf。
+func decodeNode(hash, buf []byte) (node, error) {
+	if len(buf) == 0 {
+		return nil, fmt.Errorf("trie: empty node encoding")
+	}
+	elems, _, err := rlp.SplitList(buf)
+	if err != nil {
+		return nil, fmt.Errorf("trie: decode error: %w", err)
+	}
+	switch c, _ := rlp.CountValues(elems); c {
+	case 2:
+		n, err := decodeShort(hash, elems)
+		if err != nil {
+			return nil, fmt.Errorf("trie: invalid short node: %w", err)
+		}
+		return n, nil
+	case 17:
+		n, err := decodeFull(hash, elems)
+		if err != nil {
+			return nil, fmt.Errorf("trie: invalid full node: %w", err)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("trie: invalid number of list elements: %v", c)
+	}
+}
+
+// decodeShort 方法解码 shortNode 的两个元素，如果 Key 带有终止符，第二个元素就是值，否则是子节点的引用。
+func decodeShort(hash, elems []byte) (node, error) {
+	kbuf, rest, err := rlp.SplitString(elems)
+	if err != nil {
+		return nil, err
+	}
+	flag := nodeFlag{hash: hash}
+	key := compactToHex(kbuf)
+	if hasTerm(key) {
+		val, _, err := rlp.SplitString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value node: %w", err)
+		}
+		return &shortNode{Key: key, Val: valueNode(val), flags: flag}, nil
+	}
+	r, _, err := decodeRef(rest)
+	if err != nil {
+		return nil, err
+	}
+	return &shortNode{Key: key, Val: r, flags: flag}, nil
+}
+
+// decodeFull 方法解码 fullNode 的17个元素。
+func decodeFull(hash, elems []byte) (*fullNode, error) {
+	n := &fullNode{flags: nodeFlag{hash: hash}}
+	for i := 0; i < 16; i++ {
+		child, rest, err := decodeRef(elems)
+		if err != nil {
+			return n, fmt.Errorf("child %d: %w", i, err)
+		}
+		n.Children[i], elems = child, rest
+	}
+	val, _, err := rlp.SplitString(elems)
+	if err != nil {
+		return n, err
+	}
+	if len(val) > 0 {
+		n.Children[16] = valueNode(val)
+	}
+	return n, nil
+}
+
+// decodeRef ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// decodeRef 方法解码一个子节点的引用，它可能是一个内嵌的节点（列表，编码长度不超过32字节）、一个空字符串（空节点）
+// 或者一个32字节的哈希值，第二个返回值是buf里剩下的数据。
+func decodeRef(buf []byte) (node, []byte, error) {
+	kind, val, rest, err := rlp.Split(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	switch {
+	case kind == rlp.List:
+		if size := len(buf) - len(rest); size > hashLen {
+			return nil, buf, fmt.Errorf("oversized embedded node (size is %d bytes, want size < %d)", size, hashLen)
+		}
+		n, err := decodeNode(nil, buf[:len(buf)-len(rest)])
+		return n, rest, err
+	case kind == rlp.String && len(val) == 0:
+		return nil, rest, nil
+	case kind == rlp.String && len(val) == hashLen:
+		return hashNode(val), rest, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid RLP string size %d (want 0 or %d)", len(val), hashLen)
+	}
+}
@@ -0,0 +1,531 @@
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/232425wxy/understanding-ethereum/crypto/sha3"
+)
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 🌹默克尔证明🌹
+
+// Prove ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Prove 方法为key生成默克尔证明，证明由从根节点到key所在位置的路径上所有节点的rlp编码组成，按照从根节点到叶子节点的
+// 顺序排列，这与 eth_getProof 接口返回的 accountProof 和 storageProof 的格式一样。内嵌在父节点里的短节点不会单独
+// 出现在证明里。如果key不存在，返回的证明可以用来证明它不存在。
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	var (
+		nodes  []node
+		prefix []byte
+		tn     = t.root
+	)
+	key = keybytesToHex(key)
+	for len(key) > 0 && tn != nil {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.Key) || !bytes.Equal(n.Key, key[:len(n.Key)]) {
+				// key不在前缀树里
+				tn = nil
+			} else {
+				tn = n.Val
+				prefix = append(prefix, n.Key...)
+				key = key[len(n.Key):]
+			}
+			nodes = append(nodes, n)
+		case *fullNode:
+			tn = n.Children[key[0]]
+			prefix = append(prefix, key[0])
+			key = key[1:]
+			nodes = append(nodes, n)
+		case hashNode:
+			resolved, err := t.resolveHash(n, prefix)
+			if err != nil {
+				return nil, err
+			}
+			tn = resolved
+		case valueNode:
+			tn = nil
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+	proof := make([][]byte, 0, len(nodes))
+	for i, n := range nodes {
+		// 根节点无论长短都要出现在证明里，其他节点只有在不能被内嵌时才需要
+		if enc := encodeNode(collapse(n)); len(enc) >= hashLen || i == 0 {
+			proof = append(proof, enc)
+		}
+	}
+	return proof, nil
+}
+
+// collapse 方法返回节点的折叠形式，它的子节点被替换为哈希值或者内嵌的折叠节点。
+func collapse(n node) node {
+	switch n := n.(type) {
+	case *shortNode:
+		c := n.copy()
+		c.Key = hexToCompact(n.Key)
+		if _, ok := n.Val.(valueNode); !ok {
+			c.Val, _ = hashNodeTree(n.Val, false)
+		}
+		return c
+	case *fullNode:
+		c := n.copy()
+		for i := 0; i < 16; i++ {
+			if n.Children[i] != nil {
+				c.Children[i], _ = hashNodeTree(n.Children[i], false)
+			}
+		}
+		return c
+	}
+	return n
+}
+
+// proofSet 以节点编码的哈希值为键，存储证明里的所有节点。
+type proofSet map[string][]byte
+
+// newProofSet 方法计算证明里每个节点的哈希值，重复的节点只保留一份。
+func newProofSet(proof [][]byte) proofSet {
+	set := make(proofSet, len(proof))
+	for _, enc := range proof {
+		set[string(sha3.Keccak256(enc))] = enc
+	}
+	return set
+}
+
+// resolve 方法从证明里找出哈希值为hash的节点并解码，由于 proofSet 是按照节点编码的哈希值索引的，能找到节点就说明
+// 该节点与父节点里记录的哈希值是吻合的。
+func (set proofSet) resolve(hash []byte) (node, error) {
+	buf, ok := set[string(hash)]
+	if !ok {
+		return nil, fmt.Errorf("proof node (hash %x) missing", hash)
+	}
+	n, err := decodeNode(hash, buf)
+	if err != nil {
+		return nil, fmt.Errorf("bad proof node %x: %w", hash, err)
+	}
+	return n, nil
+}
+
+// VerifyProof ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// VerifyProof 方法用默克尔证明验证key在根哈希为root的前缀树里对应的值，证明的格式与 Prove 方法和 eth_getProof 接口
+// 返回的格式一样，节点的顺序无关紧要。它从根哈希开始，每一步都用上一个节点里记录的哈希值在证明里找到下一个节点，然后借助
+// rlp.SplitList 等方法解析节点，沿着key向下走，直到找到值或者确认key不存在：
+//   - 如果key存在，返回它的值；
+//   - 如果证明表明key不存在（路径在某个空的子节点或者不匹配的短节点处中断），返回nil和nil，即不存在性证明；
+//   - 如果证明缺少需要的节点，或者某个节点无法被解码，返回错误。
+//
+// 空前缀树不需要任何节点就可以证明任何键都不存在。
+func VerifyProof(root, key []byte, proof [][]byte) ([]byte, error) {
+	if bytes.Equal(root, EmptyRoot) {
+		return nil, nil
+	}
+	set := newProofSet(proof)
+	key = keybytesToHex(key)
+	wantHash := root
+	for {
+		n, err := set.resolve(wantHash)
+		if err != nil {
+			return nil, err
+		}
+		keyRest, child := get(n, key, true)
+		switch child := child.(type) {
+		case nil:
+			// key不在前缀树里
+			return nil, nil
+		case hashNode:
+			key = keyRest
+			wantHash = child
+		case valueNode:
+			return child, nil
+		}
+	}
+}
+
+// get ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// get 方法沿着HEX形式的key从节点tn开始向下查找，遇到 hashNode、valueNode 或者路径中断时停下来，返回剩下的key和停下
+// 时所在的节点。如果skipResolved为false，则每向下走一步就返回一次。
+func get(tn node, key []byte, skipResolved bool) ([]byte, node) {
+	for {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.Key) || !bytes.Equal(n.Key, key[:len(n.Key)]) {
+				return nil, nil
+			}
+			tn = n.Val
+			key = key[len(n.Key):]
+			if !skipResolved {
+				return key, tn
+			}
+		case *fullNode:
+			tn = n.Children[key[0]]
+			key = key[1:]
+			if !skipResolved {
+				return key, tn
+			}
+		case hashNode:
+			return key, n
+		case nil:
+			return key, nil
+		case valueNode:
+			return nil, n
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// 🌹范围证明🌹
+
+// VerifyRangeProof ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// VerifyRangeProof 方法验证keys和values是否恰好是根哈希为root的前缀树里从firstKey开始的一段连续的键值对，也就是说，
+// 在firstKey和keys里最后一个键之间，前缀树里没有任何其他的键。proof 是firstKey和最后一个键的默克尔证明的并集（两条
+// 边界路径），firstKey不需要真实存在。keys必须严格递增，values里不能有空值。以下几种特殊情况也是允许的：
+//   - proof为nil：keys和values必须是整棵前缀树里所有的键值对；
+//   - keys为空：proof必须证明前缀树里不存在任何不小于firstKey的键；
+//   - 只有一个键并且它等于firstKey：proof就是这个键的普通证明。
+//
+// 验证的思路是：先用两条边界证明还原出前缀树的"骨架"，再删除两条边界路径之间的所有节点，然后把给定的键值对重新插入，
+// 如果计算出来的根哈希与root相同，就说明给定的范围是完整的。返回值hasMore表示在最后一个键的右边，前缀树里是否还有其他的键。
+// 除了只有一个键的情况，两个边界键的长度必须相同，这与以太坊状态树里的键都是32字节的哈希值相符。
+func VerifyRangeProof(root []byte, firstKey []byte, keys [][]byte, values [][]byte, proof [][]byte) (hasMore bool, err error) {
+	if len(keys) != len(values) {
+		return false, fmt.Errorf("inconsistent proof data, keys: %d, values: %d", len(keys), len(values))
+	}
+	for i := 0; i < len(keys)-1; i++ {
+		if bytes.Compare(keys[i], keys[i+1]) >= 0 {
+			return false, errors.New("range is not monotonically increasing")
+		}
+	}
+	for _, value := range values {
+		if len(value) == 0 {
+			return false, errors.New("range contains deletion")
+		}
+	}
+	// 没有边界证明，给定的键值对应当就是整棵树
+	if proof == nil {
+		tr := NewEmpty(nil)
+		for i, key := range keys {
+			tr.Update(key, values[i])
+		}
+		if have := tr.Hash(); !bytes.Equal(have, root) {
+			return false, fmt.Errorf("invalid proof, want hash %x, got %x", root, have)
+		}
+		return false, nil
+	}
+	set := newProofSet(proof)
+	// 有边界证明但没有键值对，证明firstKey右边没有任何键
+	if len(keys) == 0 {
+		rootNode, val, err := proofToPath(root, nil, firstKey, set, true)
+		if err != nil {
+			return false, err
+		}
+		if val != nil || hasRightElement(rootNode, firstKey) {
+			return false, errors.New("more entries available")
+		}
+		return false, nil
+	}
+	if bytes.Compare(firstKey, keys[0]) > 0 {
+		return false, errors.New("first key is greater than the first element of the range")
+	}
+	lastKey := keys[len(keys)-1]
+	// 只有一个键，并且两个边界是同一个键，这时无法构造出两条边界路径，单独处理
+	if len(keys) == 1 && bytes.Equal(firstKey, lastKey) {
+		rootNode, val, err := proofToPath(root, nil, firstKey, set, false)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(val, values[0]) {
+			return false, errors.New("correct proof but invalid data")
+		}
+		return hasRightElement(rootNode, firstKey), nil
+	}
+	if len(firstKey) != len(lastKey) {
+		return false, errors.New("inconsistent edge keys")
+	}
+	// 把两条边界证明转换成前缀树里的两条路径，第二条路径会被合并到第一条路径上，两条边界都允许指向不存在的键
+	rootNode, _, err := proofToPath(root, nil, firstKey, set, true)
+	if err != nil {
+		return false, err
+	}
+	rootNode, _, err = proofToPath(root, rootNode, lastKey, set, true)
+	if err != nil {
+		return false, err
+	}
+	// 删除两条路径之间的所有节点，它们应当由给定的键值对重新构造出来
+	empty, err := unsetInternal(rootNode, firstKey, lastKey)
+	if err != nil {
+		return false, err
+	}
+	tr := &Trie{root: rootNode}
+	if empty {
+		tr.root = nil
+	}
+	for i, key := range keys {
+		// 范围内的节点都已经被删除了，如果插入时还需要加载节点，说明证明是不完整的
+		if err := tr.Update(key, values[i]); err != nil {
+			return false, fmt.Errorf("invalid proof: %w", err)
+		}
+	}
+	if have := tr.Hash(); !bytes.Equal(have, root) {
+		return false, fmt.Errorf("invalid proof, want hash %x, got %x", root, have)
+	}
+	return hasRightElement(tr.root, lastKey), nil
+}
+
+// proofToPath ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// proofToPath 方法把key的默克尔证明转换成一条从根节点出发的路径，路径上的 hashNode 都会被替换成证明里对应的节点，
+// 路径以外的子节点依然是 hashNode。如果root不为nil，新的路径会被合并到这棵已经部分还原的树上。allowNonExistent 为
+// true 时，证明可以是不存在性证明，此时返回的值为nil。
+func proofToPath(rootHash []byte, root node, key []byte, set proofSet, allowNonExistent bool) (node, []byte, error) {
+	if root == nil {
+		n, err := set.resolve(rootHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		root = n
+	}
+	var (
+		err     error
+		child   node
+		parent  = root
+		keyRest []byte
+		valNode []byte
+	)
+	key = keybytesToHex(key)
+	for {
+		keyRest, child = get(parent, key, false)
+		switch c := child.(type) {
+		case nil:
+			// 前缀树里没有这个键，但是路径上所有的节点都已经被验证过了，这对于范围证明来说就足够了
+			if allowNonExistent {
+				return root, nil, nil
+			}
+			return nil, nil, errors.New("the node is not contained in trie")
+		case *shortNode, *fullNode:
+			// 内嵌的节点已经被解码出来了
+			key, parent = keyRest, child
+			continue
+		case hashNode:
+			child, err = set.resolve(c)
+			if err != nil {
+				return nil, nil, err
+			}
+		case valueNode:
+			valNode = c
+		}
+		// 把加载出来的子节点挂到父节点上
+		switch p := parent.(type) {
+		case *shortNode:
+			p.Val = child
+		case *fullNode:
+			p.Children[key[0]] = child
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", p, p))
+		}
+		if len(valNode) > 0 {
+			return root, valNode, nil
+		}
+		key, parent = keyRest, child
+	}
+}
+
+// unsetInternal ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// unsetInternal 方法删除左右两条边界路径之间的所有节点，只保留两条路径本身以及路径以外的节点。它先沿着两条路径找到
+// 它们分叉的节点，然后分别清理分叉点左右两侧的路径。被修改的节点的哈希缓存会被清除。如果整棵树都落在范围内，返回true。
+func unsetInternal(n node, left []byte, right []byte) (bool, error) {
+	left, right = keybytesToHex(left), keybytesToHex(right)
+	var (
+		pos    = 0
+		parent node
+		// 0表示路径与短节点的 Key 匹配，-1表示路径小于 Key，1表示路径大于 Key
+		shortForkLeft, shortForkRight int
+	)
+findFork:
+	for {
+		switch rn := n.(type) {
+		case *shortNode:
+			rn.flags = newFlag()
+			// 只要有一条路径与短节点的 Key 不匹配，分叉点就是这个短节点
+			if len(left)-pos < len(rn.Key) {
+				shortForkLeft = bytes.Compare(left[pos:], rn.Key)
+			} else {
+				shortForkLeft = bytes.Compare(left[pos:pos+len(rn.Key)], rn.Key)
+			}
+			if len(right)-pos < len(rn.Key) {
+				shortForkRight = bytes.Compare(right[pos:], rn.Key)
+			} else {
+				shortForkRight = bytes.Compare(right[pos:pos+len(rn.Key)], rn.Key)
+			}
+			if shortForkLeft != 0 || shortForkRight != 0 {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.Val, pos+len(rn.Key)
+		case *fullNode:
+			rn.flags = newFlag()
+			// 两条路径走向不同的子节点，分叉点就是这个分支节点
+			leftNode, rightNode := rn.Children[left[pos]], rn.Children[right[pos]]
+			if leftNode == nil || rightNode == nil || left[pos] != right[pos] {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.Children[left[pos]], pos+1
+		default:
+			return false, fmt.Errorf("invalid proof: unexpected %T on edge path", n)
+		}
+	}
+	switch rn := n.(type) {
+	case *shortNode:
+		// 两条路径都小于或者都大于短节点的 Key，范围是空的
+		if shortForkLeft == -1 && shortForkRight == -1 {
+			return false, errors.New("empty range")
+		}
+		if shortForkLeft == 1 && shortForkRight == 1 {
+			return false, errors.New("empty range")
+		}
+		// 左边界小于 Key，右边界大于 Key，整个短节点都在范围内
+		if shortForkLeft != 0 && shortForkRight != 0 {
+			if parent == nil {
+				return true, nil
+			}
+			return false, removeChild(parent, left[pos-1])
+		}
+		// 只有右边界大于 Key，清理左边界路径的右侧
+		if shortForkRight != 0 {
+			if _, ok := rn.Val.(valueNode); ok {
+				if parent == nil {
+					return true, nil
+				}
+				return false, removeChild(parent, left[pos-1])
+			}
+			return false, unset(rn, rn.Val, left[pos:], len(rn.Key), false)
+		}
+		// 只有左边界小于 Key，清理右边界路径的左侧
+		if shortForkLeft != 0 {
+			if _, ok := rn.Val.(valueNode); ok {
+				if parent == nil {
+					return true, nil
+				}
+				return false, removeChild(parent, right[pos-1])
+			}
+			return false, unset(rn, rn.Val, right[pos:], len(rn.Key), true)
+		}
+		return false, nil
+	case *fullNode:
+		// 删除分叉点里两条路径之间的所有子节点，然后分别清理两条路径
+		for i := left[pos] + 1; i < right[pos]; i++ {
+			rn.Children[i] = nil
+		}
+		if err := unset(rn, rn.Children[left[pos]], left[pos:], 1, false); err != nil {
+			return false, err
+		}
+		if err := unset(rn, rn.Children[right[pos]], right[pos:], 1, true); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid proof: unexpected %T at fork point", n)
+	}
+}
+
+// unset ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// unset 方法沿着key清理一条边界路径：removeLeft 为true时删除路径左侧的所有节点（右边界），否则删除路径右侧的所有节点
+// （左边界）。路径终点处的值也在范围内，同样会被删除，之后由给定的键值对重新插入。
+func unset(parent node, child node, key []byte, pos int, removeLeft bool) error {
+	switch c := child.(type) {
+	case *fullNode:
+		if pos >= len(key) {
+			return errors.New("invalid proof: edge path is too long")
+		}
+		if removeLeft {
+			for i := 0; i < int(key[pos]); i++ {
+				c.Children[i] = nil
+			}
+		} else {
+			for i := key[pos] + 1; i < 16; i++ {
+				c.Children[i] = nil
+			}
+		}
+		c.flags = newFlag()
+		return unset(c, c.Children[key[pos]], key, pos+1, removeLeft)
+	case *shortNode:
+		if len(key[pos:]) < len(c.Key) || !bytes.Equal(c.Key, key[pos:pos+len(c.Key)]) {
+			// 边界路径在这里中断了，如果这个短节点落在范围内，就删除整个分支，否则保留它
+			if removeLeft {
+				if bytes.Compare(c.Key, key[pos:]) < 0 {
+					return removeChild(parent, key[pos-1])
+				}
+			} else {
+				if bytes.Compare(c.Key, key[pos:]) > 0 {
+					return removeChild(parent, key[pos-1])
+				}
+			}
+			return nil
+		}
+		if _, ok := c.Val.(valueNode); ok {
+			return removeChild(parent, key[pos-1])
+		}
+		c.flags = newFlag()
+		return unset(c, c.Val, key, pos+len(c.Key), removeLeft)
+	case nil:
+		// 分叉点的分支节点里不存在的子节点
+		return nil
+	default:
+		return fmt.Errorf("invalid proof: unexpected %T on edge path", child)
+	}
+}
+
+// removeChild ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// removeChild 方法删除分支节点parent的第idx个子节点。证明是由外部提供的，边界路径上的父节点不一定是分支节点，这种
+// 情况下返回错误。
+func removeChild(parent node, idx byte) error {
+	p, ok := parent.(*fullNode)
+	if !ok {
+		return fmt.Errorf("invalid proof: unexpected %T on edge path, want branch node", parent)
+	}
+	p.Children[idx] = nil
+	return nil
+}
+
+// hasRightElement ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// hasRightElement 方法判断在还原出来的树里，key的右侧是否还有其他的键。
+func hasRightElement(n node, key []byte) bool {
+	pos, key := 0, keybytesToHex(key)
+	for n != nil {
+		switch rn := n.(type) {
+		case *fullNode:
+			for i := key[pos] + 1; i < 16; i++ {
+				if rn.Children[i] != nil {
+					return true
+				}
+			}
+			n, pos = rn.Children[key[pos]], pos+1
+		case *shortNode:
+			if len(key)-pos < len(rn.Key) || !bytes.Equal(rn.Key, key[pos:pos+len(rn.Key)]) {
+				return bytes.Compare(rn.Key, key[pos:]) > 0
+			}
+			n, pos = rn.Val, pos+len(rn.Key)
+		case valueNode:
+			return false
+		default:
+			// 边界路径上的节点都已经被还原了，不会遇到 hashNode
+			return false
+		}
+	}
+	return false
+}
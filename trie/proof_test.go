@@ -0,0 +1,314 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+	"github.com/232425wxy/understanding-ethereum/crypto/sha3"
+	"github.com/stretchr/testify/assert"
+)
+
+// testdata/proofs.json 记录了 rootVectors 里几棵前缀树的证明，这些前缀树的根哈希来自以太坊官方的测试向量，证明的格式
+// 与 eth_getProof 返回的格式一样：从根节点到叶子节点排列的节点rlp编码。value 为null的记录是不存在性证明。
+type proofFixture struct {
+	Name  string   `json:"name"`
+	Root  string   `json:"root"`
+	Key   string   `json:"key"`
+	Value *string  `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+func loadProofFixtures(t *testing.T) []proofFixture {
+	data, err := os.ReadFile("testdata/proofs.json")
+	assert.Nil(t, err)
+	var fixtures []proofFixture
+	assert.Nil(t, json.Unmarshal(data, &fixtures))
+	assert.NotEmpty(t, fixtures)
+	return fixtures
+}
+
+func fixtureProof(f proofFixture) [][]byte {
+	proof := make([][]byte, len(f.Proof))
+	for i, p := range f.Proof {
+		proof[i] = common.FromHex(p)
+	}
+	return proof
+}
+
+func TestVerifyProofFixtures(t *testing.T) {
+	for _, f := range loadProofFixtures(t) {
+		root, key, proof := common.FromHex(f.Root), common.FromHex(f.Key), fixtureProof(f)
+		val, err := VerifyProof(root, key, proof)
+		assert.Nil(t, err, "%s/%s", f.Name, f.Key)
+		if f.Value == nil {
+			assert.Nil(t, val, "%s/%s", f.Name, f.Key)
+		} else {
+			assert.Equal(t, common.FromHex(*f.Value), val, "%s/%s", f.Name, f.Key)
+		}
+
+		// 节点的顺序无关紧要
+		reversed := make([][]byte, len(proof))
+		for i := range proof {
+			reversed[len(proof)-1-i] = proof[i]
+		}
+		val2, err := VerifyProof(root, key, reversed)
+		assert.Nil(t, err)
+		assert.Equal(t, val, val2)
+
+		// 篡改任何一个节点都会导致验证失败
+		for i := range proof {
+			bad := append([][]byte{}, proof...)
+			bad[i] = common.CopyBytes(proof[i])
+			bad[i][len(bad[i])-1] ^= 0x01
+			_, err := VerifyProof(root, key, bad)
+			assert.NotNil(t, err, "%s/%s: tampered node %d", f.Name, f.Key, i)
+		}
+		// 错误的根哈希
+		_, err = VerifyProof(EmptyRoot[:31], key, proof)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestProve(t *testing.T) {
+	db := NewMemoryDB()
+	tr := NewEmpty(db)
+	keys := randomKeys(300)
+	for i, key := range keys {
+		tr.Update(key, valueFor(i))
+	}
+	root, err := tr.Commit()
+	assert.Nil(t, err)
+
+	// 从数据库重新打开的前缀树也能生成证明
+	reopened, err := New(root, db)
+	assert.Nil(t, err)
+	for _, tr := range []*Trie{tr, reopened} {
+		for i, key := range keys {
+			proof, err := tr.Prove(key)
+			assert.Nil(t, err)
+			val, err := VerifyProof(root, key, proof)
+			assert.Nil(t, err)
+			assert.Equal(t, valueFor(i), val)
+
+			// 去掉任何一个节点都会导致验证失败
+			for j := range proof {
+				partial := append(append([][]byte{}, proof[:j]...), proof[j+1:]...)
+				_, err := VerifyProof(root, key, partial)
+				assert.NotNil(t, err)
+			}
+		}
+	}
+
+	// 不存在的键
+	for _, key := range [][]byte{{}, {0x00}, append(common.CopyBytes(keys[0]), 0x00), bytes.Repeat([]byte{0xFF}, 40)} {
+		proof, err := tr.Prove(key)
+		assert.Nil(t, err)
+		val, err := VerifyProof(root, key, proof)
+		assert.Nil(t, err)
+		assert.Nil(t, val)
+	}
+
+	// 空前缀树
+	empty := NewEmpty(NewMemoryDB())
+	proof, err := empty.Prove([]byte("key"))
+	assert.Nil(t, err)
+	assert.Empty(t, proof)
+	val, err := VerifyProof(empty.Hash(), []byte("key"), proof)
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+}
+
+type rangeEntry struct {
+	k, v []byte
+}
+
+// makeRangeTrie 方法创建一棵键长固定为8字节的前缀树，并返回按键排序的所有键值对。
+func makeRangeTrie(n int) (*Trie, []rangeEntry) {
+	rnd := rand.New(rand.NewSource(int64(n)))
+	tr := NewEmpty(NewMemoryDB())
+	seen := make(map[uint64]bool)
+	var entries []rangeEntry
+	for len(entries) < n {
+		x := rnd.Uint64()
+		if n < 100 {
+			// 键比较稀疏时，让它们共享更长的前缀
+			x &= 0xFFFF0000FFFF00FF
+		}
+		if seen[x] {
+			continue
+		}
+		seen[x] = true
+		k := binary.BigEndian.AppendUint64(nil, x)
+		v := valueFor(len(entries))
+		tr.Update(k, v)
+		entries = append(entries, rangeEntry{k, v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].k, entries[j].k) < 0 })
+	return tr, entries
+}
+
+func rangeProof(t *testing.T, tr *Trie, first, last []byte) [][]byte {
+	p1, err := tr.Prove(first)
+	assert.Nil(t, err)
+	p2, err := tr.Prove(last)
+	assert.Nil(t, err)
+	return append(p1, p2...)
+}
+
+func splitEntries(entries []rangeEntry) (keys, values [][]byte) {
+	for _, e := range entries {
+		keys = append(keys, e.k)
+		values = append(values, e.v)
+	}
+	return keys, values
+}
+
+func TestRangeProof(t *testing.T) {
+	for _, size := range []int{20, 500} {
+		tr, entries := makeRangeTrie(size)
+		root := tr.Hash()
+		rnd := rand.New(rand.NewSource(2))
+		for i := 0; i < 100; i++ {
+			start := rnd.Intn(len(entries))
+			end := start + 1 + rnd.Intn(len(entries)-start)
+			keys, values := splitEntries(entries[start:end])
+			proof := rangeProof(t, tr, keys[0], keys[len(keys)-1])
+			hasMore, err := VerifyRangeProof(root, keys[0], keys, values, proof)
+			assert.Nil(t, err, "size %d range [%d, %d)", size, start, end)
+			assert.Equal(t, end < len(entries), hasMore, "size %d range [%d, %d)", size, start, end)
+
+			// 左边界可以是一个不存在的键，只要它和第一个键之间没有其他的键
+			if start > 0 {
+				first := increaseKey(common.CopyBytes(entries[start-1].k))
+				if !bytes.Equal(first, keys[0]) {
+					proof := rangeProof(t, tr, first, keys[len(keys)-1])
+					hasMore, err := VerifyRangeProof(root, first, keys, values, proof)
+					assert.Nil(t, err, "size %d range [%d, %d) with non-existent first key", size, start, end)
+					assert.Equal(t, end < len(entries), hasMore)
+				}
+			}
+		}
+	}
+}
+
+func TestRangeProofSpecialCases(t *testing.T) {
+	tr, entries := makeRangeTrie(200)
+	root := tr.Hash()
+
+	// 没有边界证明：必须给出整棵树的键值对
+	keys, values := splitEntries(entries)
+	hasMore, err := VerifyRangeProof(root, nil, keys, values, nil)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	_, err = VerifyRangeProof(root, nil, keys[1:], values[1:], nil)
+	assert.NotNil(t, err)
+
+	// 只有一个键
+	for _, i := range []int{0, 100, len(entries) - 1} {
+		proof, _ := tr.Prove(entries[i].k)
+		hasMore, err := VerifyRangeProof(root, entries[i].k, [][]byte{entries[i].k}, [][]byte{entries[i].v}, proof)
+		assert.Nil(t, err)
+		assert.Equal(t, i < len(entries)-1, hasMore)
+		_, err = VerifyRangeProof(root, entries[i].k, [][]byte{entries[i].k}, [][]byte{[]byte("wrong")}, proof)
+		assert.NotNil(t, err)
+	}
+
+	// 没有键值对：证明最后一个键的右边没有任何键
+	last := increaseKey(common.CopyBytes(entries[len(entries)-1].k))
+	proof, _ := tr.Prove(last)
+	hasMore, err = VerifyRangeProof(root, last, nil, nil, proof)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	proof, _ = tr.Prove(entries[10].k)
+	_, err = VerifyRangeProof(root, entries[10].k, nil, nil, proof)
+	assert.NotNil(t, err)
+}
+
+func TestBadRangeProof(t *testing.T) {
+	tr, entries := makeRangeTrie(500)
+	root := tr.Hash()
+	start, end := 100, 200
+	keys, values := splitEntries(entries[start:end])
+	proof := rangeProof(t, tr, keys[0], keys[len(keys)-1])
+	_, err := VerifyRangeProof(root, keys[0], keys, values, proof)
+	assert.Nil(t, err)
+
+	clone := func() ([][]byte, [][]byte) {
+		return append([][]byte{}, keys...), append([][]byte{}, values...)
+	}
+	// 缺少中间的一个键值对
+	k, v := clone()
+	k, v = append(k[:50], k[51:]...), append(v[:50], v[51:]...)
+	_, err = VerifyRangeProof(root, k[0], k, v, proof)
+	assert.NotNil(t, err)
+
+	// 值被修改
+	k, v = clone()
+	v[30] = []byte("modified")
+	_, err = VerifyRangeProof(root, k[0], k, v, proof)
+	assert.NotNil(t, err)
+
+	// 键不是递增的
+	k, v = clone()
+	k[10], k[11] = k[11], k[10]
+	_, err = VerifyRangeProof(root, k[0], k, v, proof)
+	assert.NotNil(t, err)
+
+	// 包含空值
+	k, v = clone()
+	v[5] = nil
+	_, err = VerifyRangeProof(root, k[0], k, v, proof)
+	assert.NotNil(t, err)
+
+	// 左边界与第一个键之间还有其他键（跳过了第一个键）
+	k, v = clone()
+	_, err = VerifyRangeProof(root, keys[0], k[1:], v[1:], proof)
+	assert.NotNil(t, err)
+
+	// 证明缺少节点（两条边界证明里都有根节点，需要把它们都去掉）
+	var partial [][]byte
+	for _, p := range proof {
+		if !bytes.Equal(p, proof[0]) {
+			partial = append(partial, p)
+		}
+	}
+	_, err = VerifyRangeProof(root, keys[0], keys, values, partial)
+	assert.NotNil(t, err)
+
+	// 键值对数量不一致
+	_, err = VerifyRangeProof(root, keys[0], keys, values[1:], proof)
+	assert.NotNil(t, err)
+}
+
+// TestRangeProofMalformed 构造一个恶意的证明：根节点是一个扩展节点，它的子节点是一个叶子节点，而不是边界路径所期望的
+// 分支节点，验证时应当返回错误而不是panic。
+func TestRangeProofMalformed(t *testing.T) {
+	leafEnc := encodeNode(&shortNode{Key: hexToCompact([]byte{5, 16}), Val: valueNode(bytes.Repeat([]byte{0xaa}, 40))})
+	leafHash := sha3.Keccak256(leafEnc)
+	rootEnc := encodeNode(&shortNode{Key: hexToCompact([]byte{1}), Val: hashNode(leafHash)})
+	root := sha3.Keccak256(rootEnc)
+
+	keys := [][]byte{{0x10}, {0x1f}}
+	values := [][]byte{{1}, {2}}
+	assert.NotPanics(t, func() {
+		_, err := VerifyRangeProof(root, []byte{0x10}, keys, values, [][]byte{rootEnc, leafEnc})
+		assert.NotNil(t, err)
+	})
+}
+
+// increaseKey 方法把key当作一个大端序的整数加1。
+func increaseKey(key []byte) []byte {
+	for i := len(key) - 1; i >= 0; i-- {
+		key[i]++
+		if key[i] != 0x0 {
+			break
+		}
+	}
+	return key
+}
@@ -0,0 +1,352 @@
+// Package trie 实现了以太坊的默克尔-帕特里夏前缀树（Merkle Patricia Trie），区块头里的交易根、收据根和状态根都是
+// 这种前缀树的根哈希。前缀树的节点使用 rlp 包进行编码，节点之间通过节点编码的 Keccak-256 哈希值互相引用，整棵树可以
+// 被保存在任意实现了 KeyValueStore 接口的键值存储里。
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+)
+
+// EmptyRoot 是空前缀树的根哈希，等于 keccak256(rlp.EmptyString)。
+var EmptyRoot = common.Hex2Bytes("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// MissingNodeError ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// MissingNodeError 表示前缀树在键值存储里找不到某个节点，NodeHash 是该节点的哈希值，Path 是从根节点到该节点所经过的
+// HEX形式的路径。
+type MissingNodeError struct {
+	NodeHash []byte
+	Path     []byte
+	err      error
+}
+
+// Error 方法实现了 error 接口。
+func (err *MissingNodeError) Error() string {
+	return fmt.Sprintf("missing trie node %x (path %x) %v", err.NodeHash, err.Path, err.err)
+}
+
+// Unwrap 方法返回键值存储返回的原始错误。
+func (err *MissingNodeError) Unwrap() error {
+	return err.err
+}
+
+/*⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓⛓*/
+
+// Trie ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Trie 是一棵默克尔-帕特里夏前缀树。对前缀树的修改只发生在内存里，调用 Hash 可以得到修改后的根哈希，调用 Commit
+// 才会把修改过的节点写入键值存储。从键值存储打开的前缀树只会在访问到某个节点时才去加载它。Trie 不是并发安全的。
+type Trie struct {
+	root node
+	db   KeyValueStore
+}
+
+// New ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// New 方法从键值存储db里打开根哈希为root的前缀树，root为空或者等于 EmptyRoot 时返回一棵空树。如果db里找不到根节点，
+// 则返回 *MissingNodeError。
+func New(root []byte, db KeyValueStore) (*Trie, error) {
+	t := &Trie{db: db}
+	if len(root) != 0 && !bytes.Equal(root, EmptyRoot) {
+		rootNode, err := t.resolveHash(root, nil)
+		if err != nil {
+			return nil, err
+		}
+		t.root = rootNode
+	}
+	return t, nil
+}
+
+// NewEmpty ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// NewEmpty 方法返回一棵以db为后端存储的空前缀树。
+func NewEmpty(db KeyValueStore) *Trie {
+	return &Trie{db: db}
+}
+
+// Get ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Get 方法返回key对应的值，key不存在时返回nil，只有在键值存储里找不到需要的节点时才会返回错误。返回的字节切片不能被修改。
+func (t *Trie) Get(key []byte) ([]byte, error) {
+	value, newRoot, didResolve, err := t.get(t.root, keybytesToHex(key), 0)
+	if err == nil && didResolve {
+		t.root = newRoot
+	}
+	return value, err
+}
+
+// get 方法沿着HEX形式的key从节点n开始向下查找，pos是已经匹配的半字节数，被加载的节点会替换掉原来的 hashNode。
+func (t *Trie) get(origNode node, key []byte, pos int) (value []byte, newNode node, didResolve bool, err error) {
+	switch n := origNode.(type) {
+	case nil:
+		return nil, nil, false, nil
+	case valueNode:
+		return n, n, false, nil
+	case *shortNode:
+		if len(key)-pos < len(n.Key) || !bytes.Equal(n.Key, key[pos:pos+len(n.Key)]) {
+			return nil, n, false, nil
+		}
+		value, newNode, didResolve, err = t.get(n.Val, key, pos+len(n.Key))
+		if err == nil && didResolve {
+			n = n.copy()
+			n.Val = newNode
+		}
+		return value, n, didResolve, err
+	case *fullNode:
+		value, newNode, didResolve, err = t.get(n.Children[key[pos]], key, pos+1)
+		if err == nil && didResolve {
+			n = n.copy()
+			n.Children[key[pos]] = newNode
+		}
+		return value, n, didResolve, err
+	case hashNode:
+		child, err := t.resolveHash(n, key[:pos])
+		if err != nil {
+			return nil, n, true, err
+		}
+		value, newNode, _, err := t.get(child, key, pos)
+		return value, newNode, true, err
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", origNode, origNode))
+	}
+}
+
+// Update ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Update 方法将key对应的值设置为value，如果value为空，则相当于删除key。value被前缀树引用之后，调用者不能再修改它。
+func (t *Trie) Update(key, value []byte) error {
+	k := keybytesToHex(key)
+	if len(value) == 0 {
+		return t.Delete(key)
+	}
+	_, n, err := t.insert(t.root, nil, k, valueNode(value))
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+// insert ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// insert 方法将值插入到以n为根的子树里，prefix是从根节点到n的路径，key是剩下的路径。第一个返回值表示子树是否被修改，
+// 第二个返回值是修改后的子树。被修改的节点都会被复制一份，原来的节点保持不变。
+func (t *Trie) insert(n node, prefix, key []byte, value node) (bool, node, error) {
+	if len(key) == 0 {
+		if v, ok := n.(valueNode); ok {
+			return !bytes.Equal(v, value.(valueNode)), value, nil
+		}
+		return true, value, nil
+	}
+	switch n := n.(type) {
+	case *shortNode:
+		matchLen := prefixLen(key, n.Key)
+		// 整个 Key 都匹配上了，继续向子节点插入
+		if matchLen == len(n.Key) {
+			dirty, nn, err := t.insert(n.Val, append(prefix, key[:matchLen]...), key[matchLen:], value)
+			if !dirty || err != nil {
+				return false, n, err
+			}
+			return true, &shortNode{Key: n.Key, Val: nn, flags: newFlag()}, nil
+		}
+		// 在第一个不同的半字节处分叉，创建一个分支节点
+		branch := &fullNode{flags: newFlag()}
+		var err error
+		_, branch.Children[n.Key[matchLen]], err = t.insert(nil, append(prefix, n.Key[:matchLen+1]...), n.Key[matchLen+1:], n.Val)
+		if err != nil {
+			return false, nil, err
+		}
+		_, branch.Children[key[matchLen]], err = t.insert(nil, append(prefix, key[:matchLen+1]...), key[matchLen+1:], value)
+		if err != nil {
+			return false, nil, err
+		}
+		if matchLen == 0 {
+			return true, branch, nil
+		}
+		// 公共前缀部分变成一个扩展节点
+		return true, &shortNode{Key: key[:matchLen], Val: branch, flags: newFlag()}, nil
+	case *fullNode:
+		dirty, nn, err := t.insert(n.Children[key[0]], append(prefix, key[0]), key[1:], value)
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		n = n.copy()
+		n.flags = newFlag()
+		n.Children[key[0]] = nn
+		return true, n, nil
+	case nil:
+		return true, &shortNode{Key: key, Val: value, flags: newFlag()}, nil
+	case hashNode:
+		rn, err := t.resolveHash(n, prefix)
+		if err != nil {
+			return false, nil, err
+		}
+		dirty, nn, err := t.insert(rn, prefix, key, value)
+		if !dirty || err != nil {
+			return false, rn, err
+		}
+		return true, nn, nil
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// Delete ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Delete 方法从前缀树里删除key，key不存在时什么也不做。
+func (t *Trie) Delete(key []byte) error {
+	_, n, err := t.delete(t.root, nil, keybytesToHex(key))
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+// delete ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// delete 方法从以n为根的子树里删除key，删除之后会对树的结构进行规整：只剩一个子节点的分支节点会被合并成 shortNode，
+// 相邻的两个 shortNode 也会被合并成一个，这样同样的键值集合总是对应同样的树结构，从而得到同样的根哈希。
+func (t *Trie) delete(n node, prefix, key []byte) (bool, node, error) {
+	switch n := n.(type) {
+	case *shortNode:
+		matchLen := prefixLen(key, n.Key)
+		if matchLen < len(n.Key) {
+			// key不在这棵子树里
+			return false, n, nil
+		}
+		if matchLen == len(key) {
+			// 恰好删除的是这个叶子节点
+			return true, nil, nil
+		}
+		dirty, child, err := t.delete(n.Val, append(prefix, key[:len(n.Key)]...), key[len(n.Key):])
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		switch child := child.(type) {
+		case *shortNode:
+			// 子节点也是 shortNode，把两段路径拼接起来
+			return true, &shortNode{Key: concat(n.Key, child.Key...), Val: child.Val, flags: newFlag()}, nil
+		default:
+			return true, &shortNode{Key: n.Key, Val: child, flags: newFlag()}, nil
+		}
+	case *fullNode:
+		dirty, nn, err := t.delete(n.Children[key[0]], append(prefix, key[0]), key[1:])
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		n = n.copy()
+		n.flags = newFlag()
+		n.Children[key[0]] = nn
+		if nn != nil {
+			return true, n, nil
+		}
+		// 检查分支节点是否只剩下一个子节点，pos>=0时它就是唯一的子节点的索引，pos=-2表示还有多个子节点
+		pos := -1
+		for i, child := range &n.Children {
+			if child != nil {
+				if pos == -1 {
+					pos = i
+				} else {
+					pos = -2
+					break
+				}
+			}
+		}
+		if pos >= 0 {
+			if pos != 16 {
+				child, err := t.resolve(n.Children[pos], append(prefix, byte(pos)))
+				if err != nil {
+					return false, nil, err
+				}
+				if child, ok := child.(*shortNode); ok {
+					k := concat([]byte{byte(pos)}, child.Key...)
+					return true, &shortNode{Key: k, Val: child.Val, flags: newFlag()}, nil
+				}
+			}
+			return true, &shortNode{Key: []byte{byte(pos)}, Val: n.Children[pos], flags: newFlag()}, nil
+		}
+		return true, n, nil
+	case valueNode:
+		return true, nil, nil
+	case nil:
+		return false, nil, nil
+	case hashNode:
+		rn, err := t.resolveHash(n, prefix)
+		if err != nil {
+			return false, nil, err
+		}
+		dirty, nn, err := t.delete(rn, prefix, key)
+		if !dirty || err != nil {
+			return false, rn, err
+		}
+		return true, nn, nil
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v (%v)", n, n, key))
+	}
+}
+
+// Hash ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Hash 方法返回前缀树的根哈希，空前缀树的根哈希是 EmptyRoot。计算出来的哈希值会被缓存在节点里，该方法不会访问键值存储。
+func (t *Trie) Hash() []byte {
+	if t.root == nil {
+		return common.CopyBytes(EmptyRoot)
+	}
+	hashed, cached := hashNodeTree(t.root, true)
+	t.root = cached
+	return common.CopyBytes(hashed.(hashNode))
+}
+
+// Commit ♏ |作者：吴翔宇| 🍁 |日期：2026/10/16|
+//
+// Commit 方法把所有被修改过的节点写入键值存储，然后返回根哈希，之后可以用这个根哈希和同一个键值存储通过 New 重新打开
+// 这棵前缀树。提交之后前缀树依然可以继续使用。
+func (t *Trie) Commit() ([]byte, error) {
+	root := t.Hash()
+	if t.root == nil {
+		return root, nil
+	}
+	if _, err := commitNodeTree(t.root, t.db); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// resolve 方法在n是 hashNode 时从键值存储里加载它，否则直接返回n。
+func (t *Trie) resolve(n node, prefix []byte) (node, error) {
+	if n, ok := n.(hashNode); ok {
+		return t.resolveHash(n, prefix)
+	}
+	return n, nil
+}
+
+// resolveHash 方法从键值存储里加载哈希值为n的节点并解码。
+func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
+	if t.db == nil {
+		return nil, &MissingNodeError{NodeHash: n, Path: prefix}
+	}
+	enc, err := t.db.Get(n)
+	if err != nil || len(enc) == 0 {
+		return nil, &MissingNodeError{NodeHash: n, Path: common.CopyBytes(prefix), err: err}
+	}
+	return decodeNode(n, enc)
+}
+
+// newFlag 方法返回新创建或被修改的节点使用的标志。
+func newFlag() nodeFlag {
+	return nodeFlag{dirty: true}
+}
+
+// concat 方法把s1和s2拼接成一个新的切片，不会修改s1。
+func concat(s1 []byte, s2 ...byte) []byte {
+	r := make([]byte, len(s1)+len(s2))
+	copy(r, s1)
+	copy(r[len(s1):], s2)
+	return r
+}
@@ -0,0 +1,267 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/232425wxy/understanding-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type kv struct {
+	k, v string
+}
+
+// 下面的根哈希来自以太坊官方的前缀树测试向量（ethereum/tests 里的 trietest.json 和 trieanyorder.json）。
+var rootVectors = []struct {
+	name string
+	kvs  []kv
+	root string
+}{
+	{"dogs", []kv{{"doe", "reindeer"}, {"dog", "puppy"}, {"dogglesworth", "cat"}}, "8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3"},
+	{"puppy", []kv{{"do", "verb"}, {"horse", "stallion"}, {"doge", "coin"}, {"dog", "puppy"}}, "5991bb8c6514148a29db676a14ac506cd2cd5775ace63c30a4fe457715e9ac84"},
+	{"foo", []kv{{"foo", "bar"}, {"food", "bass"}}, "17beaa1648bafa633cda809c90c04af50fc8aed3cb40d16efbddee6fdf63c4c3"},
+	{"smallValues", []kv{{"be", "e"}, {"dog", "puppy"}, {"bed", "d"}}, "3f67c7a47520f79faa29255d2d3c084a7a6df0453116ed7232ff10277a8be68b"},
+	{"testy", []kv{{"test", "test"}, {"te", "testy"}}, "8452568af70d8d140f58d941338542f645fcca50094b20f3c3d8c3df49337928"},
+	{"hex", []kv{{"\x00\x45", "\x01\x23\x45\x67\x89"}, {"\x45\x00", "\x98\x76\x54\x32\x10"}}, "285505fcabe84badc8aa310e2aae17eddc7d120aabec8a476902c8184b3a3503"},
+	// 值为空的更新等同于删除
+	{"emptyValues", []kv{
+		{"do", "verb"}, {"ether", "wookiedoo"}, {"horse", "stallion"}, {"shaman", "horse"},
+		{"doge", "coin"}, {"ether", ""}, {"dog", "puppy"}, {"shaman", ""},
+	}, "5991bb8c6514148a29db676a14ac506cd2cd5775ace63c30a4fe457715e9ac84"},
+}
+
+func TestEmptyTrie(t *testing.T) {
+	tr := NewEmpty(NewMemoryDB())
+	assert.Equal(t, EmptyRoot, tr.Hash())
+	root, err := tr.Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, EmptyRoot, root)
+
+	val, err := tr.Get([]byte("missing"))
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	assert.Nil(t, tr.Delete([]byte("missing")))
+	assert.Equal(t, EmptyRoot, tr.Hash())
+}
+
+func TestRootVectors(t *testing.T) {
+	for _, test := range rootVectors {
+		tr := NewEmpty(NewMemoryDB())
+		for _, kv := range test.kvs {
+			assert.Nil(t, tr.Update([]byte(kv.k), []byte(kv.v)), test.name)
+		}
+		assert.Equal(t, test.root, common.Bytes2Hex(tr.Hash()), test.name)
+
+		// 插入顺序不影响根哈希（emptyValues 依赖顺序，跳过）
+		if test.name == "emptyValues" {
+			continue
+		}
+		rev := NewEmpty(NewMemoryDB())
+		for i := len(test.kvs) - 1; i >= 0; i-- {
+			assert.Nil(t, rev.Update([]byte(test.kvs[i].k), []byte(test.kvs[i].v)), test.name)
+		}
+		assert.Equal(t, test.root, common.Bytes2Hex(rev.Hash()), test.name)
+	}
+}
+
+func TestGetUpdateDelete(t *testing.T) {
+	tr := NewEmpty(NewMemoryDB())
+	for _, kv := range rootVectors[1].kvs {
+		assert.Nil(t, tr.Update([]byte(kv.k), []byte(kv.v)))
+	}
+	for _, kv := range rootVectors[1].kvs {
+		val, err := tr.Get([]byte(kv.k))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte(kv.v), val, kv.k)
+	}
+	for _, key := range []string{"d", "dogs", "horses", "x", ""} {
+		val, err := tr.Get([]byte(key))
+		assert.Nil(t, err)
+		assert.Nil(t, val, key)
+	}
+
+	// 覆盖已有的值
+	assert.Nil(t, tr.Update([]byte("dog"), []byte("hound")))
+	val, _ := tr.Get([]byte("dog"))
+	assert.Equal(t, []byte("hound"), val)
+
+	// 删除所有的键之后回到空树
+	for _, kv := range rootVectors[1].kvs {
+		assert.Nil(t, tr.Delete([]byte(kv.k)))
+		val, err := tr.Get([]byte(kv.k))
+		assert.Nil(t, err)
+		assert.Nil(t, val)
+	}
+	assert.Equal(t, EmptyRoot, tr.Hash())
+}
+
+func TestCommitAndReopen(t *testing.T) {
+	db := NewMemoryDB()
+	tr := NewEmpty(db)
+	keys := randomKeys(500)
+	for i, key := range keys {
+		assert.Nil(t, tr.Update(key, valueFor(i)))
+	}
+	root, err := tr.Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, root, tr.Hash())
+	assert.NotZero(t, db.Len())
+
+	reopened, err := New(root, db)
+	assert.Nil(t, err)
+	for i, key := range keys {
+		val, err := reopened.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, valueFor(i), val)
+	}
+	assert.Equal(t, root, reopened.Hash())
+
+	// 在重新打开的前缀树上继续修改，结果与在原来的前缀树上修改一样
+	for i := 0; i < len(keys); i += 2 {
+		assert.Nil(t, tr.Delete(keys[i]))
+		assert.Nil(t, reopened.Delete(keys[i]))
+	}
+	assert.Equal(t, tr.Hash(), reopened.Hash())
+	root2, err := reopened.Commit()
+	assert.Nil(t, err)
+	again, err := New(root2, db)
+	assert.Nil(t, err)
+	for i, key := range keys {
+		val, err := again.Get(key)
+		assert.Nil(t, err)
+		if i%2 == 0 {
+			assert.Nil(t, val)
+		} else {
+			assert.Equal(t, valueFor(i), val)
+		}
+	}
+}
+
+func TestMissingNode(t *testing.T) {
+	db := NewMemoryDB()
+	tr := NewEmpty(db)
+	keys := randomKeys(100)
+	for i, key := range keys {
+		tr.Update(key, valueFor(i))
+	}
+	root, err := tr.Commit()
+	assert.Nil(t, err)
+
+	_, err = New(common.Hex2Bytes("0102030405060708091011121314151617181920212223242526272829303132"), db)
+	var missing *MissingNodeError
+	assert.True(t, errors.As(err, &missing))
+
+	// 删除根节点以外的所有节点，访问任何一个键都会遇到找不到节点的错误
+	reopened, err := New(root, db)
+	assert.Nil(t, err)
+	for key := range db.db {
+		if key != string(root) {
+			db.Delete([]byte(key))
+		}
+	}
+	_, err = reopened.Get(keys[0])
+	assert.True(t, errors.As(err, &missing))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.As(reopened.Update(keys[0], []byte("x")), &missing))
+	assert.True(t, errors.As(reopened.Delete(keys[0]), &missing))
+}
+
+func TestRandomOrder(t *testing.T) {
+	keys := randomKeys(200)
+	tr := NewEmpty(NewMemoryDB())
+	for i, key := range keys {
+		tr.Update(key, valueFor(i))
+	}
+	want := tr.Hash()
+
+	rnd := rand.New(rand.NewSource(1))
+	for round := 0; round < 5; round++ {
+		perm := rnd.Perm(len(keys))
+		other := NewEmpty(NewMemoryDB())
+		// 先插入一些会被删除的键，确保删除之后的规整过程是正确的
+		for _, i := range perm[:50] {
+			other.Update(append(common.CopyBytes(keys[i]), 0xFF), []byte("garbage"))
+		}
+		for _, i := range perm {
+			other.Update(keys[i], valueFor(i))
+		}
+		for _, i := range perm[:50] {
+			other.Delete(append(common.CopyBytes(keys[i]), 0xFF))
+		}
+		assert.Equal(t, want, other.Hash(), "round %d", round)
+	}
+}
+
+func TestDecodeNode(t *testing.T) {
+	tr := NewEmpty(NewMemoryDB())
+	for _, kv := range rootVectors[0].kvs {
+		tr.Update([]byte(kv.k), []byte(kv.v))
+	}
+	db := NewMemoryDB()
+	tr.db = db
+	_, err := tr.Commit()
+	assert.Nil(t, err)
+	// 每个被存储的节点解码之后再编码，都应当得到原来的数据
+	for hash, enc := range db.db {
+		n, err := decodeNode([]byte(hash), enc)
+		assert.Nil(t, err)
+		collapsed, _ := hashNodeTree(stripHash(n), true)
+		assert.Equal(t, []byte(hash), []byte(collapsed.(hashNode)))
+	}
+
+	for _, input := range []string{"", "80", "c0", "c3010203", "c48083010203"} {
+		_, err := decodeNode(nil, common.Hex2Bytes(input))
+		assert.NotNil(t, err, input)
+	}
+}
+
+// stripHash 方法清除节点缓存的哈希值，迫使 hashNodeTree 重新计算。
+func stripHash(n node) node {
+	switch n := n.(type) {
+	case *shortNode:
+		c := n.copy()
+		c.flags = nodeFlag{}
+		return c
+	case *fullNode:
+		c := n.copy()
+		c.flags = nodeFlag{}
+		return c
+	}
+	return n
+}
+
+func randomKeys(n int) [][]byte {
+	rnd := rand.New(rand.NewSource(0))
+	keys := make([][]byte, n)
+	for i := range keys {
+		// 长度不同的键会让前缀树里同时出现分支节点、扩展节点和叶子节点
+		key := make([]byte, 1+rnd.Intn(32))
+		rnd.Read(key)
+		keys[i] = key
+	}
+	return dedupKeys(keys)
+}
+
+func dedupKeys(keys [][]byte) [][]byte {
+	seen := make(map[string]bool)
+	out := keys[:0]
+	for _, key := range keys {
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+func valueFor(i int) []byte {
+	// 一部分值很短，使得节点可以被内嵌在父节点里
+	if i%3 == 0 {
+		return []byte{byte(i) | 1}
+	}
+	return binary.BigEndian.AppendUint64(bytes.Repeat([]byte{0xAB}, i%40), uint64(i))
+}